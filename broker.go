@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
 )
@@ -34,6 +35,8 @@ func (c *Client) FetchBrokerRuntimeStats(ctx context.Context, brokerAddr string)
 }
 
 // GetBrokerConfig 获取 Broker 配置
+// Broker 以 Java Properties 格式返回配置，解析为 key/value；早期版本将无法解析为 JSON 的
+// 整段文本放在 "raw" 中，现已改为逐项解析
 func (c *Client) GetBrokerConfig(ctx context.Context, brokerAddr string) (map[string]string, error) {
 	cmd := remoting.NewRequest(remoting.GetBrokerConfig, nil)
 
@@ -46,19 +49,32 @@ func (c *Client) GetBrokerConfig(ctx context.Context, brokerAddr string) (map[st
 		return nil, NewAdminError(resp.Code, resp.Remark)
 	}
 
-	// Broker 配置以 Properties 格式返回
+	// Broker 配置以 Properties 格式返回，兼容 JSON 格式
 	config := make(map[string]string)
 	if err := json.Unmarshal(resp.Body, &config); err != nil {
-		// 如果 JSON 解析失败，尝试解析为字符串
-		configStr := string(resp.Body)
-		if configStr != "" {
-			config["raw"] = configStr
-		}
+		config = parseProperties(resp.Body)
 	}
 
 	return config, nil
 }
 
+// parseProperties 解析 Java Properties 格式（key=value，# 开头为注释）
+func parseProperties(body []byte) map[string]string {
+	config := make(map[string]string)
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			key, value, _ = strings.Cut(line, ":")
+		}
+		config[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return config
+}
+
 // UpdateBrokerConfig 更新 Broker 配置
 func (c *Client) UpdateBrokerConfig(ctx context.Context, brokerAddr string, properties map[string]string) error {
 	extFields := make(map[string]string)
@@ -2,6 +2,9 @@ package admin
 
 import (
 	"testing"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
 )
 
 // =============================================================================
@@ -387,3 +390,39 @@ func TestIntegration_RemoveBrokerFromContainer(t *testing.T) {
 	skipIfNoRocketMQ(t)
 	t.Skip("跳过 RemoveBrokerFromContainer 测试：需要 Broker 容器环境")
 }
+
+// =============================================================================
+// Broker 管理接口单元测试
+// =============================================================================
+
+// TestGetBrokerConfig 测试 Properties 与 JSON 格式的 Broker、NameServer 配置解析
+func TestGetBrokerConfig(t *testing.T) {
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+	properties := []byte("#Broker 配置\nbrokerName=broker-a\nnamesrvAddr=a:9876;b:9876\n")
+	srv.Handle(remoting.GetBrokerConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success(properties)
+	})
+	srv.Handle(remoting.GetNamesrvConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"orderMessageEnable":"false"}`))
+	})
+	client := newFakeClient(t, srv)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	config, err := client.GetBrokerConfig(ctx, srv.Addr)
+	if err != nil {
+		t.Fatalf("获取 Broker 配置失败: %v", err)
+	}
+	if len(config) != 2 || config["brokerName"] != "broker-a" || config["namesrvAddr"] != "a:9876;b:9876" {
+		t.Errorf("应逐项解析 Properties 而不是放在 raw 中: %v", config)
+	}
+
+	config, err = client.GetNameServerConfig(ctx)
+	if err != nil {
+		t.Fatalf("获取 NameServer 配置失败: %v", err)
+	}
+	if len(config) != 1 || config["orderMessageEnable"] != "false" {
+		t.Errorf("JSON 格式配置解析错误: %v", config)
+	}
+}
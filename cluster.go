@@ -62,6 +62,7 @@ func (c *Client) UpdateNameServerConfig(ctx context.Context, properties map[stri
 }
 
 // GetNameServerConfig 获取 NameServer 配置
+// 与 GetBrokerConfig 相同，Properties 格式的配置逐项解析，不再整段放在 "raw" 中
 func (c *Client) GetNameServerConfig(ctx context.Context) (map[string]string, error) {
 	cmd := remoting.NewRequest(remoting.GetNamesrvConfig, nil)
 
@@ -74,12 +75,10 @@ func (c *Client) GetNameServerConfig(ctx context.Context) (map[string]string, er
 		return nil, NewAdminError(resp.Code, resp.Remark)
 	}
 
+	// NameServer 配置以 Properties 格式返回，兼容 JSON 格式
 	config := make(map[string]string)
 	if err := json.Unmarshal(resp.Body, &config); err != nil {
-		// 尝试作为字符串处理
-		if len(resp.Body) > 0 {
-			config["raw"] = string(resp.Body)
-		}
+		config = parseProperties(resp.Body)
 	}
 
 	return config, nil
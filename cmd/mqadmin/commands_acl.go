@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+	"github.com/codermast/rocketmq-admin-go/internal/cliutil"
+)
+
+// =============================================================================
+// ACL 命令（RocketMQ 5.x）
+// =============================================================================
+
+func init() {
+	registerCommands(
+		&command{name: "createUser", usage: "创建 ACL 用户", setup: setupUpsertUser(true)},
+		&command{name: "updateUser", usage: "更新 ACL 用户", setup: setupUpsertUser(false)},
+		&command{name: "deleteUser", usage: "删除 ACL 用户", setup: setupDeleteUser},
+		&command{name: "getUser", usage: "查看 ACL 用户", setup: setupGetUser},
+		&command{name: "listUser", usage: "查看 ACL 用户列表", setup: setupListUser},
+		&command{name: "createAcl", usage: "创建 ACL 授权", setup: setupUpsertAcl(true)},
+		&command{name: "updateAcl", usage: "更新 ACL 授权", setup: setupUpsertAcl(false)},
+		&command{name: "deleteAcl", usage: "删除 ACL 授权", setup: setupDeleteAcl},
+		&command{name: "getAcl", usage: "查看 ACL 授权", setup: setupGetAcl},
+		&command{name: "listAcl", usage: "查看 ACL 授权列表", setup: setupListAcl},
+	)
+}
+
+// eachBroker 在每个目标 Broker 上执行操作，任一失败即返回
+func eachBroker(ctx context.Context, e *env, target *brokerTarget, fn func(addr string) error) (int, error) {
+	brokers, err := target.resolve(ctx, e.client)
+	if err != nil {
+		return 0, err
+	}
+	for _, addr := range sortedKeys(brokers) {
+		if err := fn(addr); err != nil {
+			return 0, fmt.Errorf("Broker %s: %w", addr, err)
+		}
+	}
+	return len(brokers), nil
+}
+
+// setupUpsertUser createUser/updateUser -b addr | -c cluster -u username [-p password] [-t userType] [-s userStatus]
+func setupUpsertUser(create bool) func(fs *flag.FlagSet) runFunc {
+	return func(fs *flag.FlagSet) runFunc {
+		var target brokerTarget
+		target.register(fs)
+		username := fs.String("u", "", "用户名")
+		password := fs.String("p", "", "密码")
+		userType := fs.String("t", "", "用户类型（Super、Normal）")
+		userStatus := fs.String("s", "", "用户状态（enable、disable）")
+
+		return func(ctx context.Context, e *env) error {
+			if err := required(map[string]string{"u": *username}); err != nil {
+				return err
+			}
+			user := admin.UserInfo{
+				Username:   *username,
+				Password:   *password,
+				UserType:   *userType,
+				UserStatus: *userStatus,
+			}
+
+			n, err := eachBroker(ctx, e, &target, func(addr string) error {
+				if create {
+					return e.client.CreateUser(ctx, addr, user)
+				}
+				return e.client.UpdateUser(ctx, addr, user)
+			})
+			if err != nil {
+				return err
+			}
+			action := "更新"
+			if create {
+				action = "创建"
+			}
+			return e.out.message("在 %d 个 Broker 上%s用户 %s 成功", n, action, *username)
+		}
+	}
+}
+
+// setupDeleteUser deleteUser -b addr | -c cluster -u username
+func setupDeleteUser(fs *flag.FlagSet) runFunc {
+	var target brokerTarget
+	target.register(fs)
+	username := fs.String("u", "", "用户名")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"u": *username}); err != nil {
+			return err
+		}
+		n, err := eachBroker(ctx, e, &target, func(addr string) error {
+			return e.client.DeleteUser(ctx, addr, *username)
+		})
+		if err != nil {
+			return err
+		}
+		return e.out.message("在 %d 个 Broker 上删除用户 %s 成功", n, *username)
+	}
+}
+
+// setupGetUser getUser -b addr | -c cluster -u username
+func setupGetUser(fs *flag.FlagSet) runFunc {
+	var target brokerTarget
+	target.register(fs)
+	username := fs.String("u", "", "用户名")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"u": *username}); err != nil {
+			return err
+		}
+		users := make(map[string]*admin.UserInfo)
+		if _, err := eachBroker(ctx, e, &target, func(addr string) error {
+			user, err := e.client.GetUser(ctx, addr, *username)
+			users[addr] = user
+			return err
+		}); err != nil {
+			return err
+		}
+		return printUsers(e, users)
+	}
+}
+
+// setupListUser listUser -b addr | -c cluster
+func setupListUser(fs *flag.FlagSet) runFunc {
+	var target brokerTarget
+	target.register(fs)
+
+	return func(ctx context.Context, e *env) error {
+		users := make(map[string][]admin.UserInfo)
+		if _, err := eachBroker(ctx, e, &target, func(addr string) error {
+			list, err := e.client.ListUser(ctx, addr)
+			if err != nil {
+				return err
+			}
+			users[addr] = list.Users
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		var rows [][]string
+		for _, addr := range sortedKeys(users) {
+			for _, u := range users[addr] {
+				rows = append(rows, userRow(addr, &u))
+			}
+		}
+		return e.out.table(users, userHeaders, rows)
+	}
+}
+
+var userHeaders = []string{"BROKER", "USERNAME", "TYPE", "STATUS"}
+
+// userRow 用户表格行
+func userRow(addr string, u *admin.UserInfo) []string {
+	return []string{addr, u.Username, u.UserType, u.UserStatus}
+}
+
+// printUsers 按 Broker 输出用户
+func printUsers(e *env, users map[string]*admin.UserInfo) error {
+	var rows [][]string
+	for _, addr := range sortedKeys(users) {
+		if u := users[addr]; u != nil {
+			rows = append(rows, userRow(addr, u))
+		}
+	}
+	return e.out.table(users, userHeaders, rows)
+}
+
+// setupUpsertAcl createAcl/updateAcl -b addr | -c cluster -s subject -r resources -a actions [-d decision] [-i sourceIps]
+func setupUpsertAcl(create bool) func(fs *flag.FlagSet) runFunc {
+	return func(fs *flag.FlagSet) runFunc {
+		var target brokerTarget
+		target.register(fs)
+		subject := fs.String("s", "", "授权主体，如 User:alice")
+		resources := fs.String("r", "", "资源列表，逗号分隔，如 Topic:orders,Group:cg")
+		actions := fs.String("a", "", "操作列表，逗号分隔，如 Pub,Sub")
+		decision := fs.String("d", "Allow", "决策（Allow、Deny）")
+		sourceIps := fs.String("i", "", "来源 IP 列表，逗号分隔")
+
+		return func(ctx context.Context, e *env) error {
+			if err := required(map[string]string{"s": *subject, "r": *resources, "a": *actions}); err != nil {
+				return err
+			}
+			acl := buildAclInfo(*subject, cliutil.SplitList(*resources, ","), cliutil.SplitList(*actions, ","), *decision, cliutil.SplitList(*sourceIps, ","))
+
+			n, err := eachBroker(ctx, e, &target, func(addr string) error {
+				if create {
+					return e.client.CreateAcl(ctx, addr, acl)
+				}
+				return e.client.UpdateAcl(ctx, addr, acl)
+			})
+			if err != nil {
+				return err
+			}
+			action := "更新"
+			if create {
+				action = "创建"
+			}
+			return e.out.message("在 %d 个 Broker 上%s %s 的授权成功", n, action, *subject)
+		}
+	}
+}
+
+// buildAclInfo 为每个资源生成一条策略
+func buildAclInfo(subject string, resources, actions []string, decision string, sourceIps []string) admin.AclInfo {
+	acl := admin.AclInfo{Subject: subject}
+	for _, resource := range resources {
+		acl.Policies = append(acl.Policies, admin.AclPolicy{
+			Resource:  resource,
+			Actions:   actions,
+			SourceIPs: sourceIps,
+			Decision:  decision,
+		})
+	}
+	return acl
+}
+
+// setupDeleteAcl deleteAcl -b addr | -c cluster -s subject
+func setupDeleteAcl(fs *flag.FlagSet) runFunc {
+	var target brokerTarget
+	target.register(fs)
+	subject := fs.String("s", "", "授权主体")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"s": *subject}); err != nil {
+			return err
+		}
+		n, err := eachBroker(ctx, e, &target, func(addr string) error {
+			return e.client.DeleteAcl(ctx, addr, *subject)
+		})
+		if err != nil {
+			return err
+		}
+		return e.out.message("在 %d 个 Broker 上删除 %s 的授权成功", n, *subject)
+	}
+}
+
+// setupGetAcl getAcl -b addr | -c cluster -s subject
+func setupGetAcl(fs *flag.FlagSet) runFunc {
+	var target brokerTarget
+	target.register(fs)
+	subject := fs.String("s", "", "授权主体")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"s": *subject}); err != nil {
+			return err
+		}
+		acls := make(map[string][]admin.AclInfo)
+		if _, err := eachBroker(ctx, e, &target, func(addr string) error {
+			acl, err := e.client.GetAcl(ctx, addr, *subject)
+			if err != nil {
+				return err
+			}
+			if acl != nil {
+				acls[addr] = []admin.AclInfo{*acl}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		return printAcls(e, acls)
+	}
+}
+
+// setupListAcl listAcl -b addr | -c cluster
+func setupListAcl(fs *flag.FlagSet) runFunc {
+	var target brokerTarget
+	target.register(fs)
+
+	return func(ctx context.Context, e *env) error {
+		acls := make(map[string][]admin.AclInfo)
+		if _, err := eachBroker(ctx, e, &target, func(addr string) error {
+			list, err := e.client.ListAcl(ctx, addr)
+			if err != nil {
+				return err
+			}
+			acls[addr] = list.Acls
+			return nil
+		}); err != nil {
+			return err
+		}
+		return printAcls(e, acls)
+	}
+}
+
+// printAcls 按 Broker 输出授权，每条策略一行
+func printAcls(e *env, acls map[string][]admin.AclInfo) error {
+	var rows [][]string
+	for _, addr := range sortedKeys(acls) {
+		for _, acl := range acls[addr] {
+			for _, p := range acl.Policies {
+				decision := p.Decision
+				if decision == "" {
+					decision = p.Effect
+				}
+				rows = append(rows, []string{
+					addr,
+					acl.Subject,
+					p.Resource,
+					strings.Join(p.Actions, ","),
+					decision,
+					strings.Join(p.SourceIPs, ","),
+				})
+			}
+		}
+	}
+	return e.out.table(acls, []string{"BROKER", "SUBJECT", "RESOURCE", "ACTIONS", "DECISION", "SOURCE IPS"}, rows)
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+)
+
+// =============================================================================
+// 集群与 Broker 命令
+// =============================================================================
+
+func init() {
+	registerCommands(
+		&command{name: "clusterList", usage: "查看集群 Broker 列表", setup: setupClusterList},
+		&command{name: "brokerStatus", usage: "查看 Broker 运行时统计", setup: setupBrokerStatus},
+		&command{name: "getBrokerConfig", usage: "查看 Broker 配置", setup: setupGetBrokerConfig},
+		&command{name: "updateBrokerConfig", usage: "更新 Broker 配置", setup: setupUpdateBrokerConfig},
+	)
+}
+
+// brokerTarget 表示命令作用的 Broker：指定地址或集群内全部 Master
+type brokerTarget struct {
+	brokerAddr  string
+	clusterName string
+}
+
+// register 注册 -b / -c 参数
+func (t *brokerTarget) register(fs *flag.FlagSet) {
+	fs.StringVar(&t.brokerAddr, "b", "", "Broker 地址")
+	fs.StringVar(&t.clusterName, "c", "", "集群名称（作用于集群内全部 Master）")
+}
+
+// resolve 返回 Broker 地址列表，key 为 Broker 地址，value 为 Broker 名称（直接指定地址时为地址本身）
+func (t *brokerTarget) resolve(ctx context.Context, client *admin.Client) (map[string]string, error) {
+	if t.brokerAddr == "" && t.clusterName == "" {
+		return nil, newUsageError("-b 和 -c 必须指定一个")
+	}
+	if t.brokerAddr != "" {
+		return map[string]string{t.brokerAddr: t.brokerAddr}, nil
+	}
+	return clusterMasters(ctx, client, t.clusterName)
+}
+
+// clusterMasters 返回集群内全部 Master 地址，key 为地址，value 为 Broker 名称
+func clusterMasters(ctx context.Context, client *admin.Client, clusterName string) (map[string]string, error) {
+	clusterInfo, err := client.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	brokerNames, ok := clusterInfo.ClusterAddrTable[clusterName]
+	if !ok {
+		return nil, fmt.Errorf("集群 %s 不存在", clusterName)
+	}
+
+	masters := make(map[string]string)
+	for _, brokerName := range brokerNames {
+		brokerData, ok := clusterInfo.BrokerAddrTable[brokerName]
+		if !ok {
+			continue
+		}
+		if addr, ok := brokerData.BrokerAddrs["0"]; ok {
+			masters[addr] = brokerName
+		}
+	}
+	if len(masters) == 0 {
+		return nil, fmt.Errorf("集群 %s 没有可用的 Master", clusterName)
+	}
+	return masters, nil
+}
+
+// setupClusterList clusterList [-c cluster]
+func setupClusterList(fs *flag.FlagSet) runFunc {
+	clusterName := fs.String("c", "", "只显示指定集群")
+
+	return func(ctx context.Context, e *env) error {
+		clusterInfo, err := e.client.ExamineBrokerClusterInfo(ctx)
+		if err != nil {
+			return err
+		}
+
+		type brokerRow struct {
+			Cluster    string `json:"cluster"`
+			BrokerName string `json:"brokerName"`
+			BrokerId   string `json:"brokerId"`
+			Addr       string `json:"addr"`
+		}
+		var brokers []brokerRow
+		for _, cluster := range sortedKeys(clusterInfo.ClusterAddrTable) {
+			if *clusterName != "" && cluster != *clusterName {
+				continue
+			}
+			brokerNames := append([]string(nil), clusterInfo.ClusterAddrTable[cluster]...)
+			sort.Strings(brokerNames)
+			for _, brokerName := range brokerNames {
+				brokerData, ok := clusterInfo.BrokerAddrTable[brokerName]
+				if !ok {
+					continue
+				}
+				for _, id := range sortedKeys(brokerData.BrokerAddrs) {
+					brokers = append(brokers, brokerRow{
+						Cluster:    cluster,
+						BrokerName: brokerName,
+						BrokerId:   id,
+						Addr:       brokerData.BrokerAddrs[id],
+					})
+				}
+			}
+		}
+
+		rows := make([][]string, 0, len(brokers))
+		for _, b := range brokers {
+			rows = append(rows, []string{b.Cluster, b.BrokerName, b.BrokerId, b.Addr})
+		}
+		return e.out.table(brokers, []string{"CLUSTER", "BROKER", "BID", "ADDR"}, rows)
+	}
+}
+
+// setupBrokerStatus brokerStatus -b addr | -c cluster
+func setupBrokerStatus(fs *flag.FlagSet) runFunc {
+	var target brokerTarget
+	target.register(fs)
+
+	return func(ctx context.Context, e *env) error {
+		brokers, err := target.resolve(ctx, e.client)
+		if err != nil {
+			return err
+		}
+
+		if len(brokers) == 1 {
+			for addr := range brokers {
+				stats, err := e.client.FetchBrokerRuntimeStats(ctx, addr)
+				if err != nil {
+					return err
+				}
+				return e.out.kv(stats.Table, stats.Table)
+			}
+		}
+
+		result := make(map[string]map[string]string)
+		var rows [][]string
+		for _, addr := range sortedKeys(brokers) {
+			stats, err := e.client.FetchBrokerRuntimeStats(ctx, addr)
+			if err != nil {
+				return fmt.Errorf("查询 Broker %s 失败: %w", addr, err)
+			}
+			result[addr] = stats.Table
+			for _, k := range sortedKeys(stats.Table) {
+				rows = append(rows, []string{brokers[addr], addr, k, stats.Table[k]})
+			}
+		}
+		return e.out.table(result, []string{"BROKER", "ADDR", "KEY", "VALUE"}, rows)
+	}
+}
+
+// setupGetBrokerConfig getBrokerConfig -b addr | -c cluster [-k keyPrefix]
+func setupGetBrokerConfig(fs *flag.FlagSet) runFunc {
+	var target brokerTarget
+	target.register(fs)
+	key := fs.String("k", "", "只显示包含该字符串的配置项")
+
+	return func(ctx context.Context, e *env) error {
+		brokers, err := target.resolve(ctx, e.client)
+		if err != nil {
+			return err
+		}
+
+		result := make(map[string]map[string]string)
+		var rows [][]string
+		for _, addr := range sortedKeys(brokers) {
+			config, err := e.client.GetBrokerConfig(ctx, addr)
+			if err != nil {
+				return fmt.Errorf("查询 Broker %s 配置失败: %w", addr, err)
+			}
+			filtered := make(map[string]string)
+			for k, v := range config {
+				if *key == "" || strings.Contains(k, *key) {
+					filtered[k] = v
+				}
+			}
+			result[addr] = filtered
+			for _, k := range sortedKeys(filtered) {
+				rows = append(rows, []string{addr, k, filtered[k]})
+			}
+		}
+
+		if len(result) == 1 {
+			for _, config := range result {
+				return e.out.kv(config, config)
+			}
+		}
+		return e.out.table(result, []string{"ADDR", "KEY", "VALUE"}, rows)
+	}
+}
+
+// setupUpdateBrokerConfig updateBrokerConfig -b addr | -c cluster -k key -v value
+func setupUpdateBrokerConfig(fs *flag.FlagSet) runFunc {
+	var target brokerTarget
+	target.register(fs)
+	key := fs.String("k", "", "配置项名称")
+	value := fs.String("v", "", "配置项值")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"k": *key, "v": *value}); err != nil {
+			return err
+		}
+		brokers, err := target.resolve(ctx, e.client)
+		if err != nil {
+			return err
+		}
+
+		for _, addr := range sortedKeys(brokers) {
+			if err := e.client.UpdateBrokerConfig(ctx, addr, map[string]string{*key: *value}); err != nil {
+				return fmt.Errorf("更新 Broker %s 配置失败: %w", addr, err)
+			}
+		}
+		return e.out.message("更新 %d 个 Broker 配置成功: %s=%s", len(brokers), *key, *value)
+	}
+}
@@ -0,0 +1,571 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+	"github.com/codermast/rocketmq-admin-go/internal/cliutil"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+)
+
+// =============================================================================
+// 消费者命令
+// =============================================================================
+
+func init() {
+	registerCommands(
+		&command{name: "consumerProgress", usage: "查看消费进度与堆积", setup: setupConsumerProgress},
+		&command{name: "consumerConnection", usage: "查看消费者连接", setup: setupConsumerConnection},
+		&command{name: "resetOffsetByTime", usage: "按时间重置消费位点", setup: setupResetOffsetByTime},
+		&command{name: "cloneGroupOffset", usage: "复制消费组的消费进度", setup: setupCloneGroupOffset},
+		&command{name: "resetOffset", usage: "按计划重置消费位点（时间、Offset、min/max、跳过 N 条）", setup: setupResetOffset},
+		&command{name: "backupOffset", usage: "备份消费组的消费进度到文件", setup: setupBackupOffset},
+		&command{name: "restoreOffset", usage: "从文件恢复消费组的消费进度", setup: setupRestoreOffset},
+		&command{name: "skipAccumulatedMessage", usage: "跳过消费组的全部堆积，从最新位置开始消费", setup: setupSkipAccumulatedMessage},
+		&command{name: "updateSubGroup", usage: "在集群全部 Master 上创建或更新订阅组并校验", setup: setupUpdateSubGroup},
+		&command{name: "deleteSubGroup", usage: "在集群全部 Broker 上删除订阅组", setup: setupDeleteSubGroup},
+		&command{name: "describeGroup", usage: "汇总订阅组在各 Broker 上的配置并检查一致性", setup: setupDescribeGroup},
+		&command{name: "checkOffset", usage: "检查越界、未提交或不在路由中的消费进度，可按建议修复", setup: setupCheckOffset},
+		&command{name: "pauseGroup", usage: "在集群全部 Master 上暂停订阅组消费", setup: setupPauseGroup(true)},
+		&command{name: "resumeGroup", usage: "在集群全部 Master 上恢复订阅组消费", setup: setupPauseGroup(false)},
+		&command{name: "groupPauseState", usage: "查看订阅组在各 Master 上的暂停状态", setup: setupGroupPauseState},
+	)
+}
+
+// setupConsumerProgress consumerProgress [-g group] [-t topic]
+// 指定消费组时输出各队列明细，否则输出全部消费组的汇总
+func setupConsumerProgress(fs *flag.FlagSet) runFunc {
+	group := fs.String("g", "", "消费组，为空时汇总全部消费组")
+	topic := fs.String("t", "", "只统计指定 Topic")
+
+	return func(ctx context.Context, e *env) error {
+		opts := admin.LagOptions{Topic: *topic}
+
+		if *group == "" {
+			groups, err := e.client.ListConsumerGroups(ctx)
+			if err != nil {
+				return err
+			}
+			lags, err := e.client.ExamineConsumerLagList(ctx, groups, opts)
+			if err != nil {
+				return err
+			}
+			rows := make([][]string, 0, len(lags))
+			for _, lag := range lags {
+				rows = append(rows, []string{
+					lag.ConsumerGroup,
+					strconv.Itoa(lag.OnlineClients),
+					strconv.FormatFloat(lag.ConsumeTps, 'f', 2, 64),
+					strconv.FormatInt(lag.Lag, 10),
+					formatDuration(lag.MaxTimeLag),
+				})
+			}
+			return e.out.table(lags, []string{"GROUP", "CLIENTS", "TPS", "DIFF", "MAX TIME LAG"}, rows)
+		}
+
+		opts.ResolveClients = true
+		lag, err := e.client.ExamineConsumerLag(ctx, *group, opts)
+		if err != nil {
+			return err
+		}
+		var rows [][]string
+		for _, topicLag := range lag.Topics {
+			for _, q := range topicLag.Queues {
+				rows = append(rows, []string{
+					q.MessageQueue.Topic,
+					q.MessageQueue.BrokerName,
+					strconv.Itoa(q.MessageQueue.QueueId),
+					strconv.FormatInt(q.BrokerOffset, 10),
+					strconv.FormatInt(q.ConsumerOffset, 10),
+					strconv.FormatInt(q.Lag, 10),
+					q.ClientId,
+					formatTimestamp(q.LastTimestamp),
+				})
+			}
+		}
+		return e.out.table(lag, []string{"TOPIC", "BROKER", "QID", "BROKER OFFSET", "CONSUMER OFFSET", "DIFF", "CLIENT", "LAST TIME"}, rows)
+	}
+}
+
+// setupConsumerConnection consumerConnection -g group
+func setupConsumerConnection(fs *flag.FlagSet) runFunc {
+	group := fs.String("g", "", "消费组")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"g": *group}); err != nil {
+			return err
+		}
+		conn, err := e.client.ExamineConsumerConnectionInfo(ctx, *group)
+		if err != nil {
+			return err
+		}
+
+		connections := append([]*admin.Connection(nil), conn.ConnectionSet...)
+		sort.Slice(connections, func(i, j int) bool { return connections[i].ClientId < connections[j].ClientId })
+		rows := make([][]string, 0, len(connections))
+		for _, c := range connections {
+			rows = append(rows, []string{c.ClientId, c.ClientAddr, c.Language, strconv.Itoa(c.Version)})
+		}
+		return e.out.table(conn, []string{"CLIENT ID", "CLIENT ADDR", "LANGUAGE", "VERSION"}, rows)
+	}
+}
+
+// setupResetOffsetByTime resetOffsetByTime -g group -t topic -s timestamp [-f]
+func setupResetOffsetByTime(fs *flag.FlagSet) runFunc {
+	group := fs.String("g", "", "消费组")
+	topic := fs.String("t", "", "Topic 名称")
+	timestamp := fs.String("s", "", "时间：now、毫秒时间戳或 yyyy-MM-dd#HH:mm:ss:SSS")
+	force := fs.Bool("f", true, "是否强制重置（允许回退位点）")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"g": *group, "t": *topic, "s": *timestamp}); err != nil {
+			return err
+		}
+		ts, err := parseTimestamp(*timestamp)
+		if err != nil {
+			return err
+		}
+
+		// 消费组不在线时由 Broker 重置会失败，改为直接改写已提交的 Offset
+		results, resetErr := e.client.ResetOffsetByTimestamp(ctx, *topic, *group, ts, *force)
+		if admin.IsResponseCode(resetErr, remoting.ConsumerNotOnline) {
+			results, resetErr = e.client.ResetOffsetByTimestampOld(ctx, *group, *topic, ts, *force)
+		}
+		if results != nil {
+			if err := printQueueOffsetResults(e, results); err != nil {
+				return err
+			}
+		}
+		return resetErr
+	}
+}
+
+// setupCloneGroupOffset cloneGroupOffset -s srcGroup -d destGroup [-t topic] [-o] [-force]
+func setupCloneGroupOffset(fs *flag.FlagSet) runFunc {
+	srcGroup := fs.String("s", "", "源消费组")
+	destGroup := fs.String("d", "", "目标消费组")
+	topic := fs.String("t", "", "Topic 名称，为空时复制全部 Topic")
+	offline := fs.Bool("o", false, "源消费组已下线，按已提交的 Offset 复制")
+	force := fs.Bool("force", false, "目标消费组在线时仍然执行")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"s": *srcGroup, "d": *destGroup}); err != nil {
+			return err
+		}
+		results, cloneErr := e.client.CloneGroupOffset(ctx, *srcGroup, *destGroup, *topic, *offline, *force)
+		if results != nil {
+			if err := printQueueOffsetResults(e, results); err != nil {
+				return err
+			}
+		}
+		return cloneErr
+	}
+}
+
+// setupResetOffset resetOffset -g group -to target [-t topics] [-dry-run]
+func setupResetOffset(fs *flag.FlagSet) runFunc {
+	group := fs.String("g", "", "消费组")
+	topics := fs.String("t", "", "Topic 名称，逗号分隔，为空时为消费组的全部 Topic")
+	to := fs.String("to", "", "重置目标: timestamp=ms、offset=n、min、max、skip=n")
+	dryRun := fs.Bool("dry-run", false, "只输出重置计划，不修改集群")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"g": *group, "to": *to}); err != nil {
+			return err
+		}
+		target, err := admin.ParseResetTarget(*to)
+		if err != nil {
+			return newUsageError("%v", err)
+		}
+
+		plan, err := e.client.PlanOffsetReset(ctx, *group, cliutil.SplitList(*topics, ","), target)
+		if err != nil {
+			return err
+		}
+		if *dryRun {
+			rows := make([][]string, 0, len(plan.Queues))
+			for _, q := range plan.Queues {
+				rows = append(rows, []string{q.MessageQueue.Topic, q.MessageQueue.BrokerName, strconv.Itoa(q.MessageQueue.QueueId),
+					fmt.Sprintf("[%d,%d]", q.MinOffset, q.MaxOffset), strconv.FormatInt(q.CurrentOffset, 10), strconv.FormatInt(q.TargetOffset, 10),
+					strconv.FormatInt(q.Skipped, 10), strconv.FormatInt(q.Reconsumed, 10)})
+			}
+			return e.out.table(plan, []string{"TOPIC", "BROKER", "QID", "RANGE", "CURRENT", "TARGET", "SKIP", "RECONSUME"}, rows)
+		}
+
+		results, resetErr := e.client.ExecuteOffsetReset(ctx, plan)
+		if results != nil {
+			if err := printQueueOffsetResults(e, results); err != nil {
+				return err
+			}
+		}
+		return resetErr
+	}
+}
+
+// setupBackupOffset backupOffset -g group -f file
+func setupBackupOffset(fs *flag.FlagSet) runFunc {
+	group := fs.String("g", "", "消费组")
+	file := fs.String("f", "", "备份文件路径")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"g": *group, "f": *file}); err != nil {
+			return err
+		}
+		backup, err := e.client.BackupConsumerOffsets(ctx, *group)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		if err := admin.WriteOffsetBackup(f, backup); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return e.out.message("备份消费组 %s 的 %d 个队列到 %s", *group, len(backup.Queues), *file)
+	}
+}
+
+// setupRestoreOffset restoreOffset -f file [-g group] [-t topics] [-force] [-dry-run]
+func setupRestoreOffset(fs *flag.FlagSet) runFunc {
+	file := fs.String("f", "", "备份文件路径")
+	group := fs.String("g", "", "恢复到的消费组，为空时使用备份中的消费组")
+	topics := fs.String("t", "", "只恢复指定 Topic，逗号分隔")
+	force := fs.Bool("force", false, "消费组在线时仍然恢复")
+	dryRun := fs.Bool("dry-run", false, "只校验并输出报告，不修改集群")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"f": *file}); err != nil {
+			return err
+		}
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		backup, err := admin.ReadOffsetBackup(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+		report, restoreErr := e.client.RestoreConsumerOffsets(ctx, backup, admin.RestoreOffsetOptions{
+			Group:  *group,
+			Topics: cliutil.SplitList(*topics, ","),
+			Force:  *force,
+			DryRun: *dryRun,
+		})
+		if report != nil {
+			rows := make([][]string, 0, len(report.Items))
+			for _, item := range report.Items {
+				mq := item.MessageQueue
+				rows = append(rows, []string{mq.Topic, mq.BrokerName, strconv.Itoa(mq.QueueId), strconv.FormatInt(item.BackupOffset, 10),
+					strconv.FormatInt(item.CurrentOffset, 10), strconv.FormatInt(item.RestoredOffset, 10), string(item.Status), item.Reason})
+			}
+			if err := e.out.table(report, []string{"TOPIC", "BROKER", "QID", "BACKUP", "CURRENT", "RESTORED", "STATUS", "REASON"}, rows); err != nil {
+				return err
+			}
+		}
+		return restoreErr
+	}
+}
+
+// setupUpdateSubGroup updateSubGroup -c cluster -g group [-r 16] [-q 1] [-consume=false] [-broadcast=false]
+func setupUpdateSubGroup(fs *flag.FlagSet) runFunc {
+	clusterName := fs.String("c", "", "集群名称")
+	group := fs.String("g", "", "消费组")
+	retryMaxTimes := fs.Int("r", 16, "最大重试次数")
+	retryQueueNums := fs.Int("q", 1, "重试队列数量")
+	consumeEnable := fs.Bool("consume", true, "是否允许消费")
+	broadcast := fs.Bool("broadcast", true, "是否允许广播消费")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"c": *clusterName, "g": *group}); err != nil {
+			return err
+		}
+		config := admin.SubscriptionGroupConfig{
+			GroupName:                      *group,
+			ConsumeEnable:                  *consumeEnable,
+			ConsumeFromMinEnable:           true,
+			ConsumeBroadcastEnable:         *broadcast,
+			RetryQueueNums:                 *retryQueueNums,
+			RetryMaxTimes:                  *retryMaxTimes,
+			WhichBrokerWhenConsumeSlowly:   1,
+			NotifyConsumerIdsChangedEnable: true,
+		}
+		results, err := e.client.CreateSubscriptionGroupInCluster(ctx, *clusterName, config)
+		return printGroupResults(e, results, err)
+	}
+}
+
+// setupDeleteSubGroup deleteSubGroup -c cluster -g group [-clean-offset]
+func setupDeleteSubGroup(fs *flag.FlagSet) runFunc {
+	clusterName := fs.String("c", "", "集群名称")
+	group := fs.String("g", "", "消费组")
+	cleanOffset := fs.Bool("clean-offset", false, "同时删除消费进度")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"c": *clusterName, "g": *group}); err != nil {
+			return err
+		}
+		results, err := e.client.DeleteSubscriptionGroupInCluster(ctx, *clusterName, *group, *cleanOffset)
+		return printGroupResults(e, results, err)
+	}
+}
+
+// setupDescribeGroup describeGroup -g group [-c cluster]
+func setupDescribeGroup(fs *flag.FlagSet) runFunc {
+	clusterName := fs.String("c", "", "集群名称，为空时检查全部集群")
+	group := fs.String("g", "", "消费组")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"g": *group}); err != nil {
+			return err
+		}
+		desc, err := e.client.DescribeSubscriptionGroup(ctx, *clusterName, *group)
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, 0, len(desc.Brokers))
+		for _, b := range desc.Brokers {
+			status := "一致"
+			switch {
+			case b.Error != "":
+				status = b.Error
+			case b.Missing:
+				status = "缺失"
+			case len(b.Diff) > 0:
+				diffs := make([]string, len(b.Diff))
+				for i, d := range b.Diff {
+					diffs[i] = fmt.Sprintf("%s=%s（多数为 %s）", d.Field, d.New, d.Old)
+				}
+				status = strings.Join(diffs, ", ")
+			}
+			rows = append(rows, []string{b.BrokerName, b.Addr, status})
+		}
+		return e.out.table(desc, []string{"BROKER", "ADDR", "STATUS"}, rows)
+	}
+}
+
+// printGroupResults 输出各 Broker 上的订阅组操作结果
+func printGroupResults(e *env, results []admin.BrokerGroupResult, opErr error) error {
+	if results == nil {
+		return opErr
+	}
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		role := "MASTER"
+		if r.Slave {
+			role = "SLAVE"
+		}
+		status := "OK"
+		if r.Error != "" {
+			status = r.Error
+		}
+		rows = append(rows, []string{r.BrokerName, r.Addr, role, status})
+	}
+	if err := e.out.table(results, []string{"BROKER", "ADDR", "ROLE", "STATUS"}, rows); err != nil {
+		return err
+	}
+	return opErr
+}
+
+// setupPauseGroup pauseGroup|resumeGroup -g group [-c cluster] [-t topic1,topic2] [-confirm 30s]
+// 暂停时 -confirm 大于 0 则等待该时长后检查客户端是否已停止拉取
+func setupPauseGroup(pause bool) func(fs *flag.FlagSet) runFunc {
+	return func(fs *flag.FlagSet) runFunc {
+		clusterName := fs.String("c", "", "集群名称，为空时作用于全部集群")
+		group := fs.String("g", "", "消费组")
+		topics := fs.String("t", "", "同时禁止/允许读取的 Topic，逗号分隔")
+		confirm := fs.Duration("confirm", 0, "暂停后等待该时长再确认客户端已停止拉取，0 表示不确认")
+
+		return func(ctx context.Context, e *env) error {
+			if err := required(map[string]string{"g": *group}); err != nil {
+				return err
+			}
+			opts := admin.PauseGroupOptions{Topics: cliutil.SplitList(*topics, ",")}
+			since := time.Now()
+			var (
+				results []admin.BrokerPatchResult
+				opErr   error
+			)
+			if pause {
+				results, opErr = e.client.PauseGroup(ctx, *clusterName, *group, opts)
+			} else {
+				results, opErr = e.client.ResumeGroup(ctx, *clusterName, *group, opts)
+			}
+			if results == nil {
+				return opErr
+			}
+			rows := make([][]string, 0, len(results))
+			for _, r := range results {
+				status := "未变化"
+				switch {
+				case r.Error != "":
+					status = r.Error
+				case len(r.Changes) > 0:
+					status = formatFieldChanges(r.Changes)
+				}
+				rows = append(rows, []string{r.BrokerName, r.Addr, status})
+			}
+			if err := e.out.table(results, []string{"BROKER", "ADDR", "STATUS"}, rows); err != nil {
+				return err
+			}
+			if opErr != nil || !pause || *confirm <= 0 {
+				return opErr
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(*confirm):
+			}
+			clients, confirmErr := e.client.ConfirmGroupPullStopped(ctx, *group, since)
+			if clients == nil {
+				if confirmErr != nil {
+					return confirmErr
+				}
+				return e.out.message("消费组 %s 没有在线客户端", *group)
+			}
+			rows = make([][]string, 0, len(clients))
+			for _, c := range clients {
+				status := "已停止"
+				switch {
+				case c.Error != "":
+					status = c.Error
+				case !c.Stopped:
+					status = "仍在拉取"
+				}
+				rows = append(rows, []string{c.ClientId, formatTimestamp(c.LastPullTime), status})
+			}
+			if err := e.out.table(clients, []string{"CLIENT", "LAST_PULL", "STATUS"}, rows); err != nil {
+				return err
+			}
+			return confirmErr
+		}
+	}
+}
+
+// setupGroupPauseState groupPauseState -g group [-c cluster] [-t topic1,topic2]
+func setupGroupPauseState(fs *flag.FlagSet) runFunc {
+	clusterName := fs.String("c", "", "集群名称，为空时查询全部集群")
+	group := fs.String("g", "", "消费组")
+	topics := fs.String("t", "", "检查读取权限的 Topic，逗号分隔")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"g": *group}); err != nil {
+			return err
+		}
+		states, stateErr := e.client.GetGroupPauseState(ctx, *clusterName, *group, cliutil.SplitList(*topics, ","))
+		if states == nil {
+			return stateErr
+		}
+		rows := make([][]string, 0, len(states))
+		for _, s := range states {
+			status := "消费中"
+			switch {
+			case s.Error != "":
+				status = s.Error
+			case s.Paused:
+				status = "已暂停"
+			}
+			rows = append(rows, []string{s.BrokerName, s.Addr, status, strings.Join(s.ForbiddenTopics, ",")})
+		}
+		if err := e.out.table(states, []string{"BROKER", "ADDR", "STATUS", "FORBIDDEN_TOPICS"}, rows); err != nil {
+			return err
+		}
+		return stateErr
+	}
+}
+
+// formatFieldChanges 格式化字段变更，如 "consumeEnable: true -> false"
+func formatFieldChanges(changes []admin.FieldChange) string {
+	parts := make([]string, len(changes))
+	for i, ch := range changes {
+		parts[i] = fmt.Sprintf("%s: %s -> %s", ch.Field, ch.Old, ch.New)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// setupSkipAccumulatedMessage skipAccumulatedMessage -g group [-t topic] [-retry]
+func setupSkipAccumulatedMessage(fs *flag.FlagSet) runFunc {
+	group := fs.String("g", "", "消费组")
+	topic := fs.String("t", "", "Topic 名称，为空时处理全部 Topic")
+	retry := fs.Bool("retry", false, "同时跳过重试 Topic 的堆积")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"g": *group}); err != nil {
+			return err
+		}
+		results, skipErr := e.client.SkipAccumulatedMessages(ctx, *group, *topic, *retry)
+		if results != nil {
+			if err := printQueueOffsetResults(e, results); err != nil {
+				return err
+			}
+		}
+		return skipErr
+	}
+}
+
+// setupCheckOffset checkOffset [-g group1,group2] [-fix]
+func setupCheckOffset(fs *flag.FlagSet) runFunc {
+	groups := fs.String("g", "", "消费组，逗号分隔，为空时检查全部消费组")
+	fix := fs.Bool("fix", false, "按建议写入 Offset 修复异常")
+
+	return func(ctx context.Context, e *env) error {
+		report, scanErr := e.client.ScanOffsetAnomalies(ctx, admin.OffsetAnomalyOptions{Groups: cliutil.SplitList(*groups, ","), Fix: *fix})
+		if report != nil {
+			rows := make([][]string, 0, len(report.Anomalies))
+			for _, a := range report.Anomalies {
+				suggested := "-"
+				if a.SuggestedOffset >= 0 {
+					suggested = strconv.FormatInt(a.SuggestedOffset, 10)
+				}
+				status := a.Suggestion
+				switch {
+				case a.Error != "":
+					status = a.Error
+				case a.Fixed:
+					status = "已修复"
+				}
+				rows = append(rows, []string{a.Group, a.MessageQueue.Topic, a.MessageQueue.BrokerName, strconv.Itoa(a.MessageQueue.QueueId),
+					string(a.Kind), strconv.FormatInt(a.CommittedOffset, 10), strconv.FormatInt(a.MinOffset, 10),
+					strconv.FormatInt(a.MaxOffset, 10), suggested, status})
+			}
+			headers := []string{"GROUP", "TOPIC", "BROKER", "QID", "KIND", "OFFSET", "MIN", "MAX", "SUGGESTED", "STATUS"}
+			if err := e.out.table(report, headers, rows); err != nil {
+				return err
+			}
+		}
+		return scanErr
+	}
+}
+
+// printQueueOffsetResults 输出队列 Offset 修改结果
+func printQueueOffsetResults(e *env, results []admin.QueueOffsetResult) error {
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		rows = append(rows, []string{r.MessageQueue.Topic, r.MessageQueue.BrokerName, strconv.Itoa(r.MessageQueue.QueueId),
+			strconv.FormatInt(r.OldOffset, 10), strconv.FormatInt(r.NewOffset, 10), r.Error})
+	}
+	return e.out.table(results, []string{"TOPIC", "BROKER", "QID", "OLD", "NEW", "ERROR"}, rows)
+}
+
+// formatDuration 格式化毫秒时长
+func formatDuration(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return (time.Duration(ms) * time.Millisecond).Round(time.Second).String()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+)
+
+// =============================================================================
+// KV 配置命令
+// =============================================================================
+
+func init() {
+	registerCommands(
+		&command{name: "updateKvConfig", usage: "创建或更新 KV 配置", setup: setupUpdateKvConfig},
+		&command{name: "deleteKvConfig", usage: "删除 KV 配置", setup: setupDeleteKvConfig},
+		&command{name: "getKvConfig", usage: "查看 KV 配置", setup: setupGetKvConfig},
+		&command{name: "getKvList", usage: "查看命名空间下全部 KV 配置", setup: setupGetKvList},
+	)
+}
+
+// setupUpdateKvConfig updateKvConfig -s namespace -k key -v value
+func setupUpdateKvConfig(fs *flag.FlagSet) runFunc {
+	namespace := fs.String("s", "", "命名空间")
+	key := fs.String("k", "", "Key")
+	value := fs.String("v", "", "Value")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"s": *namespace, "k": *key, "v": *value}); err != nil {
+			return err
+		}
+		if err := e.client.PutKVConfig(ctx, *namespace, *key, *value); err != nil {
+			return err
+		}
+		return e.out.message("更新 KV 配置成功: %s/%s", *namespace, *key)
+	}
+}
+
+// setupDeleteKvConfig deleteKvConfig -s namespace -k key
+func setupDeleteKvConfig(fs *flag.FlagSet) runFunc {
+	namespace := fs.String("s", "", "命名空间")
+	key := fs.String("k", "", "Key")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"s": *namespace, "k": *key}); err != nil {
+			return err
+		}
+		if err := e.client.DeleteKVConfig(ctx, *namespace, *key); err != nil {
+			return err
+		}
+		return e.out.message("删除 KV 配置成功: %s/%s", *namespace, *key)
+	}
+}
+
+// setupGetKvConfig getKvConfig -s namespace -k key
+func setupGetKvConfig(fs *flag.FlagSet) runFunc {
+	namespace := fs.String("s", "", "命名空间")
+	key := fs.String("k", "", "Key")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"s": *namespace, "k": *key}); err != nil {
+			return err
+		}
+		value, err := e.client.GetKVConfig(ctx, *namespace, *key)
+		if err != nil {
+			return err
+		}
+		values := map[string]string{*key: value}
+		return e.out.kv(values, values)
+	}
+}
+
+// setupGetKvList getKvList -s namespace
+func setupGetKvList(fs *flag.FlagSet) runFunc {
+	namespace := fs.String("s", "", "命名空间")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"s": *namespace}); err != nil {
+			return err
+		}
+		values, err := e.client.GetKVListByNamespace(ctx, *namespace)
+		if err != nil {
+			return err
+		}
+		if values == nil {
+			values = map[string]string{}
+		}
+		return e.out.kv(values, values)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strconv"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+)
+
+// =============================================================================
+// 消息命令
+// =============================================================================
+
+func init() {
+	registerCommands(
+		&command{name: "queryMsgByKey", usage: "按 Key 查询消息", setup: setupQueryMsgByKey},
+	)
+}
+
+// setupQueryMsgByKey queryMsgByKey -t topic -k key [-m maxNum] [-b begin] [-e end]
+func setupQueryMsgByKey(fs *flag.FlagSet) runFunc {
+	topic := fs.String("t", "", "Topic 名称")
+	key := fs.String("k", "", "消息 Key")
+	maxNum := fs.Int("m", 64, "最大返回条数")
+	begin := fs.String("b", "0", "起始时间：毫秒时间戳或 yyyy-MM-dd#HH:mm:ss:SSS")
+	end := fs.String("e", "now", "结束时间：now、毫秒时间戳或 yyyy-MM-dd#HH:mm:ss:SSS")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"t": *topic, "k": *key}); err != nil {
+			return err
+		}
+		beginTs, err := parseTimestamp(*begin)
+		if err != nil {
+			return err
+		}
+		endTs, err := parseTimestamp(*end)
+		if err != nil {
+			return err
+		}
+
+		msgs, err := e.client.QueryMessage(ctx, *topic, *key, *maxNum, beginTs, endTs)
+		if err != nil {
+			return err
+		}
+		if msgs == nil {
+			msgs = []*admin.MessageExt{}
+		}
+
+		rows := make([][]string, 0, len(msgs))
+		for _, msg := range msgs {
+			rows = append(rows, []string{
+				msg.MsgId,
+				msg.BrokerName,
+				strconv.Itoa(msg.QueueId),
+				strconv.FormatInt(msg.QueueOffset, 10),
+				msg.Properties["TAGS"],
+				formatTimestamp(msg.StoreTimestamp),
+			})
+		}
+		return e.out.table(msgs, []string{"MSG ID", "BROKER", "QID", "OFFSET", "TAGS", "STORE TIME"}, rows)
+	}
+}
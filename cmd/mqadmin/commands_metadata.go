@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+	"github.com/codermast/rocketmq-admin-go/internal/cliutil"
+)
+
+// =============================================================================
+// 元数据备份命令
+// =============================================================================
+
+func init() {
+	registerCommands(
+		&command{name: "exportMetadata", usage: "导出集群元数据快照", setup: setupExportMetadata},
+		&command{name: "restoreMetadata", usage: "从快照恢复集群元数据", setup: setupRestoreMetadata},
+		&command{name: "migrateCluster", usage: "迁移 Topic、订阅组和消费进度到其他集群", setup: setupMigrateCluster},
+	)
+}
+
+// snapshotFilterFlags 快照过滤参数
+type snapshotFilterFlags struct {
+	kinds, include, exclude *string
+	system                  *bool
+}
+
+// register 注册 -k、-i、-x、-system 参数
+func (f *snapshotFilterFlags) register(fs *flag.FlagSet) {
+	f.kinds = fs.String("k", "", "资源类型，逗号分隔: topic,group,offset,brokerConfig,user,acl,kv")
+	f.include = fs.String("i", "", "包含的名称通配符，逗号分隔")
+	f.exclude = fs.String("x", "", "排除的名称通配符，逗号分隔")
+	f.system = fs.Bool("system", false, "包含系统 Topic 和系统订阅组")
+}
+
+// filter 返回过滤条件
+func (f *snapshotFilterFlags) filter() admin.SnapshotFilter {
+	filter := admin.SnapshotFilter{
+		Include:       cliutil.SplitList(*f.include, ","),
+		Exclude:       cliutil.SplitList(*f.exclude, ","),
+		IncludeSystem: *f.system,
+	}
+	for _, kind := range cliutil.SplitList(*f.kinds, ",") {
+		filter.Kinds = append(filter.Kinds, admin.ResourceKind(kind))
+	}
+	return filter
+}
+
+// setupExportMetadata exportMetadata -f file [-c cluster] [-k kinds] [-i include] [-x exclude] [-s namespaces]
+func setupExportMetadata(fs *flag.FlagSet) runFunc {
+	file := fs.String("f", "", "快照文件路径")
+	cluster := fs.String("c", "", "集群名称，为空时导出全部集群")
+	namespaces := fs.String("s", "", "导出的 KV 命名空间，逗号分隔")
+	var filter snapshotFilterFlags
+	filter.register(fs)
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"f": *file}); err != nil {
+			return err
+		}
+		snap, err := e.client.ExportMetadata(ctx, admin.ExportOptions{
+			SnapshotFilter: filter.filter(),
+			Cluster:        *cluster,
+			KVNamespaces:   cliutil.SplitList(*namespaces, ","),
+		})
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		if err := admin.WriteSnapshot(f, snap); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("导出 %d 个 Broker 的元数据到 %s", len(snap.Brokers), *file)
+		for _, w := range snap.Warnings {
+			msg += "\n警告: " + w
+		}
+		return e.out.message("%s", msg)
+	}
+}
+
+// setupRestoreMetadata restoreMetadata -f file [-c cluster] [-m old=new,...] [-conflict skip|overwrite|fail] [-dry-run]
+func setupRestoreMetadata(fs *flag.FlagSet) runFunc {
+	file := fs.String("f", "", "快照文件路径")
+	cluster := fs.String("c", "", "目标集群名称，为空时使用全部集群")
+	mapping := fs.String("m", "", "Broker 名映射，如 broker-a=broker-x,broker-b=broker-y")
+	conflict := fs.String("conflict", string(admin.ConflictSkip), "冲突处理: skip、overwrite、fail")
+	dryRun := fs.Bool("dry-run", false, "只输出恢复报告，不修改集群")
+	var filter snapshotFilterFlags
+	filter.register(fs)
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"f": *file}); err != nil {
+			return err
+		}
+		policy := admin.ConflictPolicy(*conflict)
+		switch policy {
+		case admin.ConflictSkip, admin.ConflictOverwrite, admin.ConflictFail:
+		default:
+			return newUsageError("未知的冲突处理方式: %s", *conflict)
+		}
+		brokerMapping := make(map[string]string)
+		for _, pair := range cliutil.SplitList(*mapping, ",") {
+			from, to, ok := strings.Cut(pair, "=")
+			if !ok {
+				return newUsageError("Broker 映射格式错误: %s", pair)
+			}
+			brokerMapping[strings.TrimSpace(from)] = strings.TrimSpace(to)
+		}
+
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		snap, err := admin.ReadSnapshot(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+		report, restoreErr := e.client.RestoreMetadata(ctx, snap, admin.RestoreOptions{
+			SnapshotFilter: filter.filter(),
+			Cluster:        *cluster,
+			BrokerMapping:  brokerMapping,
+			Conflict:       policy,
+			DryRun:         *dryRun,
+		})
+		if report != nil {
+			var rows [][]string
+			for _, item := range report.Items {
+				rows = append(rows, []string{string(item.Kind), item.Broker, item.Name, string(item.Status), item.Reason})
+			}
+			if err := e.out.table(report, []string{"KIND", "BROKER", "NAME", "STATUS", "REASON"}, rows); err != nil {
+				return err
+			}
+		}
+		return restoreErr
+	}
+}
+
+// setupMigrateCluster migrateCluster -t targetNamesrv [-t-ak ak -t-sk sk] [-topics a,b] [-groups g1,g2] [-c cluster] [-overwrite] [-skip-offsets] [-force] [-dry-run]
+func setupMigrateCluster(fs *flag.FlagSet) runFunc {
+	targetNamesrv := fs.String("t", "", "目标 NameServer 地址，多个用分号分隔")
+	targetAK := fs.String("t-ak", "", "目标集群 ACL AccessKey")
+	targetSK := fs.String("t-sk", "", "目标集群 ACL SecretKey")
+	topics := fs.String("topics", "", "迁移的 Topic，逗号分隔")
+	groups := fs.String("groups", "", "迁移的订阅组及消费进度，逗号分隔")
+	cluster := fs.String("c", "", "目标集群名称，为空时使用全部集群")
+	overwrite := fs.Bool("overwrite", false, "目标配置不同时使用源配置覆盖")
+	skipOffsets := fs.Bool("skip-offsets", false, "只迁移配置，不迁移消费进度")
+	force := fs.Bool("force", false, "允许目标消费组在线，并允许回退目标 Offset")
+	dryRun := fs.Bool("dry-run", false, "只输出迁移报告，不修改目标集群")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"t": *targetNamesrv}); err != nil {
+			return err
+		}
+		opts := []admin.Option{admin.WithNameServers(cliutil.SplitList(*targetNamesrv, ";"))}
+		if *targetAK != "" {
+			opts = append(opts, admin.WithACL(*targetAK, *targetSK))
+		}
+		target, err := admin.NewClient(opts...)
+		if err != nil {
+			return err
+		}
+		if err := target.Start(); err != nil {
+			return err
+		}
+		defer target.Close()
+
+		report, migrateErr := admin.MigrateCluster(ctx, e.client, target, admin.MigrationOptions{
+			Topics:          cliutil.SplitList(*topics, ","),
+			Groups:          cliutil.SplitList(*groups, ","),
+			TargetCluster:   *cluster,
+			OverwriteConfig: *overwrite,
+			SkipOffsets:     *skipOffsets,
+			Force:           *force,
+			DryRun:          *dryRun,
+		})
+		if report != nil {
+			var rows [][]string
+			for _, item := range report.Configs {
+				rows = append(rows, []string{string(item.Kind), item.Broker + "/" + item.Name, "", "", "", string(item.Status), item.Reason})
+			}
+			for _, m := range report.Offsets {
+				queue := fmt.Sprintf("%s@%s@%d %s", m.Target.Topic, m.Target.BrokerName, m.Target.QueueId, m.Group)
+				rows = append(rows, []string{string(admin.ResourceOffset), queue, fmt.Sprint(m.PreviousOffset), fmt.Sprint(m.TargetOffset),
+					string(m.Accuracy), string(m.Status), m.Reason})
+			}
+			if err := e.out.table(report, []string{"KIND", "NAME", "OLD", "NEW", "ACCURACY", "STATUS", "REASON"}, rows); err != nil {
+				return err
+			}
+		}
+		return migrateErr
+	}
+}
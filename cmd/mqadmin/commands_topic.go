@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+	"github.com/codermast/rocketmq-admin-go/internal/cliutil"
+)
+
+// =============================================================================
+// Topic 命令
+// =============================================================================
+
+func init() {
+	registerCommands(
+		&command{name: "topicList", usage: "查看 Topic 列表", setup: setupTopicList},
+		&command{name: "topicRoute", usage: "查看 Topic 路由", setup: setupTopicRoute},
+		&command{name: "topicStatus", usage: "查看 Topic 各队列偏移", setup: setupTopicStatus},
+		&command{name: "updateTopic", usage: "创建或更新 Topic", setup: setupUpdateTopic},
+		&command{name: "deleteTopic", usage: "删除 Topic", setup: setupDeleteTopic},
+		&command{name: "updateTopicPerm", usage: "修改 Topic 在指定 Broker 上的权限并等待路由生效", setup: setupUpdateTopicPerm},
+	)
+}
+
+// setupTopicList topicList [-c cluster]
+func setupTopicList(fs *flag.FlagSet) runFunc {
+	clusterName := fs.String("c", "", "只显示指定集群的 Topic")
+
+	return func(ctx context.Context, e *env) error {
+		var (
+			topics *admin.TopicList
+			err    error
+		)
+		if *clusterName != "" {
+			topics, err = e.client.FetchTopicsByCluster(ctx, *clusterName)
+		} else {
+			topics, err = e.client.FetchAllTopicList(ctx)
+		}
+		if err != nil {
+			return err
+		}
+
+		names := append([]string(nil), topics.TopicList...)
+		sort.Strings(names)
+		rows := make([][]string, 0, len(names))
+		for _, name := range names {
+			rows = append(rows, []string{name})
+		}
+		return e.out.table(names, []string{"TOPIC"}, rows)
+	}
+}
+
+// setupTopicRoute topicRoute -t topic
+func setupTopicRoute(fs *flag.FlagSet) runFunc {
+	topic := fs.String("t", "", "Topic 名称")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"t": *topic}); err != nil {
+			return err
+		}
+		route, err := e.client.ExamineTopicRouteInfo(ctx, *topic)
+		if err != nil {
+			return err
+		}
+
+		addrs := make(map[string]string)
+		for _, brokerData := range route.BrokerDatas {
+			addrs[brokerData.BrokerName] = brokerData.BrokerAddrs["0"]
+		}
+		queueDatas := append([]*admin.QueueData(nil), route.QueueDatas...)
+		sort.Slice(queueDatas, func(i, j int) bool { return queueDatas[i].BrokerName < queueDatas[j].BrokerName })
+
+		rows := make([][]string, 0, len(queueDatas))
+		for _, qd := range queueDatas {
+			rows = append(rows, []string{
+				qd.BrokerName,
+				addrs[qd.BrokerName],
+				strconv.Itoa(qd.ReadQueueNums),
+				strconv.Itoa(qd.WriteQueueNums),
+				qd.Perm.String(),
+			})
+		}
+		return e.out.table(route, []string{"BROKER", "MASTER", "READ", "WRITE", "PERM"}, rows)
+	}
+}
+
+// setupTopicStatus topicStatus -t topic
+func setupTopicStatus(fs *flag.FlagSet) runFunc {
+	topic := fs.String("t", "", "Topic 名称")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"t": *topic}); err != nil {
+			return err
+		}
+		stats, err := e.client.ExamineTopicStats(ctx, *topic)
+		if err != nil {
+			return err
+		}
+
+		type queueStatus struct {
+			admin.MessageQueue
+			MinOffset           int64 `json:"minOffset"`
+			MaxOffset           int64 `json:"maxOffset"`
+			LastUpdateTimestamp int64 `json:"lastUpdateTimestamp"`
+		}
+		queues := make([]queueStatus, 0, len(stats.OffsetTable))
+		for key, offset := range stats.OffsetTable {
+			mq, err := admin.ParseMessageQueueKey(key)
+			if err != nil {
+				continue
+			}
+			queues = append(queues, queueStatus{
+				MessageQueue:        mq,
+				MinOffset:           offset.MinOffset,
+				MaxOffset:           offset.MaxOffset,
+				LastUpdateTimestamp: offset.LastUpdateTimestamp,
+			})
+		}
+		sort.Slice(queues, func(i, j int) bool {
+			if queues[i].BrokerName != queues[j].BrokerName {
+				return queues[i].BrokerName < queues[j].BrokerName
+			}
+			return queues[i].QueueId < queues[j].QueueId
+		})
+
+		rows := make([][]string, 0, len(queues))
+		for _, q := range queues {
+			rows = append(rows, []string{
+				q.BrokerName,
+				strconv.Itoa(q.QueueId),
+				strconv.FormatInt(q.MinOffset, 10),
+				strconv.FormatInt(q.MaxOffset, 10),
+				formatTimestamp(q.LastUpdateTimestamp),
+			})
+		}
+		return e.out.table(queues, []string{"BROKER", "QID", "MIN", "MAX", "LAST UPDATED"}, rows)
+	}
+}
+
+// setupUpdateTopic updateTopic -b addr | -c cluster -t topic [-r 8] [-w 8] [-p 6] [-o]
+func setupUpdateTopic(fs *flag.FlagSet) runFunc {
+	var target brokerTarget
+	target.register(fs)
+	topic := fs.String("t", "", "Topic 名称")
+	readQueues := fs.Int("r", 8, "读队列数量")
+	writeQueues := fs.Int("w", 8, "写队列数量")
+	perm := fs.String("p", "RW-", "权限（R-- 只读、-W- 只写、RW- 读写，也可用 4、2、6）")
+	order := fs.Bool("o", false, "是否顺序 Topic")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"t": *topic}); err != nil {
+			return err
+		}
+		topicPerm, err := admin.ParsePerm(*perm)
+		if err != nil {
+			return err
+		}
+		config := admin.TopicConfig{
+			TopicName:       *topic,
+			ReadQueueNums:   *readQueues,
+			WriteQueueNums:  *writeQueues,
+			Perm:            topicPerm,
+			TopicFilterType: "SINGLE_TAG",
+			Order:           *order,
+		}
+
+		// 按集群创建时部分失败会回滚，并等待 NameServer 路由生效
+		if target.brokerAddr == "" && target.clusterName != "" {
+			results, createErr := e.client.CreateTopicInCluster(ctx, target.clusterName, config)
+			if results != nil {
+				rows := make([][]string, 0, len(results))
+				for _, r := range results {
+					rows = append(rows, []string{r.Topic, r.BrokerName, r.Addr, string(r.Status), r.Error})
+				}
+				if err := e.out.table(results, []string{"TOPIC", "BROKER", "ADDR", "STATUS", "ERROR"}, rows); err != nil {
+					return err
+				}
+			}
+			return createErr
+		}
+
+		brokers, err := target.resolve(ctx, e.client)
+		if err != nil {
+			return err
+		}
+		for _, addr := range sortedKeys(brokers) {
+			if err := e.client.CreateTopic(ctx, addr, config); err != nil {
+				return fmt.Errorf("在 Broker %s 创建 Topic 失败: %w", addr, err)
+			}
+		}
+		return e.out.message("在 %d 个 Broker 上创建或更新 Topic %s 成功", len(brokers), *topic)
+	}
+}
+
+// setupUpdateTopicPerm updateTopicPerm -t topic -b broker-a,broker-b -p R--
+func setupUpdateTopicPerm(fs *flag.FlagSet) runFunc {
+	topic := fs.String("t", "", "Topic 名称")
+	brokers := fs.String("b", "", "Broker 名称，逗号分隔")
+	perm := fs.String("p", "", "权限（R-- 只读、-W- 只写、RW- 读写，也可用 4、2、6）")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"t": *topic, "b": *brokers, "p": *perm}); err != nil {
+			return err
+		}
+		topicPerm, err := admin.ParsePerm(*perm)
+		if err != nil {
+			return err
+		}
+		results, updateErr := e.client.UpdateTopicPerm(ctx, *topic, cliutil.SplitList(*brokers, ","), topicPerm)
+		if results == nil {
+			return updateErr
+		}
+		rows := make([][]string, 0, len(results))
+		for _, r := range results {
+			status := "未变化"
+			switch {
+			case r.Error != "":
+				status = r.Error
+			case len(r.Changes) > 0:
+				status = formatFieldChanges(r.Changes)
+			}
+			rows = append(rows, []string{r.BrokerName, r.Addr, status})
+		}
+		if err := e.out.table(results, []string{"BROKER", "ADDR", "STATUS"}, rows); err != nil {
+			return err
+		}
+		return updateErr
+	}
+}
+
+// setupDeleteTopic deleteTopic -t topic -c cluster
+func setupDeleteTopic(fs *flag.FlagSet) runFunc {
+	topic := fs.String("t", "", "Topic 名称")
+	clusterName := fs.String("c", "", "集群名称")
+
+	return func(ctx context.Context, e *env) error {
+		if err := required(map[string]string{"t": *topic, "c": *clusterName}); err != nil {
+			return err
+		}
+		if err := e.client.DeleteTopic(ctx, *topic, *clusterName); err != nil {
+			return err
+		}
+		return e.out.message("从集群 %s 删除 Topic %s 成功", *clusterName, *topic)
+	}
+}
@@ -0,0 +1,237 @@
+// mqadmin 与 Java mqadmin 命令名兼容的 RocketMQ 运维命令行工具
+//
+// 用法:
+//
+//	mqadmin <command> [flags]
+//
+// 公共参数也可以通过环境变量设置：
+//
+//	ROCKETMQ_NAMESRV_ADDR / NAMESRV_ADDR  NameServer 地址
+//	ROCKETMQ_ACCESS_KEY / ROCKETMQ_SECRET_KEY  ACL 凭据
+//	MQADMIN_OUTPUT  输出格式（table、json、yaml）
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+	"github.com/codermast/rocketmq-admin-go/internal/cliutil"
+)
+
+// runFunc 命令执行函数
+type runFunc func(ctx context.Context, env *env) error
+
+// command 子命令
+type command struct {
+	name  string
+	usage string
+	// setup 注册命令参数并返回执行函数
+	setup func(fs *flag.FlagSet) runFunc
+}
+
+// env 命令执行环境
+type env struct {
+	client *admin.Client
+	out    *printer
+}
+
+// commonFlags 所有命令共享的参数
+type commonFlags struct {
+	namesrv   string
+	accessKey string
+	secretKey string
+	timeout   time.Duration
+	output    string
+}
+
+// register 注册公共参数，默认值取自环境变量
+func (f *commonFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.namesrv, "n", firstEnv("127.0.0.1:9876", "ROCKETMQ_NAMESRV_ADDR", "NAMESRV_ADDR"), "NameServer 地址，多个以分号分隔")
+	fs.StringVar(&f.accessKey, "ak", os.Getenv("ROCKETMQ_ACCESS_KEY"), "ACL AccessKey")
+	fs.StringVar(&f.secretKey, "sk", os.Getenv("ROCKETMQ_SECRET_KEY"), "ACL SecretKey")
+	fs.DurationVar(&f.timeout, "timeout", 10*time.Second, "请求超时时间")
+	fs.StringVar(&f.output, "output", firstEnv(outputTable, "MQADMIN_OUTPUT"), "输出格式: table、json、yaml")
+}
+
+// commands 全部子命令
+var commands []*command
+
+// registerCommands 注册一组子命令
+func registerCommands(cmds ...*command) {
+	commands = append(commands, cmds...)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run 解析参数并执行子命令，返回进程退出码
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stderr)
+		return 2
+	}
+
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		fmt.Fprintf(stderr, "未知命令: %s\n\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var common commonFlags
+	common.register(fs)
+	runCmd := cmd.setup(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "用法: mqadmin %s [flags]\n\n%s\n\n", cmd.name, cmd.usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	out, err := newPrinter(stdout, common.output)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	opts := []admin.Option{
+		admin.WithNameServers(cliutil.SplitList(common.namesrv, ";")),
+		admin.WithTimeout(common.timeout),
+	}
+	if common.accessKey != "" {
+		opts = append(opts, admin.WithACL(common.accessKey, common.secretKey))
+	}
+	client, err := admin.NewClient(opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "创建客户端失败: %v\n", err)
+		return 1
+	}
+	if err := client.Start(); err != nil {
+		fmt.Fprintf(stderr, "启动客户端失败: %v\n", err)
+		return 1
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*common.timeout)
+	defer cancel()
+
+	if err := runCmd(ctx, &env{client: client, out: out}); err != nil {
+		var usageErr *usageError
+		if errors.As(err, &usageErr) {
+			fmt.Fprintf(stderr, "%v\n\n", err)
+			fs.Usage()
+			return 2
+		}
+		fmt.Fprintf(stderr, "%s 执行失败: %v\n", cmd.name, err)
+		return 1
+	}
+	return 0
+}
+
+// findCommand 按名称查找子命令（忽略大小写）
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if strings.EqualFold(cmd.name, name) {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// printUsage 输出命令列表
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "用法: mqadmin <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "命令:")
+
+	sorted := make([]*command, len(commands))
+	copy(sorted, commands)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	for _, cmd := range sorted {
+		fmt.Fprintf(w, "  %-22s %s\n", cmd.name, cmd.usage)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "使用 mqadmin <command> -h 查看命令参数")
+}
+
+// usageError 参数错误
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// newUsageError 创建参数错误
+func newUsageError(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// required 检查必填参数
+func required(values map[string]string) error {
+	names := make([]string, 0, len(values))
+	for name, value := range values {
+		if value == "" {
+			names = append(names, "-"+name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return newUsageError("缺少必填参数: %s", strings.Join(names, ", "))
+}
+
+// firstEnv 返回第一个非空环境变量，都为空时返回默认值
+func firstEnv(def string, keys ...string) string {
+	for _, key := range keys {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return def
+}
+
+// parseTimestamp 解析时间参数，支持 now、毫秒时间戳、yyyy-MM-dd#HH:mm:ss:SSS 和 RFC3339
+func parseTimestamp(s string) (int64, error) {
+	if s == "now" {
+		return time.Now().UnixMilli(), nil
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ms, nil
+	}
+	value := s
+	// Java mqadmin 的毫秒以冒号分隔，转换为 Go 可解析的小数形式
+	if i := strings.LastIndex(value, ":"); strings.Contains(value, "#") && i == len(value)-4 {
+		value = value[:i] + "." + value[i+1:]
+	}
+	for _, layout := range []string{"2006-01-02#15:04:05.000", "2006-01-02#15:04:05", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t.UnixMilli(), nil
+		}
+	}
+	return 0, newUsageError("无法解析时间: %s（支持 now、毫秒时间戳、yyyy-MM-dd#HH:mm:ss:SSS）", s)
+}
+
+// formatTimestamp 格式化毫秒时间戳，0 输出为 -
+func formatTimestamp(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return time.UnixMilli(ms).Format("2006-01-02 15:04:05")
+}
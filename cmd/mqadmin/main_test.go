@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// mqadmin 单元测试
+// =============================================================================
+
+// TestParseTimestamp 测试时间参数解析
+func TestParseTimestamp(t *testing.T) {
+	if ms, err := parseTimestamp("1700000000000"); err != nil || ms != 1700000000000 {
+		t.Errorf("解析毫秒时间戳失败: %d %v", ms, err)
+	}
+
+	want := time.Date(2024, 1, 2, 3, 4, 5, 6*int(time.Millisecond), time.Local).UnixMilli()
+	if ms, err := parseTimestamp("2024-01-02#03:04:05:006"); err != nil || ms != want {
+		t.Errorf("解析 yyyy-MM-dd#HH:mm:ss:SSS 失败: %d %v, want %d", ms, err, want)
+	}
+
+	before := time.Now().UnixMilli()
+	if ms, err := parseTimestamp("now"); err != nil || ms < before {
+		t.Errorf("解析 now 失败: %d %v", ms, err)
+	}
+
+	var usageErr *usageError
+	if _, err := parseTimestamp("yesterday"); !errors.As(err, &usageErr) {
+		t.Errorf("非法时间应返回参数错误: %v", err)
+	}
+}
+
+// TestRequired 测试必填参数检查
+func TestRequired(t *testing.T) {
+	if err := required(map[string]string{"t": "TopicTest"}); err != nil {
+		t.Errorf("参数齐全时不应返回错误: %v", err)
+	}
+
+	err := required(map[string]string{"t": "", "g": "", "k": "key"})
+	if err == nil || err.Error() != "缺少必填参数: -g, -t" {
+		t.Errorf("错误信息不匹配: %v", err)
+	}
+}
+
+// TestPrinter 测试三种输出格式
+func TestPrinter(t *testing.T) {
+	data := []map[string]any{{"topic": "TopicTest", "queues": 8}}
+	headers := []string{"TOPIC", "QUEUES"}
+	rows := [][]string{{"TopicTest", "8"}}
+
+	cases := map[string]string{
+		outputTable: "TOPIC      QUEUES\nTopicTest  8\n",
+		outputJSON:  "[\n  {\n    \"queues\": 8,\n    \"topic\": \"TopicTest\"\n  }\n]\n",
+		outputYAML:  "- queues: 8\n  topic: TopicTest\n",
+	}
+	for format, want := range cases {
+		var buf bytes.Buffer
+		p, err := newPrinter(&buf, format)
+		if err != nil {
+			t.Fatalf("创建输出器失败: %v", err)
+		}
+		if err := p.table(data, headers, rows); err != nil {
+			t.Fatalf("%s 输出失败: %v", format, err)
+		}
+		if buf.String() != want {
+			t.Errorf("%s 输出不匹配:\n%q\nwant:\n%q", format, buf.String(), want)
+		}
+	}
+
+	if _, err := newPrinter(&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("不支持的格式应返回错误")
+	}
+}
+
+// TestBuildAclInfo 测试按资源生成 ACL 策略
+func TestBuildAclInfo(t *testing.T) {
+	acl := buildAclInfo("User:alice", []string{"Topic:orders", "Group:cg"}, []string{"Pub", "Sub"}, "Allow", nil)
+	if acl.Subject != "User:alice" || len(acl.Policies) != 2 {
+		t.Fatalf("ACL 不匹配: %+v", acl)
+	}
+	if acl.Policies[1].Resource != "Group:cg" || len(acl.Policies[1].Actions) != 2 || acl.Policies[1].Decision != "Allow" {
+		t.Errorf("策略不匹配: %+v", acl.Policies[1])
+	}
+}
+
+// TestRun_Usage 测试命令行参数错误的退出码
+func TestRun_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	if code := run(nil, &stdout, &stderr); code != 2 {
+		t.Errorf("无参数退出码应为 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "topicList") {
+		t.Error("用法说明应包含命令列表")
+	}
+
+	stderr.Reset()
+	if code := run([]string{"noSuchCommand"}, &stdout, &stderr); code != 2 {
+		t.Errorf("未知命令退出码应为 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "未知命令") {
+		t.Errorf("错误信息不匹配: %s", stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run([]string{"topicroute", "-n", "127.0.0.1:1"}, &stdout, &stderr); code != 2 {
+		t.Errorf("缺少必填参数退出码应为 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "-t") {
+		t.Errorf("应提示缺少 -t: %s", stderr.String())
+	}
+
+	stderr.Reset()
+	if code := run([]string{"topicList", "-output", "xml"}, &stdout, &stderr); code != 2 {
+		t.Errorf("非法输出格式退出码应为 2, got %d", code)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 输出格式
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// printer 按指定格式输出命令结果
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// newPrinter 创建输出器
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	format = strings.ToLower(format)
+	switch format {
+	case outputTable, outputJSON, outputYAML:
+		return &printer{w: w, format: format}, nil
+	default:
+		return nil, fmt.Errorf("不支持的输出格式: %s（支持 table、json、yaml）", format)
+	}
+}
+
+// table 输出结果：表格格式使用 headers 和 rows，JSON/YAML 格式输出 data
+func (p *printer) table(data any, headers []string, rows [][]string) error {
+	if p.format != outputTable {
+		return p.data(data)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// kv 输出键值对：表格格式按 key 排序输出两列，JSON/YAML 格式输出 data
+func (p *printer) kv(data any, values map[string]string) error {
+	rows := make([][]string, 0, len(values))
+	for _, k := range sortedKeys(values) {
+		rows = append(rows, []string{k, values[k]})
+	}
+	return p.table(data, []string{"KEY", "VALUE"}, rows)
+}
+
+// message 输出操作结果，JSON/YAML 格式输出 {"result": msg}
+func (p *printer) message(format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if p.format == outputTable {
+		_, err := fmt.Fprintln(p.w, msg)
+		return err
+	}
+	return p.data(map[string]string{"result": msg})
+}
+
+// data 按 JSON 或 YAML 输出任意数据（YAML 字段名与 JSON 保持一致）
+func (p *printer) data(data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if p.format == outputYAML {
+		var generic any
+		if err := json.Unmarshal(raw, &generic); err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(p.w)
+		enc.SetIndent(2)
+		if err := enc.Encode(generic); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(p.w)
+	return err
+}
+
+// sortedKeys 返回排序后的 key
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,141 @@
+// rocketmq-admin-server 以 REST/JSON 形式暴露 RocketMQ 运维接口
+//
+// 用法:
+//
+//	rocketmq-admin-server -namesrv 127.0.0.1:9876 -listen :8080 -tokens tokens.json
+//
+// tokens.json 以 Token 为 key 配置调用方和访问级别:
+//
+//	{"d2ViLWNvbnNvbGU": {"name": "web-console", "access": "read-write"}}
+//
+// 未配置 -tokens 时不做认证，匿名调用方只能访问只读接口；
+// 仅在可信网络中可通过 -allow-anonymous-write 允许匿名调用方执行变更。
+// 接口文档见 GET /openapi.json。
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+	"github.com/codermast/rocketmq-admin-go/gateway"
+	"github.com/codermast/rocketmq-admin-go/internal/cliutil"
+)
+
+func main() {
+	var (
+		namesrv        = flag.String("namesrv", cliutil.EnvOr("ROCKETMQ_NAMESRV_ADDR", "127.0.0.1:9876"), "NameServer 地址，多个以分号分隔")
+		listen         = flag.String("listen", ":8080", "HTTP 监听地址")
+		accessKey      = flag.String("access-key", os.Getenv("ROCKETMQ_ACCESS_KEY"), "ACL AccessKey")
+		secretKey      = flag.String("secret-key", os.Getenv("ROCKETMQ_SECRET_KEY"), "ACL SecretKey")
+		timeout        = flag.Duration("timeout", 5*time.Second, "单个 RocketMQ 请求超时时间")
+		requestTimeout = flag.Duration("request-timeout", 30*time.Second, "单个 HTTP 请求超时时间")
+		tokensFile     = flag.String("tokens", os.Getenv("ROCKETMQ_ADMIN_TOKENS"), "Token 配置文件（JSON），为空时不认证")
+		readOnly       = flag.Bool("read-only", false, "只开放只读接口")
+		anonymousWrite = flag.Bool("allow-anonymous-write", false, "未配置 -tokens 时允许匿名调用方访问读写接口")
+		quiet          = flag.Bool("quiet", false, "不输出请求日志")
+	)
+	flag.Parse()
+
+	clientOpts := []admin.Option{
+		admin.WithNameServers(cliutil.SplitList(*namesrv, ";")),
+		admin.WithTimeout(*timeout),
+	}
+	if *accessKey != "" {
+		clientOpts = append(clientOpts, admin.WithACL(*accessKey, *secretKey))
+	}
+	client, err := admin.NewClient(clientOpts...)
+	if err != nil {
+		log.Fatalf("创建客户端失败: %v", err)
+	}
+	if err := client.Start(); err != nil {
+		log.Fatalf("启动客户端失败: %v", err)
+	}
+	defer client.Close()
+
+	gatewayOpts := []gateway.Option{gateway.WithRequestTimeout(*requestTimeout)}
+	switch {
+	case *tokensFile != "" && *anonymousWrite:
+		log.Fatalf("-allow-anonymous-write 不能与 -tokens 同时使用")
+	case *tokensFile != "":
+		tokens, err := loadTokens(*tokensFile)
+		if err != nil {
+			log.Fatalf("加载 Token 配置失败: %v", err)
+		}
+		gatewayOpts = append(gatewayOpts, gateway.WithAuthenticator(gateway.NewTokenAuthenticator(tokens)))
+	case *anonymousWrite:
+		log.Printf("警告: 未配置 -tokens 且允许匿名写入，任何能访问 %s 的调用方都可以修改集群", *listen)
+		gatewayOpts = append(gatewayOpts, gateway.WithAnonymousWrite())
+	default:
+		log.Printf("未配置 -tokens，匿名调用方只能访问只读接口")
+	}
+	if *readOnly {
+		gatewayOpts = append(gatewayOpts, gateway.WithReadOnly())
+	}
+	if !*quiet {
+		gatewayOpts = append(gatewayOpts, gateway.WithLogger(log.Default()))
+	}
+	gw, err := gateway.New(client, gatewayOpts...)
+	if err != nil {
+		log.Fatalf("创建网关失败: %v", err)
+	}
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           gw,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Printf("rocketmq-admin-server 监听 %s，NameServer: %s", *listen, *namesrv)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("HTTP 服务异常: %v", err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_ = server.Shutdown(ctx)
+}
+
+// loadTokens 读取 Token 配置文件
+func loadTokens(path string) (map[string]gateway.Principal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string]struct {
+		Name   string `json:"name"`
+		Access string `json:"access"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+
+	tokens := make(map[string]gateway.Principal, len(entries))
+	for token, entry := range entries {
+		access, err := gateway.ParseAccess(entry.Access)
+		if err != nil {
+			return nil, fmt.Errorf("Token %s: %w", entry.Name, err)
+		}
+		tokens[token] = gateway.Principal{Name: entry.Name, Access: access}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%s 中没有 Token", path)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,95 @@
+// rocketmq-exporter 以 Prometheus 格式暴露 RocketMQ 集群指标
+//
+// 用法:
+//
+//	rocketmq-exporter -namesrv 127.0.0.1:9876 -listen :5557
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+	"github.com/codermast/rocketmq-admin-go/exporter"
+	"github.com/codermast/rocketmq-admin-go/internal/cliutil"
+)
+
+func main() {
+	var (
+		namesrv       = flag.String("namesrv", cliutil.EnvOr("ROCKETMQ_NAMESRV_ADDR", "127.0.0.1:9876"), "NameServer 地址，多个以分号分隔")
+		listen        = flag.String("listen", ":5557", "HTTP 监听地址")
+		accessKey     = flag.String("access-key", os.Getenv("ROCKETMQ_ACCESS_KEY"), "ACL AccessKey")
+		secretKey     = flag.String("secret-key", os.Getenv("ROCKETMQ_SECRET_KEY"), "ACL SecretKey")
+		timeout       = flag.Duration("timeout", 5*time.Second, "单个请求超时时间")
+		concurrency   = flag.Int("concurrency", 8, "采集并发数")
+		cacheTTL      = flag.Duration("cache-ttl", 15*time.Second, "采集结果缓存时间")
+		scrapeTimeout = flag.Duration("scrape-timeout", 30*time.Second, "单次采集超时时间")
+		topicAllow    = flag.String("topic-allow", "", "Topic 允许列表（正则，逗号分隔）")
+		topicDeny     = flag.String("topic-deny", "", "Topic 拒绝列表（正则，逗号分隔）")
+		groupAllow    = flag.String("group-allow", "", "消费组允许列表（正则，逗号分隔）")
+		groupDeny     = flag.String("group-deny", "", "消费组拒绝列表（正则，逗号分隔）")
+		systemTopics  = flag.Bool("system-topics", false, "采集重试、死信及系统 Topic")
+		noBrokerStats = flag.Bool("no-broker-stats", false, "关闭 Topic/消费组 TPS 采集")
+	)
+	flag.Parse()
+
+	clientOpts := []admin.Option{
+		admin.WithNameServers(cliutil.SplitList(*namesrv, ";")),
+		admin.WithTimeout(*timeout),
+	}
+	if *accessKey != "" {
+		clientOpts = append(clientOpts, admin.WithACL(*accessKey, *secretKey))
+	}
+	client, err := admin.NewClient(clientOpts...)
+	if err != nil {
+		log.Fatalf("创建客户端失败: %v", err)
+	}
+	if err := client.Start(); err != nil {
+		log.Fatalf("启动客户端失败: %v", err)
+	}
+	defer client.Close()
+
+	exporterOpts := []exporter.Option{
+		exporter.WithConcurrency(*concurrency),
+		exporter.WithCacheTTL(*cacheTTL),
+		exporter.WithScrapeTimeout(*scrapeTimeout),
+		exporter.WithTopicFilter(cliutil.SplitList(*topicAllow, ","), cliutil.SplitList(*topicDeny, ",")),
+		exporter.WithGroupFilter(cliutil.SplitList(*groupAllow, ","), cliutil.SplitList(*groupDeny, ",")),
+		exporter.WithSystemTopics(*systemTopics),
+	}
+	if *noBrokerStats {
+		exporterOpts = append(exporterOpts, exporter.WithoutBrokerStats())
+	}
+	exp, err := exporter.New(client, exporterOpts...)
+	if err != nil {
+		log.Fatalf("创建导出器失败: %v", err)
+	}
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           exp.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		log.Printf("rocketmq-exporter 监听 %s，NameServer: %s", *listen, *namesrv)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("HTTP 服务异常: %v", err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = server.Shutdown(ctx)
+}
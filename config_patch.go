@@ -0,0 +1,245 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"sync"
+)
+
+// =============================================================================
+// Topic / 订阅组配置补丁
+// =============================================================================
+
+// Ptr 返回 v 的指针，便于构造补丁
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// TopicConfigPatch Topic 配置补丁，为 nil 的字段保持 Broker 上的原值
+type TopicConfigPatch struct {
+	ReadQueueNums   *int    `json:"readQueueNums,omitempty"`
+	WriteQueueNums  *int    `json:"writeQueueNums,omitempty"`
+	Perm            *Perm   `json:"perm,omitempty"`
+	TopicFilterType *string `json:"topicFilterType,omitempty"`
+	TopicSysFlag    *int    `json:"topicSysFlag,omitempty"`
+	Order           *bool   `json:"order,omitempty"`
+
+	// Attributes 合并到 Topic 的原有属性，未列出的属性保持不变
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// apply 将补丁应用到配置上，返回实际变更的字段
+func (p *TopicConfigPatch) apply(config *TopicConfig) []FieldChange {
+	var changes []FieldChange
+	changes = patchField(changes, "readQueueNums", &config.ReadQueueNums, p.ReadQueueNums)
+	changes = patchField(changes, "writeQueueNums", &config.WriteQueueNums, p.WriteQueueNums)
+	changes = patchField(changes, "perm", &config.Perm, p.Perm)
+	changes = patchField(changes, "topicFilterType", &config.TopicFilterType, p.TopicFilterType)
+	changes = patchField(changes, "topicSysFlag", &config.TopicSysFlag, p.TopicSysFlag)
+	changes = patchField(changes, "order", &config.Order, p.Order)
+	changes, config.Attributes = patchAttributes(changes, config.Attributes, p.Attributes)
+	return changes
+}
+
+// SubscriptionGroupConfigPatch 订阅组配置补丁，为 nil 的字段保持 Broker 上的原值
+type SubscriptionGroupConfigPatch struct {
+	ConsumeEnable                  *bool  `json:"consumeEnable,omitempty"`
+	ConsumeFromMinEnable           *bool  `json:"consumeFromMinEnable,omitempty"`
+	ConsumeBroadcastEnable         *bool  `json:"consumeBroadcastEnable,omitempty"`
+	RetryQueueNums                 *int   `json:"retryQueueNums,omitempty"`
+	RetryMaxTimes                  *int   `json:"retryMaxTimes,omitempty"`
+	BrokerId                       *int64 `json:"brokerId,omitempty"`
+	WhichBrokerWhenConsumeSlowly   *int64 `json:"whichBrokerWhenConsumeSlowly,omitempty"`
+	NotifyConsumerIdsChangedEnable *bool  `json:"notifyConsumerIdsChangedEnable,omitempty"`
+	ConsumeMessageOrderly          *bool  `json:"consumeMessageOrderly,omitempty"`
+	ConsumeTimeoutMinute           *int   `json:"consumeTimeoutMinute,omitempty"`
+	GroupSysFlag                   *int   `json:"groupSysFlag,omitempty"`
+
+	// GroupRetryPolicy 替换原有的重试策略
+	GroupRetryPolicy *GroupRetryPolicy `json:"groupRetryPolicy,omitempty"`
+
+	// Attributes 合并到订阅组的原有属性，未列出的属性保持不变
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// apply 将补丁应用到配置上，返回实际变更的字段
+func (p *SubscriptionGroupConfigPatch) apply(config *SubscriptionGroupConfig) []FieldChange {
+	var changes []FieldChange
+	changes = patchField(changes, "consumeEnable", &config.ConsumeEnable, p.ConsumeEnable)
+	changes = patchField(changes, "consumeFromMinEnable", &config.ConsumeFromMinEnable, p.ConsumeFromMinEnable)
+	changes = patchField(changes, "consumeBroadcastEnable", &config.ConsumeBroadcastEnable, p.ConsumeBroadcastEnable)
+	changes = patchField(changes, "retryQueueNums", &config.RetryQueueNums, p.RetryQueueNums)
+	changes = patchField(changes, "retryMaxTimes", &config.RetryMaxTimes, p.RetryMaxTimes)
+	changes = patchField(changes, "brokerId", &config.BrokerId, p.BrokerId)
+	changes = patchField(changes, "whichBrokerWhenConsumeSlowly", &config.WhichBrokerWhenConsumeSlowly, p.WhichBrokerWhenConsumeSlowly)
+	changes = patchField(changes, "notifyConsumerIdsChangedEnable", &config.NotifyConsumerIdsChangedEnable, p.NotifyConsumerIdsChangedEnable)
+	changes = patchField(changes, "consumeMessageOrderly", &config.ConsumeMessageOrderly, p.ConsumeMessageOrderly)
+	changes = patchField(changes, "consumeTimeoutMinute", &config.ConsumeTimeoutMinute, p.ConsumeTimeoutMinute)
+	changes = patchField(changes, "groupSysFlag", &config.GroupSysFlag, p.GroupSysFlag)
+	if p.GroupRetryPolicy != nil && !reflect.DeepEqual(config.GroupRetryPolicy, p.GroupRetryPolicy) {
+		changes = append(changes, FieldChange{Field: "groupRetryPolicy", Old: config.GroupRetryPolicy.String(), New: p.GroupRetryPolicy.String()})
+		config.GroupRetryPolicy = p.GroupRetryPolicy
+	}
+	changes, config.Attributes = patchAttributes(changes, config.Attributes, p.Attributes)
+	return changes
+}
+
+// patchAttributes 将 patch 合并到 attributes，有变更时返回新的属性表，不修改读取到的原配置
+func patchAttributes(changes []FieldChange, attributes, patch map[string]string) ([]FieldChange, map[string]string) {
+	merged := attributes
+	copied := false
+	for _, key := range sortedKeys(patch) {
+		old, ok := attributes[key]
+		if ok && old == patch[key] {
+			continue
+		}
+		if !copied {
+			merged = make(map[string]string, len(attributes)+len(patch))
+			maps.Copy(merged, attributes)
+			copied = true
+		}
+		merged[key] = patch[key]
+		changes = append(changes, FieldChange{Field: "attributes." + key, Old: old, New: patch[key]})
+	}
+	return changes, merged
+}
+
+// patchField value 不为 nil 且与原值不同时修改字段并记录变更
+func patchField[V comparable](changes []FieldChange, field string, target *V, value *V) []FieldChange {
+	if value == nil {
+		return changes
+	}
+	changes = appendChange(changes, field, *target, *value)
+	*target = *value
+	return changes
+}
+
+// BrokerPatchResult 单个 Broker 上的补丁结果
+type BrokerPatchResult struct {
+	BrokerName string        `json:"brokerName"`
+	Addr       string        `json:"addr"`
+	Changes    []FieldChange `json:"changes,omitempty"` // 实际修改的字段，为空表示配置已符合补丁
+	Error      string        `json:"error,omitempty"`
+}
+
+// PatchTopicConfig 只修改 Topic 在单个 Broker 上的指定字段
+//
+// 先读取 Broker 上的当前配置并应用补丁，写入前重新读取：配置表的数据版本变化且该 Topic
+// 的配置已被修改时返回 ErrConcurrentModification，不写入。写入后再次读取校验。
+// Topic 不存在时返回 ErrTopicNotFound，补丁不会创建 Topic。
+func (c *Client) PatchTopicConfig(ctx context.Context, brokerAddr, topic string, patch TopicConfigPatch) ([]FieldChange, error) {
+	return patchConfig(ctx, brokerAddr, topic, ErrTopicNotFound, c.topicConfigTable, patch.apply, c.CreateTopic)
+}
+
+// PatchTopicConfigInCluster 在集群全部 Master 上只修改 Topic 的指定字段，clusterName 为空时作用于全部集群
+//
+// 各 Master 独立读取、修改和校验，失败的 Broker 汇总为 BrokerErrors，已成功的 Broker 不回滚。
+func (c *Client) PatchTopicConfigInCluster(ctx context.Context, clusterName, topic string, patch TopicConfigPatch) ([]BrokerPatchResult, error) {
+	return c.patchInCluster(ctx, clusterName, func(ctx context.Context, addr string) ([]FieldChange, error) {
+		return c.PatchTopicConfig(ctx, addr, topic, patch)
+	})
+}
+
+// PatchSubscriptionGroupConfig 只修改订阅组在单个 Broker 上的指定字段
+//
+// 并发修改检测与 PatchTopicConfig 相同；订阅组不存在时返回 ErrConsumerGroupNotFound。
+func (c *Client) PatchSubscriptionGroupConfig(ctx context.Context, brokerAddr, group string, patch SubscriptionGroupConfigPatch) ([]FieldChange, error) {
+	return patchConfig(ctx, brokerAddr, group, ErrConsumerGroupNotFound, c.subscriptionGroupTable, patch.apply, c.CreateSubscriptionGroup)
+}
+
+// PatchSubscriptionGroupConfigInCluster 在集群全部 Master 上只修改订阅组的指定字段，clusterName 为空时作用于全部集群
+func (c *Client) PatchSubscriptionGroupConfigInCluster(ctx context.Context, clusterName, group string, patch SubscriptionGroupConfigPatch) ([]BrokerPatchResult, error) {
+	return c.patchInCluster(ctx, clusterName, func(ctx context.Context, addr string) ([]FieldChange, error) {
+		return c.PatchSubscriptionGroupConfig(ctx, addr, group, patch)
+	})
+}
+
+// patchConfig 读取-修改-写入单个配置项
+func patchConfig[T any](ctx context.Context, addr, name string, notFound error,
+	load func(ctx context.Context, addr string) (map[string]*T, *DataVersion, error),
+	apply func(config *T) []FieldChange,
+	write func(ctx context.Context, addr string, config T) error) ([]FieldChange, error) {
+
+	table, version, err := load(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	current, ok := table[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", notFound, name)
+	}
+	desired := *current
+	changes := apply(&desired)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	// 数据版本未变化时配置表没有被修改；变化时只在目标配置本身被修改时视为冲突
+	table, latest, err := load(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil || latest == nil || *latest != *version {
+		if !reflect.DeepEqual(table[name], current) {
+			return nil, fmt.Errorf("%w: %s 在读取后被修改", ErrConcurrentModification, name)
+		}
+	}
+
+	if err := write(ctx, addr, desired); err != nil {
+		return nil, err
+	}
+	table, _, err = load(ctx, addr)
+	if err != nil {
+		return changes, fmt.Errorf("校验失败: %w", err)
+	}
+	if !reflect.DeepEqual(table[name], &desired) {
+		return changes, fmt.Errorf("校验失败: %s 写入后的配置与补丁不一致", name)
+	}
+	return changes, nil
+}
+
+// patchInCluster 在集群全部 Master 上并行执行补丁
+func (c *Client) patchInCluster(ctx context.Context, clusterName string, fn func(ctx context.Context, addr string) ([]FieldChange, error)) ([]BrokerPatchResult, error) {
+	clusterInfo, err := c.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取集群信息失败: %w", err)
+	}
+	masters, err := planBrokers(clusterInfo, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return patchOnBrokers(ctx, masters, fn)
+}
+
+// patchOnBrokers 在指定 Master 上并行执行补丁，key: brokerName, value: 地址
+func patchOnBrokers(ctx context.Context, masters map[string]string, fn func(ctx context.Context, addr string) ([]FieldChange, error)) ([]BrokerPatchResult, error) {
+	names := sortedKeys(masters)
+	results := make([]BrokerPatchResult, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		results[i] = BrokerPatchResult{BrokerName: name, Addr: masters[name]}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i].Changes, errs[i] = fn(ctx, masters[name])
+			if errs[i] != nil {
+				results[i].Error = errs[i].Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	var brokerErrs BrokerErrors
+	for i, r := range results {
+		if errs[i] != nil {
+			brokerErrs = append(brokerErrs, &BrokerError{BrokerName: r.BrokerName, Addr: r.Addr, Err: errs[i]})
+		}
+	}
+	if len(brokerErrs) > 0 {
+		return results, brokerErrs
+	}
+	return results, nil
+}
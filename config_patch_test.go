@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// 配置补丁单元测试
+// =============================================================================
+
+// TestPatchTopicConfig 测试只修改指定字段并检测并发修改
+func TestPatchTopicConfig(t *testing.T) {
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+	var (
+		mu      sync.Mutex
+		topic   = &TopicConfig{TopicName: "TopicA", ReadQueueNums: 8, WriteQueueNums: 8, Perm: 6, TopicFilterType: "SINGLE_TAG", Order: true}
+		version = DataVersion{Timestamp: 1, Counter: 1}
+		reads   int
+		racer   int // 第 racer 次读取前模拟其他客户端修改 TopicA
+	)
+	srv.Handle(remoting.GetAllTopicConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		mu.Lock()
+		defer mu.Unlock()
+		if reads++; reads == racer {
+			topic = &TopicConfig{TopicName: "TopicA", ReadQueueNums: 16, WriteQueueNums: 16, Perm: 6}
+			version.Counter++
+		}
+		return remotingtest.JSON(map[string]any{"topicConfigTable": map[string]any{"TopicA": topic}, "dataVersion": version})
+	})
+	srv.Handle(remoting.UpdateAndCreateTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		mu.Lock()
+		defer mu.Unlock()
+		f := req.ExtFields
+		topic = &TopicConfig{TopicName: f["topic"], ReadQueueNums: atoi(f["readQueueNums"]), WriteQueueNums: atoi(f["writeQueueNums"]),
+			Perm: Perm(atoi(f["perm"])), TopicFilterType: f["topicFilterType"], TopicSysFlag: atoi(f["topicSysFlag"]), Order: f["order"] == "true"}
+		version.Counter++
+		return remotingtest.Success(nil)
+	})
+	client := newFakeClient(t, srv)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	changes, err := client.PatchTopicConfig(ctx, srv.Addr, "TopicA", TopicConfigPatch{Perm: Ptr(PermRead)})
+	if err != nil {
+		t.Fatalf("修改 Topic 配置失败: %v", err)
+	}
+	if len(changes) != 1 || changes[0] != (FieldChange{Field: "perm", Old: "RW-", New: "R--"}) {
+		t.Errorf("应只修改 perm: %+v", changes)
+	}
+	if w := srv.Requests(remoting.UpdateAndCreateTopic)[0].ExtFields; w["readQueueNums"] != "8" || w["order"] != "true" {
+		t.Errorf("未指定的字段应保持原值: %v", w)
+	}
+
+	// 配置已符合补丁时不写入
+	if changes, err := client.PatchTopicConfig(ctx, srv.Addr, "TopicA", TopicConfigPatch{Perm: Ptr(PermRead)}); err != nil || changes != nil {
+		t.Errorf("无变更时不应写入: %v %+v", err, changes)
+	}
+
+	// 读取后 TopicA 被其他客户端修改
+	mu.Lock()
+	reads, racer = 0, 2
+	mu.Unlock()
+	if _, err := client.PatchTopicConfig(ctx, srv.Addr, "TopicA", TopicConfigPatch{Perm: Ptr(PermReadWrite)}); !errors.Is(err, ErrConcurrentModification) {
+		t.Errorf("应检测到并发修改, got %v", err)
+	}
+	if n := len(srv.Requests(remoting.UpdateAndCreateTopic)); n != 1 {
+		t.Errorf("检测到并发修改时不应写入, got %d 次", n)
+	}
+	if _, err := client.PatchTopicConfig(ctx, srv.Addr, "TopicB", TopicConfigPatch{Perm: Ptr(PermReadWrite)}); !errors.Is(err, ErrTopicNotFound) {
+		t.Errorf("不存在的 Topic 应返回 ErrTopicNotFound, got %v", err)
+	}
+}
+
+// TestPatchSubscriptionGroupConfigInCluster 测试集群级修改订阅组并保留其他字段
+func TestPatchSubscriptionGroupConfigInCluster(t *testing.T) {
+	a, _, b, c := newGroupCluster(t)
+	for _, broker := range []*groupBroker{a, b, c} {
+		broker.groups["GroupA"] = &SubscriptionGroupConfig{GroupName: "GroupA", ConsumeEnable: true, ConsumeBroadcastEnable: true,
+			RetryQueueNums: 1, RetryMaxTimes: 3, WhichBrokerWhenConsumeSlowly: 1}
+	}
+	client := newFakeClient(t, a.Server)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	// broker-b 会把 retryMaxTimes 限制为 3，写入后校验失败
+	results, err := client.PatchSubscriptionGroupConfigInCluster(ctx, "DefaultCluster", "GroupA", SubscriptionGroupConfigPatch{RetryMaxTimes: Ptr(10)})
+	var brokerErrs BrokerErrors
+	if !errors.As(err, &brokerErrs) || len(brokerErrs) != 1 || brokerErrs[0].BrokerName != "broker-b" {
+		t.Fatalf("broker-b 应校验失败, got %v", err)
+	}
+	if len(results) != 3 || len(results[0].Changes) != 1 || results[2].Error != "" {
+		t.Errorf("修改结果错误: %+v", results)
+	}
+
+	// 禁止消费只修改 consumeEnable
+	if _, err := client.UpdateAndGetGroupReadForbidden(ctx, c.Addr, "GroupA", "", true); err != nil {
+		t.Fatalf("禁止消费失败: %v", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if got := c.groups["GroupA"]; got.ConsumeEnable || !got.ConsumeBroadcastEnable || got.RetryMaxTimes != 10 || got.WhichBrokerWhenConsumeSlowly != 1 {
+		t.Errorf("其他字段应保持不变: %+v", got)
+	}
+}
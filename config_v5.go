@@ -0,0 +1,230 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// RocketMQ 5.x Topic 属性
+// =============================================================================
+
+// Topic 属性名称
+const (
+	TopicAttributeMessageType = "message.type" // 消息类型，见 TopicMessageType
+	TopicAttributeQueueType   = "queue.type"   // 队列类型，见 TopicQueueType
+)
+
+// TopicMessageType Topic 消息类型
+type TopicMessageType string
+
+const (
+	MessageTypeUnspecified TopicMessageType = "UNSPECIFIED"
+	MessageTypeNormal      TopicMessageType = "NORMAL"
+	MessageTypeFIFO        TopicMessageType = "FIFO"
+	MessageTypeDelay       TopicMessageType = "DELAY"
+	MessageTypeTransaction TopicMessageType = "TRANSACTION"
+	MessageTypeMixed       TopicMessageType = "MIXED"
+)
+
+// TopicQueueType Topic 的 ConsumeQueue 类型
+type TopicQueueType string
+
+const (
+	QueueTypeSimple TopicQueueType = "SimpleCQ"
+	QueueTypeBatch  TopicQueueType = "BatchCQ"
+)
+
+// WithAttribute 设置 Topic 属性
+func (c *TopicConfig) WithAttribute(key, value string) *TopicConfig {
+	if c.Attributes == nil {
+		c.Attributes = make(map[string]string)
+	}
+	c.Attributes[key] = value
+	return c
+}
+
+// WithMessageType 设置 Topic 的消息类型
+func (c *TopicConfig) WithMessageType(t TopicMessageType) *TopicConfig {
+	return c.WithAttribute(TopicAttributeMessageType, string(t))
+}
+
+// WithQueueType 设置 Topic 的 ConsumeQueue 类型
+func (c *TopicConfig) WithQueueType(t TopicQueueType) *TopicConfig {
+	return c.WithAttribute(TopicAttributeQueueType, string(t))
+}
+
+// MessageType 返回 Topic 的消息类型，未设置时与 Broker 一致视为 NORMAL
+func (c *TopicConfig) MessageType() TopicMessageType {
+	if t, ok := c.Attributes[TopicAttributeMessageType]; ok {
+		return TopicMessageType(t)
+	}
+	return MessageTypeNormal
+}
+
+// attributesModification 将属性编码为 UPDATE_AND_CREATE_TOPIC 请求头格式，如 "+message.type=FIFO,+queue.type=BatchCQ"
+func attributesModification(attributes map[string]string) string {
+	parts := make([]string, 0, len(attributes))
+	for _, key := range sortedKeys(attributes) {
+		parts = append(parts, "+"+key+"="+attributes[key])
+	}
+	return strings.Join(parts, ",")
+}
+
+// =============================================================================
+// RocketMQ 5.x 订阅组重试策略
+// =============================================================================
+
+// GroupRetryPolicyType 重试策略类型
+type GroupRetryPolicyType string
+
+const (
+	RetryPolicyExponential GroupRetryPolicyType = "EXPONENTIAL" // 指数退避
+	RetryPolicyCustomized  GroupRetryPolicyType = "CUSTOMIZED"  // 按自定义间隔列表
+)
+
+// ExponentialRetryPolicy 指数退避重试策略，时间单位为毫秒
+type ExponentialRetryPolicy struct {
+	Initial    int64 `json:"initial"`    // 首次重试间隔
+	Max        int64 `json:"max"`        // 最大重试间隔
+	Multiplier int64 `json:"multiplier"` // 每次重试间隔的倍数
+}
+
+// CustomizedRetryPolicy 自定义重试策略，Next 为各级重试间隔（毫秒）
+type CustomizedRetryPolicy struct {
+	Next []int64 `json:"next"`
+}
+
+// GroupRetryPolicy 订阅组重试策略
+type GroupRetryPolicy struct {
+	Type                   GroupRetryPolicyType    `json:"type"`
+	ExponentialRetryPolicy *ExponentialRetryPolicy `json:"exponentialRetryPolicy,omitempty"`
+	CustomizedRetryPolicy  *CustomizedRetryPolicy  `json:"customizedRetryPolicy,omitempty"`
+}
+
+// NewExponentialRetryPolicy 创建指数退避重试策略：第 n 次重试间隔为 initial * multiplier^n，不超过 maxDelay
+func NewExponentialRetryPolicy(initial, maxDelay time.Duration, multiplier int64) *GroupRetryPolicy {
+	return &GroupRetryPolicy{
+		Type: RetryPolicyExponential,
+		ExponentialRetryPolicy: &ExponentialRetryPolicy{
+			Initial:    initial.Milliseconds(),
+			Max:        maxDelay.Milliseconds(),
+			Multiplier: multiplier,
+		},
+	}
+}
+
+// NewCustomizedRetryPolicy 创建自定义重试策略
+//
+// 与 Broker 的延迟等级一致，第 n 次重试使用 delays[n+2]，超出列表时使用最后一个间隔，
+// 即前两个间隔保留给延迟等级 1、2。
+func NewCustomizedRetryPolicy(delays ...time.Duration) *GroupRetryPolicy {
+	next := make([]int64, len(delays))
+	for i, d := range delays {
+		next[i] = d.Milliseconds()
+	}
+	return &GroupRetryPolicy{
+		Type:                  RetryPolicyCustomized,
+		CustomizedRetryPolicy: &CustomizedRetryPolicy{Next: next},
+	}
+}
+
+// NextDelay 返回第 reconsumeTimes 次重试（从 0 开始）的间隔，计算方式与 Broker 一致
+func (p *GroupRetryPolicy) NextDelay(reconsumeTimes int) time.Duration {
+	reconsumeTimes = max(reconsumeTimes, 0)
+	switch {
+	case p.Type == RetryPolicyExponential && p.ExponentialRetryPolicy != nil:
+		e := p.ExponentialRetryPolicy
+		delay := float64(e.Initial) * math.Pow(float64(e.Multiplier), float64(min(reconsumeTimes, 32)))
+		return time.Duration(min(delay, float64(e.Max))) * time.Millisecond
+	case p.CustomizedRetryPolicy != nil && len(p.CustomizedRetryPolicy.Next) > 0:
+		next := p.CustomizedRetryPolicy.Next
+		return time.Duration(next[min(reconsumeTimes+2, len(next)-1)]) * time.Millisecond
+	default:
+		return 0
+	}
+}
+
+// String 返回重试策略的简要描述，如 "EXPONENTIAL(1s, x2, max 2h0m0s)"
+func (p *GroupRetryPolicy) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	switch {
+	case p.ExponentialRetryPolicy != nil && p.Type == RetryPolicyExponential:
+		e := p.ExponentialRetryPolicy
+		return fmt.Sprintf("%s(%v, x%d, max %v)", p.Type, time.Duration(e.Initial)*time.Millisecond, e.Multiplier, time.Duration(e.Max)*time.Millisecond)
+	case p.CustomizedRetryPolicy != nil:
+		delays := make([]string, len(p.CustomizedRetryPolicy.Next))
+		for i, ms := range p.CustomizedRetryPolicy.Next {
+			delays[i] = (time.Duration(ms) * time.Millisecond).String()
+		}
+		return fmt.Sprintf("%s(%s)", p.Type, strings.Join(delays, " "))
+	default:
+		return string(p.Type)
+	}
+}
+
+// =============================================================================
+// 未知字段
+// =============================================================================
+
+// unknownFields 返回 data 中 v 的结构体未定义的字段，没有时返回 nil
+func unknownFields(data []byte, v any) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		delete(fields, name)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// marshalWithExtra 编码 v，并追加 v 未定义的 extra 字段
+func marshalWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range extra {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// decodeConfigTable 解析 Broker 返回的配置表，并通过 setExtra 保存每项配置的未知字段
+func decodeConfigTable[T any](table map[string]json.RawMessage, setExtra func(*T, map[string]json.RawMessage)) (map[string]*T, error) {
+	configs := make(map[string]*T, len(table))
+	for _, name := range sortedKeys(table) {
+		config := new(T)
+		if err := json.Unmarshal(table[name], config); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		extra, err := unknownFields(table[name], config)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		setExtra(config, extra)
+		configs[name] = config
+	}
+	return configs, nil
+}
@@ -0,0 +1,124 @@
+package admin
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// RocketMQ 5.x 配置字段单元测试
+// =============================================================================
+
+// TestGroupRetryPolicy 测试重试策略的构造、间隔计算与 JSON 格式
+func TestGroupRetryPolicy(t *testing.T) {
+	exponential := NewExponentialRetryPolicy(time.Second, 10*time.Second, 2)
+	for times, want := range map[int]time.Duration{-1: time.Second, 0: time.Second, 3: 8 * time.Second, 5: 10 * time.Second, 100: 10 * time.Second} {
+		if got := exponential.NextDelay(times); got != want {
+			t.Errorf("指数退避第 %d 次重试间隔应为 %v, got %v", times, want, got)
+		}
+	}
+	data, _ := json.Marshal(exponential)
+	if string(data) != `{"type":"EXPONENTIAL","exponentialRetryPolicy":{"initial":1000,"max":10000,"multiplier":2}}` {
+		t.Errorf("JSON 格式错误: %s", data)
+	}
+
+	customized := NewCustomizedRetryPolicy(time.Second, 5*time.Second, 10*time.Second, 30*time.Second)
+	if customized.NextDelay(0) != 10*time.Second || customized.NextDelay(5) != 30*time.Second {
+		t.Errorf("自定义策略应从第三个间隔开始: %v %v", customized.NextDelay(0), customized.NextDelay(5))
+	}
+	if got := customized.String(); got != "CUSTOMIZED(1s 5s 10s 30s)" {
+		t.Errorf("String() = %s", got)
+	}
+
+	config := (&TopicConfig{TopicName: "TopicA"}).WithMessageType(MessageTypeFIFO).WithQueueType(QueueTypeBatch)
+	if config.MessageType() != MessageTypeFIFO || (&TopicConfig{}).MessageType() != MessageTypeNormal {
+		t.Errorf("消息类型错误: %v", config.Attributes)
+	}
+	if got := attributesModification(config.Attributes); got != "+message.type=FIFO,+queue.type=BatchCQ" {
+		t.Errorf("属性请求头格式错误: %s", got)
+	}
+}
+
+// TestPatchConfig_PreservesUnknownFields 测试修改配置时保留 5.x 字段与未知字段
+func TestPatchConfig_PreservesUnknownFields(t *testing.T) {
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+	var (
+		mu    sync.Mutex
+		group = json.RawMessage(`{"groupName":"GroupA","consumeEnable":true,"retryMaxTimes":16,"consumeTimeoutMinute":15,` +
+			`"groupRetryPolicy":{"type":"CUSTOMIZED","customizedRetryPolicy":{"next":[1000,5000,10000]}},` +
+			`"subscriptionDataSet":[{"topic":"TopicA","expression":"*"}]}`)
+		topic = map[string]any{"topicName": "TopicA", "readQueueNums": 8, "writeQueueNums": 8, "perm": 6,
+			"attributes": map[string]string{"queue.type": "SimpleCQ"}, "topicQueueMappingInfo": "kept"}
+	)
+	srv.Handle(remoting.GetAllSubscriptionGroup, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		mu.Lock()
+		defer mu.Unlock()
+		return remotingtest.JSON(map[string]any{"subscriptionGroupTable": map[string]any{"GroupA": group}})
+	})
+	srv.Handle(remoting.UpdateAndCreateSubscriptionGroup, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		mu.Lock()
+		defer mu.Unlock()
+		group = append(json.RawMessage(nil), req.Body...)
+		return remotingtest.Success(nil)
+	})
+	srv.Handle(remoting.GetAllTopicConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		mu.Lock()
+		defer mu.Unlock()
+		return remotingtest.JSON(map[string]any{"topicConfigTable": map[string]any{"TopicA": topic}})
+	})
+	srv.Handle(remoting.UpdateAndCreateTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		mu.Lock()
+		defer mu.Unlock()
+		attributes := make(map[string]string)
+		for _, item := range strings.Split(req.ExtFields["attributes"], ",") {
+			key, value, _ := strings.Cut(strings.TrimPrefix(item, "+"), "=")
+			attributes[key] = value
+		}
+		topic["perm"], topic["attributes"] = atoi(req.ExtFields["perm"]), attributes
+		return remotingtest.Success(nil)
+	})
+	client := newFakeClient(t, srv)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	patch := SubscriptionGroupConfigPatch{ConsumeEnable: Ptr(false), GroupRetryPolicy: NewExponentialRetryPolicy(time.Second, time.Minute, 2)}
+	changes, err := client.PatchSubscriptionGroupConfig(ctx, srv.Addr, "GroupA", patch)
+	if err != nil {
+		t.Fatalf("修改订阅组失败: %v", err)
+	}
+	if len(changes) != 2 || changes[1].Old != "CUSTOMIZED(1s 5s 10s)" || changes[1].New != "EXPONENTIAL(1s, x2, max 1m0s)" {
+		t.Errorf("变更错误: %+v", changes)
+	}
+	var written map[string]any
+	if err := json.Unmarshal(srv.Requests(remoting.UpdateAndCreateSubscriptionGroup)[0].Body, &written); err != nil {
+		t.Fatalf("请求体应为订阅组 JSON: %v", err)
+	}
+	if written["consumeEnable"] != false || written["consumeTimeoutMinute"] != 15.0 || written["retryMaxTimes"] != 16.0 || written["subscriptionDataSet"] == nil {
+		t.Errorf("请求体应保留原有字段: %v", written)
+	}
+
+	changes, err = client.PatchTopicConfig(ctx, srv.Addr, "TopicA", TopicConfigPatch{Perm: Ptr(PermRead), Attributes: map[string]string{TopicAttributeMessageType: string(MessageTypeFIFO)}})
+	if err != nil {
+		t.Fatalf("修改 Topic 失败: %v", err)
+	}
+	if len(changes) != 2 || changes[1] != (FieldChange{Field: "attributes.message.type", New: "FIFO"}) {
+		t.Errorf("变更错误: %+v", changes)
+	}
+	if got := srv.Requests(remoting.UpdateAndCreateTopic)[0].ExtFields["attributes"]; got != "+message.type=FIFO,+queue.type=SimpleCQ" {
+		t.Errorf("应保留原有属性: %s", got)
+	}
+	configs, err := client.GetAllTopicConfig(ctx, srv.Addr)
+	if err != nil {
+		t.Fatalf("读取 Topic 配置失败: %v", err)
+	}
+	if extra := configs["TopicA"].Extra; string(extra["topicQueueMappingInfo"]) != `"kept"` {
+		t.Errorf("应保存未知字段: %v", extra)
+	}
+}
@@ -3,7 +3,12 @@ package admin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
 )
@@ -27,6 +32,12 @@ func (c *Client) CreateSubscriptionGroup(ctx context.Context, addr string, confi
 	}
 
 	cmd := remoting.NewRequest(remoting.UpdateAndCreateSubscriptionGroup, extFields)
+	// Broker 以请求体中的完整配置为准，请求体携带 5.x 字段与读取时保留的未知字段
+	body, err := marshalWithExtra(config, config.Extra)
+	if err != nil {
+		return fmt.Errorf("序列化订阅组配置失败: %w", err)
+	}
+	cmd.Body = body
 
 	resp, err := c.invokeBroker(ctx, addr, cmd)
 	if err != nil {
@@ -79,6 +90,9 @@ func (c *Client) ExamineSubscriptionGroupConfig(ctx context.Context, addr, group
 	if err := json.Unmarshal(resp.Body, &config); err != nil {
 		return nil, fmt.Errorf("解析订阅组配置失败: %w", err)
 	}
+	if config.Extra, err = unknownFields(resp.Body, &config); err != nil {
+		return nil, fmt.Errorf("解析订阅组配置失败: %w", err)
+	}
 
 	return &config, nil
 }
@@ -121,8 +135,11 @@ func (c *Client) ExamineConsumeStats(ctx context.Context, consumerGroup string)
 			continue
 		}
 
+		// 修复 RocketMQ 返回的非标准 JSON（MessageQueue 作为 key）
+		fixedBody := fixJSONBody(resp.Body)
+
 		var stats ConsumeStats
-		if err := json.Unmarshal(resp.Body, &stats); err != nil {
+		if err := json.Unmarshal(fixedBody, &stats); err != nil {
 			continue
 		}
 
@@ -190,60 +207,261 @@ func (c *Client) ExamineConsumerConnectionInfo(ctx context.Context, consumerGrou
 // =============================================================================
 
 // ResetOffsetByTimestamp 按时间戳重置消费位点
-func (c *Client) ResetOffsetByTimestamp(ctx context.Context, topic, group string, timestamp int64, force bool) (map[MessageQueue]int64, error) {
-	// 获取 Topic 路由信息
+//
+// 在 Topic 的全部 Master 上并行执行，由 Broker 计算各队列的新 Offset 并通知在线
+// 客户端，因此消费组必须在线；不在线时 Broker 返回 ConsumerNotOnline，可改用
+// ResetOffsetByTimestampOld。force 为 false 时 Broker 不会回退已消费的位点。
+// 参数顺序与 Java resetOffsetByTimestamp 一致为 topic、group。
+//
+// 返回成功 Broker 上各队列的新旧 Offset；部分 Broker 失败时同时返回 BrokerErrors。
+func (c *Client) ResetOffsetByTimestamp(ctx context.Context, topic, group string, timestamp int64, force bool) ([]QueueOffsetResult, error) {
+	return c.resetOnMasters(ctx, topic, func(ctx context.Context, brokerName, addr string) ([]QueueOffsetResult, error) {
+		previous := make(map[MessageQueue]int64)
+		if stats, err := c.brokerConsumeStats(ctx, addr, group, topic); err == nil {
+			for mq, wrapper := range stats {
+				previous[mq] = wrapper.ConsumerOffset
+			}
+		}
+
+		offsets, err := c.invokeBrokerToResetOffset(ctx, addr, topic, group, timestamp, force)
+		if err != nil {
+			return nil, err
+		}
+		results := make([]QueueOffsetResult, 0, len(offsets))
+		for mq, offset := range offsets {
+			result := QueueOffsetResult{MessageQueue: mq, OldOffset: -1, NewOffset: offset}
+			if old, ok := previous[mq]; ok {
+				result.OldOffset = old
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	})
+}
+
+// ResetOffsetByTimestampOld 按时间戳直接改写已提交的消费位点，适用于消费组不在线
+//
+// 各 Master 上按时间戳查询每个队列的 Offset 后通过 UpdateConsumeOffset 写入；
+// force 为 false 时只回退，不会跳过未消费的消息，此时跳过的队列 NewOffset 等于
+// OldOffset。消费组在线时客户端会用内存中的进度覆盖写入结果。
+func (c *Client) ResetOffsetByTimestampOld(ctx context.Context, group, topic string, timestamp int64, force bool) ([]QueueOffsetResult, error) {
+	return c.resetOnMasters(ctx, topic, func(ctx context.Context, brokerName, addr string) ([]QueueOffsetResult, error) {
+		stats, err := c.brokerConsumeStats(ctx, addr, group, topic)
+		if err != nil {
+			return nil, err
+		}
+
+		results := make([]QueueOffsetResult, 0, len(stats))
+		for mq, wrapper := range stats {
+			result := QueueOffsetResult{MessageQueue: mq, OldOffset: wrapper.ConsumerOffset, NewOffset: wrapper.ConsumerOffset}
+			offset, err := c.SearchOffset(ctx, addr, mq.Topic, mq.QueueId, timestamp)
+			switch {
+			case err != nil:
+				result.Error = err.Error()
+			case force || offset <= wrapper.ConsumerOffset:
+				if err := c.UpdateConsumeOffset(ctx, addr, group, mq.Topic, mq.QueueId, offset); err != nil {
+					result.Error = err.Error()
+				} else {
+					result.NewOffset = offset
+				}
+			}
+			results = append(results, result)
+		}
+		return results, nil
+	})
+}
+
+// ResetOffsetNew 强制按时间戳重置消费位点，消费组不在线时改用 ResetOffsetByTimestampOld
+func (c *Client) ResetOffsetNew(ctx context.Context, group, topic string, timestamp int64) ([]QueueOffsetResult, error) {
+	results, err := c.ResetOffsetByTimestamp(ctx, topic, group, timestamp, true)
+	if IsResponseCode(err, remoting.ConsumerNotOnline) {
+		return c.ResetOffsetByTimestampOld(ctx, group, topic, timestamp, true)
+	}
+	return results, err
+}
+
+// ResetOffsetByQueueId 将消费组在单个队列上的 Offset 重置为 resetOffset
+//
+// 先通过 UpdateConsumeOffset 写入已提交的 Offset，再请求 Broker 通知在线客户端；
+// 消费组不在线时 Broker 返回的 ConsumerNotOnline 被忽略。按队列通知需要开启
+// useServerSideResetOffset 的 5.x Broker。
+//
+// 不支持按队列重置的 Broker 会忽略 queueId 和 offset，按 timestamp 重置整个 Topic。
+// 因此请求携带当前时间且不强制，这类 Broker 只会按已提交的 Offset 通知各队列，
+// 不会回退整个 Topic；响应中出现其他队列时返回 ErrResetByQueueUnsupported。
+func (c *Client) ResetOffsetByQueueId(ctx context.Context, brokerAddr, consumerGroup, topic string, queueId int, resetOffset int64) error {
+	if err := c.UpdateConsumeOffset(ctx, brokerAddr, consumerGroup, topic, queueId, resetOffset); err != nil {
+		return err
+	}
+
+	extFields := map[string]string{
+		"topic":     topic,
+		"group":     consumerGroup,
+		"timestamp": fmt.Sprintf("%d", time.Now().UnixMilli()),
+		"isForce":   "false",
+		"queueId":   fmt.Sprintf("%d", queueId),
+		"offset":    fmt.Sprintf("%d", resetOffset),
+	}
+	cmd := remoting.NewRequest(remoting.ResetOffsetByQueueId, extFields)
+
+	resp, err := c.invokeBroker(ctx, brokerAddr, cmd)
+	if err != nil {
+		return err
+	}
+	if resp.Code == remoting.ConsumerNotOnline {
+		return nil
+	}
+	if resp.Code != remoting.Success {
+		return NewAdminError(resp.Code, resp.Remark)
+	}
+
+	// 响应体为 ResetOffsetBody，支持按队列重置的 Broker 只返回目标队列
+	var body struct {
+		OffsetTable map[string]int64 `json:"offsetTable"`
+	}
+	if len(resp.Body) > 0 {
+		if err := json.Unmarshal(fixJSONBody(resp.Body), &body); err != nil {
+			return fmt.Errorf("解析重置结果失败: %w", err)
+		}
+	}
+	for key := range body.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil {
+			return err
+		}
+		if mq.Topic != topic || mq.QueueId != queueId {
+			return fmt.Errorf("%w: %s 按时间重置了 %s 的全部队列", ErrResetByQueueUnsupported, brokerAddr, topic)
+		}
+	}
+	return nil
+}
+
+// resetOnMasters 在 Topic 的全部 Master 上并行执行 fn，结果按队列排序
+//
+// 队列级别的失败记录在 QueueOffsetResult.Error 中，Broker 级别的失败汇总为 BrokerErrors；
+// 两者任一存在时返回错误，已成功的结果同时返回。
+func (c *Client) resetOnMasters(ctx context.Context, topic string,
+	fn func(ctx context.Context, brokerName, addr string) ([]QueueOffsetResult, error)) ([]QueueOffsetResult, error) {
+
 	routeData, err := c.ExamineTopicRouteInfo(ctx, topic)
 	if err != nil {
 		return nil, err
 	}
+	masters := masterAddrs(routeData)
+	if len(masters) == 0 {
+		return nil, fmt.Errorf("%w: Topic %s 没有可用的 Master", ErrBrokerNotFound, topic)
+	}
+
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		results    []QueueOffsetResult
+		brokerErrs BrokerErrors
+	)
+	for brokerName, addr := range masters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			queues, err := fn(ctx, brokerName, addr)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				brokerErrs = append(brokerErrs, &BrokerError{BrokerName: brokerName, Addr: addr, Err: err})
+				return
+			}
+			results = append(results, queues...)
+		}()
+	}
+	wg.Wait()
 
-	result := make(map[MessageQueue]int64)
-
-	for _, brokerData := range routeData.BrokerDatas {
-		var brokerAddr string
-		for _, addr := range brokerData.BrokerAddrs {
-			brokerAddr = addr
-			break
+	sort.Slice(results, func(i, j int) bool { return queueLess(results[i].MessageQueue, results[j].MessageQueue) })
+	if len(brokerErrs) > 0 {
+		sort.Slice(brokerErrs, func(i, j int) bool { return brokerErrs[i].BrokerName < brokerErrs[j].BrokerName })
+		return results, brokerErrs
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
 		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("重置消费位点失败: %d/%d 个队列", failed, len(results))
+	}
+	return results, nil
+}
 
-		if brokerAddr == "" {
-			continue
-		}
+// invokeBrokerToResetOffset 请求 Broker 按时间戳重置消费组在 Topic 上的 Offset
+func (c *Client) invokeBrokerToResetOffset(ctx context.Context, addr, topic, group string, timestamp int64, force bool) (map[MessageQueue]int64, error) {
+	extFields := map[string]string{
+		"topic":     topic,
+		"group":     group,
+		"timestamp": fmt.Sprintf("%d", timestamp),
+		"isForce":   fmt.Sprintf("%t", force),
+	}
+	cmd := remoting.NewRequest(remoting.InvokeBrokerToResetOffset, extFields)
 
-		extFields := map[string]string{
-			"topic":     topic,
-			"group":     group,
-			"timestamp": fmt.Sprintf("%d", timestamp),
-			"isForce":   fmt.Sprintf("%t", force),
-		}
-		cmd := remoting.NewRequest(remoting.ResetConsumerOffset, extFields)
+	resp, err := c.invokeBroker(ctx, addr, cmd)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != remoting.Success {
+		return nil, NewAdminError(resp.Code, resp.Remark)
+	}
 
-		resp, err := c.invokeBroker(ctx, brokerAddr, cmd)
+	// 响应体为 ResetOffsetBody，MessageQueue 作为 key
+	var body struct {
+		OffsetTable map[string]int64 `json:"offsetTable"`
+	}
+	if len(resp.Body) > 0 {
+		if err := json.Unmarshal(fixJSONBody(resp.Body), &body); err != nil {
+			return nil, fmt.Errorf("解析重置结果失败: %w", err)
+		}
+	}
+	offsets := make(map[MessageQueue]int64, len(body.OffsetTable))
+	for key, offset := range body.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
 		if err != nil {
-			continue
+			return nil, err
 		}
+		offsets[mq] = offset
+	}
+	return offsets, nil
+}
 
-		if resp.Code != remoting.Success {
-			continue
-		}
+// brokerConsumeStats 获取消费组在单个 Broker 上的消费统计，topic 为空时返回全部 Topic
+func (c *Client) brokerConsumeStats(ctx context.Context, addr, group, topic string) (map[MessageQueue]*OffsetWrapper, error) {
+	extFields := map[string]string{
+		"consumerGroup": group,
+	}
+	if topic != "" {
+		extFields["topic"] = topic
+	}
+	cmd := remoting.NewRequest(remoting.GetConsumeStats, extFields)
 
-		// 解析重置结果
-		var offsetTable map[string]int64
-		if err := json.Unmarshal(resp.Body, &offsetTable); err != nil {
-			continue
-		}
+	resp, err := c.invokeBroker(ctx, addr, cmd)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != remoting.Success {
+		return nil, NewAdminError(resp.Code, resp.Remark)
+	}
 
-		// 转换为 MessageQueue 格式
-		for queueKey, offset := range offsetTable {
-			mq := MessageQueue{
-				Topic:      topic,
-				BrokerName: brokerData.BrokerName,
-			}
-			result[mq] = offset
-			_ = queueKey // 忽略具体队列解析
+	var stats ConsumeStats
+	if err := json.Unmarshal(fixJSONBody(resp.Body), &stats); err != nil {
+		return nil, fmt.Errorf("解析消费统计失败: %w", err)
+	}
+	result := make(map[MessageQueue]*OffsetWrapper, len(stats.OffsetTable))
+	for key, wrapper := range stats.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if topic == "" || mq.Topic == topic {
+			result[mq] = wrapper
 		}
 	}
-
 	return result, nil
 }
 
@@ -306,8 +524,9 @@ func (c *Client) GetConsumerRunningInfo(ctx context.Context, consumerGroup, clie
 			continue
 		}
 
+		// mqTable 以 MessageQueue 作为 key，需要先修复为标准 JSON
 		var runningInfo ConsumerRunningInfo
-		if err := json.Unmarshal(resp.Body, &runningInfo); err != nil {
+		if err := json.Unmarshal(fixJSONBody(resp.Body), &runningInfo); err != nil {
 			continue
 		}
 
@@ -390,25 +609,38 @@ func (c *Client) QueryConsumeTimeSpan(ctx context.Context, topic, consumerGroup
 
 // GetAllSubscriptionGroup 获取所有订阅组
 func (c *Client) GetAllSubscriptionGroup(ctx context.Context, brokerAddr string) (map[string]*SubscriptionGroupConfig, error) {
+	table, _, err := c.subscriptionGroupTable(ctx, brokerAddr)
+	return table, err
+}
+
+// subscriptionGroupTable 获取 Broker 上全部订阅组配置及其数据版本
+func (c *Client) subscriptionGroupTable(ctx context.Context, brokerAddr string) (map[string]*SubscriptionGroupConfig, *DataVersion, error) {
 	cmd := remoting.NewRequest(remoting.GetAllSubscriptionGroup, nil)
 
 	resp, err := c.invokeBroker(ctx, brokerAddr, cmd)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if resp.Code != remoting.Success {
-		return nil, NewAdminError(resp.Code, resp.Remark)
+		return nil, nil, NewAdminError(resp.Code, resp.Remark)
 	}
 
 	var wrapper struct {
-		SubscriptionGroupTable map[string]*SubscriptionGroupConfig `json:"subscriptionGroupTable"`
+		SubscriptionGroupTable map[string]json.RawMessage `json:"subscriptionGroupTable"`
+		DataVersion            *DataVersion               `json:"dataVersion"`
 	}
 	if err := json.Unmarshal(resp.Body, &wrapper); err != nil {
-		return nil, fmt.Errorf("解析订阅组列表失败: %w", err)
+		return nil, nil, fmt.Errorf("解析订阅组列表失败: %w", err)
+	}
+	table, err := decodeConfigTable(wrapper.SubscriptionGroupTable, func(config *SubscriptionGroupConfig, extra map[string]json.RawMessage) {
+		config.Extra = extra
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析订阅组列表失败: %w", err)
 	}
 
-	return wrapper.SubscriptionGroupTable, nil
+	return table, wrapper.DataVersion, nil
 }
 
 // UpdateConsumeOffset 更新消费 Offset
@@ -433,6 +665,31 @@ func (c *Client) UpdateConsumeOffset(ctx context.Context, brokerAddr, consumerGr
 	return nil
 }
 
+// GetAllConsumerOffset 获取 Broker 上全部消费 Offset
+// 返回 key: topic@group, value: queueId -> offset
+func (c *Client) GetAllConsumerOffset(ctx context.Context, brokerAddr string) (map[string]map[int]int64, error) {
+	cmd := remoting.NewRequest(remoting.GetAllConsumerOffset, nil)
+
+	resp, err := c.invokeBroker(ctx, brokerAddr, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != remoting.Success {
+		return nil, NewAdminError(resp.Code, resp.Remark)
+	}
+
+	// queueId 以数字 key 返回，需要先修复
+	var wrapper struct {
+		OffsetTable map[string]map[int]int64 `json:"offsetTable"`
+	}
+	if err := json.Unmarshal(fixJSONBody(resp.Body), &wrapper); err != nil {
+		return nil, fmt.Errorf("解析消费 Offset 失败: %w", err)
+	}
+
+	return wrapper.OffsetTable, nil
+}
+
 // =============================================================================
 // 高级消费者操作 (并发/批量)
 // =============================================================================
@@ -492,37 +749,102 @@ func isSystemGroup(groupName string) bool {
 	return false
 }
 
-// CloneGroupOffset 克隆消费组偏移
-func (c *Client) CloneGroupOffset(ctx context.Context, srcGroup, destGroup, topic string, isOffline bool) error {
-	// 获取源组消费统计
+// CloneGroupOffset 将源消费组的消费进度复制到目标消费组
+//
+// topic 为空时复制全部 Topic。isOffline 为 false 时源消费组必须在线，只复制其
+// 当前订阅的 Topic；为 true 时按 Broker 上已提交的 Offset 复制，适用于源消费组
+// 已下线的场景。重试 Topic 属于源消费组自身，不复制。
+//
+// 目标消费组在线时客户端会用自己的进度覆盖写入的 Offset，除非 force 为 true，
+// 否则返回 ErrConsumerOnline。返回每个队列的修改结果，任一队列失败时同时返回错误。
+func (c *Client) CloneGroupOffset(ctx context.Context, srcGroup, destGroup, topic string, isOffline, force bool) ([]QueueOffsetResult, error) {
+	destConn, err := c.ExamineConsumerConnectionInfo(ctx, destGroup)
+	if err == nil && len(destConn.ConnectionSet) > 0 && !force {
+		return nil, fmt.Errorf("%w: %s 有 %d 个在线客户端", ErrConsumerOnline, destGroup, len(destConn.ConnectionSet))
+	}
+	if err != nil && !errors.Is(err, ErrConsumerGroupNotFound) {
+		return nil, fmt.Errorf("查询目标消费组连接失败: %w", err)
+	}
+
+	// 在线模式只复制源消费组当前订阅的 Topic
+	var subscribed map[string]bool
+	if !isOffline {
+		srcConn, err := c.ExamineConsumerConnectionInfo(ctx, srcGroup)
+		if err != nil {
+			return nil, fmt.Errorf("源消费组 %s 不在线，已下线的消费组请使用 isOffline: %w", srcGroup, err)
+		}
+		subscribed = make(map[string]bool, len(srcConn.SubscriptionTable))
+		for t := range srcConn.SubscriptionTable {
+			subscribed[t] = true
+		}
+	}
+
 	srcStats, err := c.ExamineConsumeStats(ctx, srcGroup)
 	if err != nil {
-		return fmt.Errorf("获取源组消费统计失败: %w", err)
+		return nil, fmt.Errorf("获取源组消费统计失败: %w", err)
+	}
+	destStats, err := c.ExamineConsumeStats(ctx, destGroup)
+	if err != nil {
+		return nil, fmt.Errorf("获取目标组消费统计失败: %w", err)
+	}
+	previous := make(map[MessageQueue]int64, len(destStats.OffsetTable))
+	for key, wrapper := range destStats.OffsetTable {
+		if mq, err := ParseMessageQueueKey(key); err == nil {
+			previous[mq] = wrapper.ConsumerOffset
+		}
 	}
 
-	// 复制偏移到目标组
-	// 注意：这里需要遍历所有 OffsetWrapper，然后调用 UpdateConsumeOffset
-	// 但现有代码中只用了 placeholders。我们保留原样。
+	masters, err := c.brokerMasterAddrs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取集群信息失败: %w", err)
+	}
+
+	var results []QueueOffsetResult
 	for key, wrapper := range srcStats.OffsetTable {
-		// 解析 key 获取 brokerName 和 queueId
-		// key 格式: topic@brokerName@queueId
-		// 简化实现，直接使用现有接口
-		_ = key
-		_ = wrapper
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if (topic != "" && mq.Topic != topic) || (subscribed != nil && !subscribed[mq.Topic]) ||
+			strings.HasPrefix(mq.Topic, RetryGroupTopicPrefix) || wrapper.ConsumerOffset < 0 {
+			continue
+		}
+		result := QueueOffsetResult{MessageQueue: mq, OldOffset: -1, NewOffset: wrapper.ConsumerOffset}
+		if offset, ok := previous[mq]; ok {
+			result.OldOffset = offset
+		}
+		results = append(results, result)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("源消费组 %s 没有可复制的消费进度", srcGroup)
 	}
+	sort.Slice(results, func(i, j int) bool { return queueLess(results[i].MessageQueue, results[j].MessageQueue) })
 
-	return nil
+	failed := 0
+	for i := range results {
+		r := &results[i]
+		addr, ok := masters[r.MessageQueue.BrokerName]
+		if !ok {
+			r.Error = fmt.Sprintf("%v: %s", ErrBrokerNotFound, r.MessageQueue.BrokerName)
+			failed++
+			continue
+		}
+		if err := c.UpdateConsumeOffset(ctx, addr, destGroup, r.MessageQueue.Topic, r.MessageQueue.QueueId, r.NewOffset); err != nil {
+			r.Error = err.Error()
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("复制消费进度失败: %d/%d 个队列", failed, len(results))
+	}
+	return results, nil
 }
 
 // UpdateAndGetGroupReadForbidden 更新并获取组读取禁止状态
 func (c *Client) UpdateAndGetGroupReadForbidden(ctx context.Context, brokerAddr, groupName, topic string, forbid bool) (bool, error) {
-	// 使用更新订阅组的方式
-	config := SubscriptionGroupConfig{
-		GroupName:     groupName,
-		ConsumeEnable: !forbid,
-	}
-
-	if err := c.CreateSubscriptionGroup(ctx, brokerAddr, config); err != nil {
+	// 只修改 consumeEnable，保留订阅组的其他配置
+	patch := SubscriptionGroupConfigPatch{ConsumeEnable: Ptr(!forbid)}
+	if _, err := c.PatchSubscriptionGroupConfig(ctx, brokerAddr, groupName, patch); err != nil {
 		return false, err
 	}
 
@@ -1,9 +1,279 @@
 package admin
 
 import (
+	"errors"
+	"strconv"
 	"testing"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
 )
 
+// =============================================================================
+// 消费者管理接口单元测试（本地模拟 NameServer/Broker）
+// =============================================================================
+
+// newCloneOffsetCluster 启动模拟集群：SrcGroup 订阅 TopicA，已提交 TopicA、TopicB 和重试 Topic 的 Offset
+func newCloneOffsetCluster(t *testing.T, destOnline *bool) *remotingtest.Server {
+	t.Helper()
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+	handleClusterInfo(srv, "broker-a")
+
+	srv.Handle(remoting.GetConsumeStats, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		if req.ExtFields["consumerGroup"] == "DestGroup" {
+			return remotingtest.Success([]byte(`{"offsetTable":{` +
+				`{"brokerName":"broker-a","queueId":0,"topic":"TopicA"}:{"brokerOffset":10,"consumerOffset":2}}}`))
+		}
+		return remotingtest.Success([]byte(`{"offsetTable":{` +
+			`{"brokerName":"broker-a","queueId":1,"topic":"TopicA"}:{"brokerOffset":10,"consumerOffset":7},` +
+			`{"brokerName":"broker-a","queueId":0,"topic":"TopicA"}:{"brokerOffset":10,"consumerOffset":4},` +
+			`{"brokerName":"broker-a","queueId":0,"topic":"TopicB"}:{"brokerOffset":3,"consumerOffset":3},` +
+			`{"brokerName":"broker-a","queueId":0,"topic":"%RETRY%SrcGroup"}:{"brokerOffset":1,"consumerOffset":1}}}`))
+	})
+	srv.Handle(remoting.GetConsumerConnectionList, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		if req.ExtFields["consumerGroup"] == "DestGroup" && !*destOnline {
+			return remotingtest.Error(remoting.ConsumerNotOnline, "the consumer group not online")
+		}
+		return remotingtest.JSON(map[string]any{
+			"connectionSet":     []map[string]any{{"clientId": "10.0.0.3@1"}},
+			"subscriptionTable": map[string]any{"TopicA": map[string]any{"topic": "TopicA", "subString": "*"}},
+		})
+	})
+	srv.Handle(remoting.UpdateConsumeOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success(nil)
+	})
+	return srv
+}
+
+// TestCloneGroupOffset 测试复制消费进度
+func TestCloneGroupOffset(t *testing.T) {
+	destOnline := false
+	srv := newCloneOffsetCluster(t, &destOnline)
+	client := newFakeClient(t, srv)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	// 离线模式复制全部已提交的 Offset，跳过重试 Topic
+	results, err := client.CloneGroupOffset(ctx, "SrcGroup", "DestGroup", "", true, false)
+	if err != nil {
+		t.Fatalf("复制消费进度失败: %v", err)
+	}
+	want := []QueueOffsetResult{
+		{MessageQueue: MessageQueue{Topic: "TopicA", BrokerName: "broker-a", QueueId: 0}, OldOffset: 2, NewOffset: 4},
+		{MessageQueue: MessageQueue{Topic: "TopicA", BrokerName: "broker-a", QueueId: 1}, OldOffset: -1, NewOffset: 7},
+		{MessageQueue: MessageQueue{Topic: "TopicB", BrokerName: "broker-a", QueueId: 0}, OldOffset: -1, NewOffset: 3},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("应复制 %d 个队列, got %+v", len(want), results)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("第 %d 个队列应为 %+v, got %+v", i, want[i], results[i])
+		}
+	}
+	reqs := srv.Requests(remoting.UpdateConsumeOffset)
+	if len(reqs) != 3 || reqs[0].ExtFields["consumerGroup"] != "DestGroup" || reqs[0].ExtFields["commitOffset"] != "4" {
+		t.Errorf("应向目标消费组提交 3 个 Offset: %+v", reqs)
+	}
+
+	// 在线模式只复制源消费组订阅的 Topic
+	if results, err = client.CloneGroupOffset(ctx, "SrcGroup", "DestGroup", "", false, false); err != nil || len(results) != 2 {
+		t.Errorf("在线模式应只复制 TopicA: %+v, %v", results, err)
+	}
+
+	// 按 Topic 过滤
+	if results, err = client.CloneGroupOffset(ctx, "SrcGroup", "DestGroup", "TopicB", true, false); err != nil || len(results) != 1 {
+		t.Errorf("应只复制 TopicB: %+v, %v", results, err)
+	}
+	if _, err = client.CloneGroupOffset(ctx, "SrcGroup", "DestGroup", "TopicC", true, false); err == nil {
+		t.Error("没有可复制的消费进度时应返回错误")
+	}
+
+	// 目标消费组在线时需要强制执行
+	destOnline = true
+	writes := len(srv.Requests(remoting.UpdateConsumeOffset))
+	if _, err = client.CloneGroupOffset(ctx, "SrcGroup", "DestGroup", "", true, false); !errors.Is(err, ErrConsumerOnline) {
+		t.Errorf("目标消费组在线应返回 ErrConsumerOnline, got %v", err)
+	}
+	if n := len(srv.Requests(remoting.UpdateConsumeOffset)); n != writes {
+		t.Errorf("拒绝执行时不应提交 Offset, got %d 次", n-writes)
+	}
+	if _, err = client.CloneGroupOffset(ctx, "SrcGroup", "DestGroup", "", true, true); err != nil {
+		t.Errorf("强制复制失败: %v", err)
+	}
+}
+
+// newResetOffsetCluster 启动两个 Broker：srvA 同时充当 NameServer 和 broker-a，srvB 为 broker-b
+//
+// broker-a 上 GroupA 的 Offset 为 q0=5、q1=8，按时间查询结果为 2；broker-b 上为 q0=4，
+// 按时间查询结果为 6。online 为 false 时 Broker 重置返回 ConsumerNotOnline。
+func newResetOffsetCluster(t *testing.T, online *bool) (srvA, srvB *remotingtest.Server) {
+	t.Helper()
+	srvA, srvB = remotingtest.NewServer(), remotingtest.NewServer()
+	t.Cleanup(srvA.Close)
+	t.Cleanup(srvB.Close)
+
+	srvA.Handle(remoting.GetRouteInfoByTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"queueDatas":[{"brokerName":"broker-a","readQueueNums":2,"writeQueueNums":2,"perm":6},` +
+			`{"brokerName":"broker-b","readQueueNums":1,"writeQueueNums":1,"perm":6}],"brokerDatas":[` +
+			`{"cluster":"DefaultCluster","brokerName":"broker-a","brokerAddrs":{0:"` + srvA.Addr + `"}},` +
+			`{"cluster":"DefaultCluster","brokerName":"broker-b","brokerAddrs":{0:"` + srvB.Addr + `"}}]}`))
+	})
+	srvA.Handle(remoting.GetConsumeStats, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"offsetTable":{` +
+			`{"brokerName":"broker-a","queueId":0,"topic":"TopicA"}:{"brokerOffset":10,"consumerOffset":5},` +
+			`{"brokerName":"broker-a","queueId":1,"topic":"TopicA"}:{"brokerOffset":10,"consumerOffset":8}}}`))
+	})
+	srvB.Handle(remoting.GetConsumeStats, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"offsetTable":{` +
+			`{"brokerName":"broker-b","queueId":0,"topic":"TopicA"}:{"brokerOffset":10,"consumerOffset":4}}}`))
+	})
+	srvA.Handle(remoting.InvokeBrokerToResetOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		if !*online {
+			return remotingtest.Error(remoting.ConsumerNotOnline, "Consumer not online")
+		}
+		return remotingtest.Success([]byte(`{"offsetTable":{` +
+			`{"brokerName":"broker-a","queueId":1,"topic":"TopicA"}:3,{"brokerName":"broker-a","queueId":0,"topic":"TopicA"}:2}}`))
+	})
+	srvB.Handle(remoting.InvokeBrokerToResetOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		if !*online {
+			return remotingtest.Error(remoting.ConsumerNotOnline, "Consumer not online")
+		}
+		return remotingtest.Error(remoting.SystemError, "store error")
+	})
+	for srv, offset := range map[*remotingtest.Server]string{srvA: "2", srvB: "6"} {
+		srv.Handle(remoting.SearchOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+			resp := remotingtest.Success(nil)
+			resp.ExtFields = map[string]string{"offset": offset}
+			return resp
+		})
+		srv.Handle(remoting.UpdateConsumeOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+			return remotingtest.Success(nil)
+		})
+	}
+	return srvA, srvB
+}
+
+// TestResetOffsetByTimestamp 测试由 Broker 按时间重置位点并汇总各 Broker 错误
+func TestResetOffsetByTimestamp(t *testing.T) {
+	online := true
+	srvA, _ := newResetOffsetCluster(t, &online)
+	client := newFakeClient(t, srvA)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	results, err := client.ResetOffsetByTimestamp(ctx, "TopicA", "GroupA", 1700000000000, true)
+	var brokerErrs BrokerErrors
+	if !errors.As(err, &brokerErrs) || len(brokerErrs) != 1 || brokerErrs[0].BrokerName != "broker-b" {
+		t.Fatalf("broker-b 失败应返回 BrokerErrors, got %v", err)
+	}
+	if !IsResponseCode(err, remoting.SystemError) {
+		t.Errorf("应能取出 Broker 响应码: %v", err)
+	}
+	want := []QueueOffsetResult{
+		{MessageQueue: MessageQueue{Topic: "TopicA", BrokerName: "broker-a", QueueId: 0}, OldOffset: 5, NewOffset: 2},
+		{MessageQueue: MessageQueue{Topic: "TopicA", BrokerName: "broker-a", QueueId: 1}, OldOffset: 8, NewOffset: 3},
+	}
+	if len(results) != len(want) || results[0] != want[0] || results[1] != want[1] {
+		t.Errorf("重置结果应为 %+v, got %+v", want, results)
+	}
+	req := srvA.Requests(remoting.InvokeBrokerToResetOffset)[0]
+	if req.ExtFields["group"] != "GroupA" || req.ExtFields["topic"] != "TopicA" || req.ExtFields["timestamp"] != "1700000000000" || req.ExtFields["isForce"] != "true" {
+		t.Errorf("重置请求参数错误: %v", req.ExtFields)
+	}
+}
+
+// TestResetOffsetByTimestampOld 测试消费组不在线时直接改写位点
+func TestResetOffsetByTimestampOld(t *testing.T) {
+	online := false
+	srvA, srvB := newResetOffsetCluster(t, &online)
+	client := newFakeClient(t, srvA)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	// 不强制时 broker-b 的查询结果 6 大于已提交的 4，不会跳过消息
+	results, err := client.ResetOffsetByTimestampOld(ctx, "GroupA", "TopicA", 1700000000000, false)
+	if err != nil {
+		t.Fatalf("重置失败: %v", err)
+	}
+	if len(results) != 3 || results[0].NewOffset != 2 || results[1].NewOffset != 2 || results[2].OldOffset != 4 || results[2].NewOffset != 4 {
+		t.Errorf("不强制重置结果错误: %+v", results)
+	}
+	if n := len(srvB.Requests(remoting.UpdateConsumeOffset)); n != 0 {
+		t.Errorf("broker-b 不应提交 Offset, got %d 次", n)
+	}
+	if n := len(srvA.Requests(remoting.UpdateConsumeOffset)); n != 2 {
+		t.Errorf("broker-a 应提交 2 个 Offset, got %d 次", n)
+	}
+
+	// ResetOffsetNew 在消费组不在线时回退为强制改写
+	results, err = client.ResetOffsetNew(ctx, "GroupA", "TopicA", 1700000000000)
+	if err != nil {
+		t.Fatalf("重置失败: %v", err)
+	}
+	if len(results) != 3 || results[2].NewOffset != 6 {
+		t.Errorf("强制重置结果错误: %+v", results)
+	}
+	if n := len(srvB.Requests(remoting.UpdateConsumeOffset)); n != 1 {
+		t.Errorf("broker-b 应提交 1 个 Offset, got %d 次", n)
+	}
+}
+
+// TestResetOffsetByQueueId 测试按队列重置：携带当前时间且不强制，Broker 不支持时返回错误
+func TestResetOffsetByQueueId(t *testing.T) {
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+	client := newFakeClient(t, srv)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	srv.Handle(remoting.UpdateConsumeOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success(nil)
+	})
+	// 5.x Broker 只返回目标队列
+	srv.Handle(remoting.ResetOffsetByQueueId, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"offsetTable":{{"brokerName":"broker-a","queueId":1,"topic":"TopicA"}:7}}`))
+	})
+
+	before := time.Now().UnixMilli()
+	if err := client.ResetOffsetByQueueId(ctx, srv.Addr, "GroupA", "TopicA", 1, 7); err != nil {
+		t.Fatalf("按队列重置失败: %v", err)
+	}
+	after := time.Now().UnixMilli()
+
+	commit := srv.Requests(remoting.UpdateConsumeOffset)
+	if len(commit) != 1 || commit[0].ExtFields["queueId"] != "1" || commit[0].ExtFields["commitOffset"] != "7" {
+		t.Errorf("应先写入已提交的 Offset: %+v", commit)
+	}
+	req := srv.Requests(remoting.ResetOffsetByQueueId)[0]
+	if req.ExtFields["queueId"] != "1" || req.ExtFields["offset"] != "7" || req.ExtFields["isForce"] != "false" {
+		t.Errorf("重置请求参数错误: %v", req.ExtFields)
+	}
+	// timestamp 为 0 时不支持按队列重置的 Broker 会把整个 Topic 回退到最早位置
+	if ts, _ := strconv.ParseInt(req.ExtFields["timestamp"], 10, 64); ts < before || ts > after {
+		t.Errorf("timestamp 应为当前时间 [%d, %d], got %s", before, after, req.ExtFields["timestamp"])
+	}
+
+	// 旧版 Broker 按时间重置全部队列
+	srv.Handle(remoting.ResetOffsetByQueueId, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"offsetTable":{{"brokerName":"broker-a","queueId":0,"topic":"TopicA"}:20,` +
+			`{"brokerName":"broker-a","queueId":1,"topic":"TopicA"}:7}}`))
+	})
+	if err := client.ResetOffsetByQueueId(ctx, srv.Addr, "GroupA", "TopicA", 1, 7); !errors.Is(err, ErrResetByQueueUnsupported) {
+		t.Errorf("Broker 不支持按队列重置时应返回 ErrResetByQueueUnsupported, got %v", err)
+	}
+
+	// 消费组不在线时只写入已提交的 Offset
+	srv.Handle(remoting.ResetOffsetByQueueId, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Error(remoting.ConsumerNotOnline, "Consumer not online")
+	})
+	if err := client.ResetOffsetByQueueId(ctx, srv.Addr, "GroupA", "TopicA", 1, 7); err != nil {
+		t.Errorf("消费组不在线时不应返回错误: %v", err)
+	}
+}
+
 // =============================================================================
 // 消费者管理接口集成测试
 // =============================================================================
@@ -775,11 +1045,11 @@ func TestIntegration_CloneGroupOffset(t *testing.T) {
 	}
 
 	// 克隆 Offset
-	err = client.CloneGroupOffset(ctx, srcGroup, destGroup, testTopic, false)
+	results, err := client.CloneGroupOffset(ctx, srcGroup, destGroup, testTopic, true, false)
 	if err != nil {
 		t.Logf("克隆 Offset 失败（可能是源组没有消费数据）: %v", err)
 	} else {
-		t.Log("克隆 Offset 成功")
+		t.Logf("克隆 Offset 成功: %d 个队列", len(results))
 	}
 }
 
@@ -0,0 +1,969 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// =============================================================================
+// 声明式集群配置
+// =============================================================================
+
+// ClusterSpec 声明式集群配置，描述 Topic、订阅组、ACL 用户/策略和 KV 的期望状态
+//
+// 某类资源为 nil（配置文件中未出现）时不纳入管理；出现但为空列表时视为
+// 期望该类资源全部不存在，集群中已有的资源都会生成删除动作。
+// KV 只管理配置中列出的命名空间。
+type ClusterSpec struct {
+	// Cluster 目标集群，为空时作用于全部集群的 Master Broker
+	Cluster string `json:"cluster,omitempty"`
+
+	// Topics Topic 列表
+	Topics []TopicSpec `json:"topics,omitempty"`
+
+	// Groups 订阅组列表
+	Groups []GroupSpec `json:"groups,omitempty"`
+
+	// Users ACL 用户列表
+	Users []UserInfo `json:"users,omitempty"`
+
+	// Acls ACL 策略列表
+	Acls []AclInfo `json:"acls,omitempty"`
+
+	// KV KV 配置 key: 命名空间, value: 该命名空间下的全部 KV
+	KV map[string]map[string]string `json:"kv,omitempty"`
+}
+
+// TopicSpec Topic 期望配置，未设置的字段取默认值
+type TopicSpec struct {
+	Name           string `json:"name"`                     // Topic 名称
+	ReadQueueNums  int    `json:"readQueueNums,omitempty"`  // 读队列数，默认 8
+	WriteQueueNums int    `json:"writeQueueNums,omitempty"` // 写队列数，默认 8
+	Perm           Perm   `json:"perm,omitempty"`           // 权限，默认 6（读写）
+	Order          bool   `json:"order,omitempty"`          // 是否顺序消息
+
+	// Attributes Topic 属性（5.x），如 message.type: FIFO；只对比填写的属性
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// GroupSpec 订阅组期望配置，未设置的字段取 Broker 默认值
+type GroupSpec struct {
+	Name                           string `json:"name"`                                     // 订阅组名称
+	ConsumeEnable                  *bool  `json:"consumeEnable,omitempty"`                  // 默认 true
+	ConsumeFromMinEnable           *bool  `json:"consumeFromMinEnable,omitempty"`           // 默认 true
+	ConsumeBroadcastEnable         *bool  `json:"consumeBroadcastEnable,omitempty"`         // 默认 true
+	RetryQueueNums                 int    `json:"retryQueueNums,omitempty"`                 // 默认 1
+	RetryMaxTimes                  int    `json:"retryMaxTimes,omitempty"`                  // 默认 16
+	BrokerId                       int64  `json:"brokerId,omitempty"`                       // 默认 0
+	WhichBrokerWhenConsumeSlowly   int64  `json:"whichBrokerWhenConsumeSlowly,omitempty"`   // 默认 1
+	NotifyConsumerIdsChangedEnable *bool  `json:"notifyConsumerIdsChangedEnable,omitempty"` // 默认 true
+	ConsumeMessageOrderly          bool   `json:"consumeMessageOrderly,omitempty"`          // 是否顺序消费（5.x）
+	ConsumeTimeoutMinute           int    `json:"consumeTimeoutMinute,omitempty"`           // 消费超时（分钟，5.x），默认取 Broker 配置
+
+	// GroupRetryPolicy 重试策略（5.x），为空时不修改
+	GroupRetryPolicy *GroupRetryPolicy `json:"groupRetryPolicy,omitempty"`
+}
+
+// config 返回填充默认值后的 Topic 配置
+func (s TopicSpec) config() *TopicConfig {
+	config := &TopicConfig{
+		TopicName:       s.Name,
+		ReadQueueNums:   s.ReadQueueNums,
+		WriteQueueNums:  s.WriteQueueNums,
+		Perm:            s.Perm,
+		TopicFilterType: "SINGLE_TAG",
+		Order:           s.Order,
+		Attributes:      s.Attributes,
+	}
+	if config.ReadQueueNums == 0 {
+		config.ReadQueueNums = 8
+	}
+	if config.WriteQueueNums == 0 {
+		config.WriteQueueNums = 8
+	}
+	if config.Perm == 0 {
+		config.Perm = PermReadWrite
+	}
+	return config
+}
+
+// config 返回填充默认值后的订阅组配置
+func (s GroupSpec) config() *SubscriptionGroupConfig {
+	boolOr := func(v *bool, def bool) bool {
+		if v == nil {
+			return def
+		}
+		return *v
+	}
+	config := &SubscriptionGroupConfig{
+		GroupName:                      s.Name,
+		ConsumeEnable:                  boolOr(s.ConsumeEnable, true),
+		ConsumeFromMinEnable:           boolOr(s.ConsumeFromMinEnable, true),
+		ConsumeBroadcastEnable:         boolOr(s.ConsumeBroadcastEnable, true),
+		RetryQueueNums:                 s.RetryQueueNums,
+		RetryMaxTimes:                  s.RetryMaxTimes,
+		BrokerId:                       s.BrokerId,
+		WhichBrokerWhenConsumeSlowly:   s.WhichBrokerWhenConsumeSlowly,
+		NotifyConsumerIdsChangedEnable: boolOr(s.NotifyConsumerIdsChangedEnable, true),
+		ConsumeMessageOrderly:          s.ConsumeMessageOrderly,
+		ConsumeTimeoutMinute:           s.ConsumeTimeoutMinute,
+		GroupRetryPolicy:               s.GroupRetryPolicy,
+	}
+	if config.RetryQueueNums == 0 {
+		config.RetryQueueNums = 1
+	}
+	if config.RetryMaxTimes == 0 {
+		config.RetryMaxTimes = 16
+	}
+	if config.WhichBrokerWhenConsumeSlowly == 0 {
+		config.WhichBrokerWhenConsumeSlowly = 1
+	}
+	return config
+}
+
+// ParseClusterSpec 解析 YAML 或 JSON 格式的集群配置
+func ParseClusterSpec(data []byte) (*ClusterSpec, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(trimmed, "{") {
+		// YAML 先转为 JSON，字段名与 JSON 格式保持一致
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("解析 YAML 失败: %w", err)
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("转换 YAML 失败: %w", err)
+		}
+		data = converted
+	}
+
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.DisallowUnknownFields()
+	var spec ClusterSpec
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("解析集群配置失败: %w", err)
+	}
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// LoadClusterSpec 从文件加载集群配置
+func LoadClusterSpec(path string) (*ClusterSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取集群配置失败: %w", err)
+	}
+	return ParseClusterSpec(data)
+}
+
+// validate 检查名称非空且不重复
+func (s *ClusterSpec) validate() error {
+	check := func(kind string, names []string) error {
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			if name == "" {
+				return fmt.Errorf("%s 名称不能为空", kind)
+			}
+			if seen[name] {
+				return fmt.Errorf("%s %s 重复定义", kind, name)
+			}
+			seen[name] = true
+		}
+		return nil
+	}
+
+	var topics, groups, users, subjects []string
+	for _, t := range s.Topics {
+		topics = append(topics, t.Name)
+	}
+	for _, g := range s.Groups {
+		groups = append(groups, g.Name)
+	}
+	for _, u := range s.Users {
+		users = append(users, u.Username)
+	}
+	for _, a := range s.Acls {
+		subjects = append(subjects, a.Subject)
+	}
+	for _, c := range []struct {
+		kind  string
+		names []string
+	}{{"Topic", topics}, {"订阅组", groups}, {"用户", users}, {"ACL", subjects}} {
+		if err := check(c.kind, c.names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// =============================================================================
+// 变更计划
+// =============================================================================
+
+// ResourceKind 资源类型
+type ResourceKind string
+
+const (
+	ResourceKV    ResourceKind = "kv"
+	ResourceTopic ResourceKind = "topic"
+	ResourceGroup ResourceKind = "group"
+	ResourceUser  ResourceKind = "user"
+	ResourceAcl   ResourceKind = "acl"
+)
+
+// resourceOrder 创建/更新的执行顺序，删除按相反顺序执行
+var resourceOrder = map[ResourceKind]int{
+	ResourceKV:    0,
+	ResourceTopic: 1,
+	ResourceGroup: 2,
+	ResourceUser:  3,
+	ResourceAcl:   4,
+}
+
+// ActionOp 变更操作
+type ActionOp string
+
+const (
+	ActionCreate ActionOp = "create"
+	ActionUpdate ActionOp = "update"
+	ActionDelete ActionOp = "delete"
+)
+
+// FieldChange 字段变更
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// ConfigAction 变更动作
+type ConfigAction struct {
+	// ID 动作标识，由动作内容计算，用于断点续执行
+	ID string `json:"id"`
+
+	// Kind 资源类型
+	Kind ResourceKind `json:"kind"`
+
+	// Op 变更操作
+	Op ActionOp `json:"op"`
+
+	// Name 资源名称，KV 为 namespace/key
+	Name string `json:"name"`
+
+	// Brokers 需要变更的 Broker 名称，KV 为空
+	Brokers []string `json:"brokers,omitempty"`
+
+	// Changes 更新时的字段差异
+	Changes []FieldChange `json:"changes,omitempty"`
+
+	// 期望状态，按 Kind 取对应字段
+	Topic     *TopicConfig             `json:"topic,omitempty"`
+	Group     *SubscriptionGroupConfig `json:"group,omitempty"`
+	User      *UserInfo                `json:"user,omitempty"`
+	Acl       *AclInfo                 `json:"acl,omitempty"`
+	Namespace string                   `json:"namespace,omitempty"`
+	Key       string                   `json:"key,omitempty"`
+	Value     string                   `json:"value,omitempty"`
+}
+
+// String 返回动作描述，如 create topic TopicA [broker-a broker-b]
+func (a *ConfigAction) String() string {
+	s := fmt.Sprintf("%s %s %s", a.Op, a.Kind, a.Name)
+	if len(a.Brokers) > 0 {
+		s += fmt.Sprintf(" %v", a.Brokers)
+	}
+	return s
+}
+
+// ConfigPlan 变更计划
+type ConfigPlan struct {
+	// Cluster 目标集群，为空表示全部集群
+	Cluster string `json:"cluster,omitempty"`
+
+	// Brokers 参与计划的 Master 地址 key: brokerName, value: 地址
+	Brokers map[string]string `json:"brokers"`
+
+	// Actions 按执行顺序排列的变更动作
+	Actions []*ConfigAction `json:"actions"`
+}
+
+// Empty 返回计划是否没有变更
+func (p *ConfigPlan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// Count 返回指定操作的动作数量
+func (p *ConfigPlan) Count(op ActionOp) int {
+	n := 0
+	for _, a := range p.Actions {
+		if a.Op == op {
+			n++
+		}
+	}
+	return n
+}
+
+// liveState 各 Broker 上的现有配置 key: brokerName
+type liveState struct {
+	topics map[string]map[string]*TopicConfig
+	groups map[string]map[string]*SubscriptionGroupConfig
+	users  map[string]map[string]*UserInfo
+	acls   map[string]map[string]*AclInfo
+	kv     map[string]map[string]string
+}
+
+// Plan 对比集群配置与集群现状，生成变更计划
+//
+// Topic、订阅组、用户和 ACL 在每个 Master 上分别对比，只有缺失或不一致的
+// Broker 会出现在动作中。系统 Topic、系统订阅组以及 Broker 自动创建的
+// 集群/Broker 同名 Topic 不会生成删除动作。
+func (c *Client) Plan(ctx context.Context, spec *ClusterSpec) (*ConfigPlan, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+
+	clusterInfo, err := c.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取集群信息失败: %w", err)
+	}
+	brokers, err := planBrokers(clusterInfo, spec.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := c.loadLiveState(ctx, spec, brokers)
+	if err != nil {
+		return nil, err
+	}
+
+	// 集群名和 Broker 名同名 Topic 由 Broker 自动创建
+	builtin := builtinTopics(clusterInfo)
+
+	names := sortedKeys(brokers)
+	plan := &ConfigPlan{Cluster: spec.Cluster, Brokers: brokers}
+
+	if spec.Topics != nil {
+		desired := make(map[string]*TopicConfig, len(spec.Topics))
+		for _, t := range spec.Topics {
+			desired[t.Name] = t.config()
+		}
+		diffResource(plan, ResourceTopic, names, desired, live.topics,
+			func(name string) bool { return IsSystemTopic(name) || builtin[name] },
+			topicChanges,
+			func(a *ConfigAction, config *TopicConfig) { a.Topic = config })
+	}
+
+	if spec.Groups != nil {
+		desired := make(map[string]*SubscriptionGroupConfig, len(spec.Groups))
+		for _, g := range spec.Groups {
+			desired[g.Name] = g.config()
+		}
+		diffResource(plan, ResourceGroup, names, desired, live.groups,
+			isBuiltinGroup, groupChanges,
+			func(a *ConfigAction, config *SubscriptionGroupConfig) { a.Group = config })
+	}
+
+	if spec.Users != nil {
+		desired := make(map[string]*UserInfo, len(spec.Users))
+		for i := range spec.Users {
+			desired[spec.Users[i].Username] = &spec.Users[i]
+		}
+		diffResource(plan, ResourceUser, names, desired, live.users,
+			func(string) bool { return false }, userChanges,
+			func(a *ConfigAction, user *UserInfo) { a.User = user })
+	}
+
+	if spec.Acls != nil {
+		desired := make(map[string]*AclInfo, len(spec.Acls))
+		for i := range spec.Acls {
+			desired[spec.Acls[i].Subject] = &spec.Acls[i]
+		}
+		diffResource(plan, ResourceAcl, names, desired, live.acls,
+			func(string) bool { return false }, aclChanges,
+			func(a *ConfigAction, acl *AclInfo) { a.Acl = acl })
+	}
+
+	for _, namespace := range sortedKeys(spec.KV) {
+		diffKV(plan, namespace, spec.KV[namespace], live.kv[namespace])
+	}
+
+	sortActions(plan.Actions)
+	for _, a := range plan.Actions {
+		a.ID = actionID(a)
+	}
+	return plan, nil
+}
+
+// planBrokers 返回集群的 Master 地址，clusterName 为空时返回全部集群
+func planBrokers(clusterInfo *ClusterInfo, clusterName string) (map[string]string, error) {
+	brokerNames := make([]string, 0, len(clusterInfo.BrokerAddrTable))
+	if clusterName != "" {
+		names, ok := clusterInfo.ClusterAddrTable[clusterName]
+		if !ok {
+			return nil, fmt.Errorf("集群 %s 不存在", clusterName)
+		}
+		brokerNames = append(brokerNames, names...)
+	} else {
+		for name := range clusterInfo.BrokerAddrTable {
+			brokerNames = append(brokerNames, name)
+		}
+	}
+
+	brokers := make(map[string]string, len(brokerNames))
+	for _, name := range brokerNames {
+		if data, ok := clusterInfo.BrokerAddrTable[name]; ok && data.BrokerAddrs["0"] != "" {
+			brokers[name] = data.BrokerAddrs["0"]
+		}
+	}
+	if len(brokers) == 0 {
+		return nil, ErrBrokerNotFound
+	}
+	return brokers, nil
+}
+
+// loadLiveState 读取配置中纳入管理的资源的现状
+func (c *Client) loadLiveState(ctx context.Context, spec *ClusterSpec, brokers map[string]string) (*liveState, error) {
+	live := &liveState{
+		topics: make(map[string]map[string]*TopicConfig),
+		groups: make(map[string]map[string]*SubscriptionGroupConfig),
+		users:  make(map[string]map[string]*UserInfo),
+		acls:   make(map[string]map[string]*AclInfo),
+		kv:     make(map[string]map[string]string),
+	}
+
+	for name, addr := range brokers {
+		if spec.Topics != nil {
+			topics, err := c.GetAllTopicConfig(ctx, addr)
+			if err != nil {
+				return nil, fmt.Errorf("获取 %s 的 Topic 配置失败: %w", name, err)
+			}
+			live.topics[name] = topics
+		}
+		if spec.Groups != nil {
+			groups, err := c.GetAllSubscriptionGroup(ctx, addr)
+			if err != nil {
+				return nil, fmt.Errorf("获取 %s 的订阅组失败: %w", name, err)
+			}
+			live.groups[name] = groups
+		}
+		if spec.Users != nil {
+			list, err := c.ListUser(ctx, addr)
+			if err != nil {
+				return nil, fmt.Errorf("获取 %s 的用户列表失败: %w", name, err)
+			}
+			users := make(map[string]*UserInfo, len(list.Users))
+			for i := range list.Users {
+				users[list.Users[i].Username] = &list.Users[i]
+			}
+			live.users[name] = users
+		}
+		if spec.Acls != nil {
+			list, err := c.ListAcl(ctx, addr)
+			if err != nil {
+				return nil, fmt.Errorf("获取 %s 的 ACL 列表失败: %w", name, err)
+			}
+			acls := make(map[string]*AclInfo, len(list.Acls))
+			for i := range list.Acls {
+				acls[list.Acls[i].Subject] = &list.Acls[i]
+			}
+			live.acls[name] = acls
+		}
+	}
+
+	for namespace := range spec.KV {
+		table, err := c.getKVNamespace(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		live.kv[namespace] = table
+	}
+	return live, nil
+}
+
+// diffResource 逐个 Broker 对比资源，生成创建、更新和删除动作
+//
+// 同一资源在部分 Broker 缺失、部分 Broker 不一致时分别生成创建和更新动作。
+func diffResource[T any](plan *ConfigPlan, kind ResourceKind, brokers []string,
+	desired map[string]*T, live map[string]map[string]*T,
+	builtin func(name string) bool,
+	changes func(desired, live *T) []FieldChange,
+	set func(a *ConfigAction, v *T)) {
+
+	for _, name := range sortedKeys(desired) {
+		var missing, differ []string
+		var diff []FieldChange
+		for _, broker := range brokers {
+			current, ok := live[broker][name]
+			if !ok || current == nil {
+				missing = append(missing, broker)
+				continue
+			}
+			if d := changes(desired[name], current); len(d) > 0 {
+				differ = append(differ, broker)
+				if diff == nil {
+					diff = d
+				}
+			}
+		}
+		if len(missing) > 0 {
+			a := &ConfigAction{Kind: kind, Op: ActionCreate, Name: name, Brokers: missing}
+			set(a, desired[name])
+			plan.Actions = append(plan.Actions, a)
+		}
+		if len(differ) > 0 {
+			a := &ConfigAction{Kind: kind, Op: ActionUpdate, Name: name, Brokers: differ, Changes: diff}
+			set(a, desired[name])
+			plan.Actions = append(plan.Actions, a)
+		}
+	}
+
+	// 现有但未声明的资源
+	extra := make(map[string][]string)
+	for _, broker := range brokers {
+		for name := range live[broker] {
+			if _, ok := desired[name]; !ok && !builtin(name) {
+				extra[name] = append(extra[name], broker)
+			}
+		}
+	}
+	for _, name := range sortedKeys(extra) {
+		plan.Actions = append(plan.Actions, &ConfigAction{Kind: kind, Op: ActionDelete, Name: name, Brokers: extra[name]})
+	}
+}
+
+// diffKV 对比命名空间下的 KV
+func diffKV(plan *ConfigPlan, namespace string, desired, live map[string]string) {
+	for _, key := range sortedKeys(desired) {
+		a := &ConfigAction{Kind: ResourceKV, Name: namespace + "/" + key, Namespace: namespace, Key: key, Value: desired[key]}
+		current, ok := live[key]
+		switch {
+		case !ok:
+			a.Op = ActionCreate
+		case current != desired[key]:
+			a.Op = ActionUpdate
+			a.Changes = []FieldChange{{Field: "value", Old: current, New: desired[key]}}
+		default:
+			continue
+		}
+		plan.Actions = append(plan.Actions, a)
+	}
+	for _, key := range sortedKeys(live) {
+		if _, ok := desired[key]; !ok {
+			plan.Actions = append(plan.Actions, &ConfigAction{Kind: ResourceKV, Op: ActionDelete,
+				Name: namespace + "/" + key, Namespace: namespace, Key: key})
+		}
+	}
+}
+
+// topicChanges 对比 Topic 配置
+func topicChanges(desired, live *TopicConfig) []FieldChange {
+	var changes []FieldChange
+	changes = appendChange(changes, "readQueueNums", live.ReadQueueNums, desired.ReadQueueNums)
+	changes = appendChange(changes, "writeQueueNums", live.WriteQueueNums, desired.WriteQueueNums)
+	changes = appendChange(changes, "perm", live.Perm, desired.Perm)
+	changes = appendChange(changes, "order", live.Order, desired.Order)
+	for _, key := range sortedKeys(desired.Attributes) {
+		changes = appendChange(changes, "attributes."+key, live.Attributes[key], desired.Attributes[key])
+	}
+	return changes
+}
+
+// groupChanges 对比订阅组配置
+func groupChanges(desired, live *SubscriptionGroupConfig) []FieldChange {
+	var changes []FieldChange
+	changes = appendChange(changes, "consumeEnable", live.ConsumeEnable, desired.ConsumeEnable)
+	changes = appendChange(changes, "consumeFromMinEnable", live.ConsumeFromMinEnable, desired.ConsumeFromMinEnable)
+	changes = appendChange(changes, "consumeBroadcastEnable", live.ConsumeBroadcastEnable, desired.ConsumeBroadcastEnable)
+	changes = appendChange(changes, "retryQueueNums", live.RetryQueueNums, desired.RetryQueueNums)
+	changes = appendChange(changes, "retryMaxTimes", live.RetryMaxTimes, desired.RetryMaxTimes)
+	changes = appendChange(changes, "brokerId", live.BrokerId, desired.BrokerId)
+	changes = appendChange(changes, "whichBrokerWhenConsumeSlowly", live.WhichBrokerWhenConsumeSlowly, desired.WhichBrokerWhenConsumeSlowly)
+	changes = appendChange(changes, "notifyConsumerIdsChangedEnable", live.NotifyConsumerIdsChangedEnable, desired.NotifyConsumerIdsChangedEnable)
+	changes = appendChange(changes, "consumeMessageOrderly", live.ConsumeMessageOrderly, desired.ConsumeMessageOrderly)
+	// 以下字段未填写时由 Broker 取默认值，不参与对比
+	if desired.ConsumeTimeoutMinute > 0 {
+		changes = appendChange(changes, "consumeTimeoutMinute", live.ConsumeTimeoutMinute, desired.ConsumeTimeoutMinute)
+	}
+	if desired.GroupRetryPolicy != nil {
+		changes = appendChange(changes, "groupRetryPolicy", live.GroupRetryPolicy.String(), desired.GroupRetryPolicy.String())
+	}
+	return changes
+}
+
+// userChanges 对比用户，配置中未填写的字段不参与对比
+//
+// Broker 返回的密码可能为空或已加密，只在两边都有值时对比。
+func userChanges(desired, live *UserInfo) []FieldChange {
+	var changes []FieldChange
+	if desired.UserType != "" && !strings.EqualFold(desired.UserType, live.UserType) {
+		changes = append(changes, FieldChange{Field: "userType", Old: live.UserType, New: desired.UserType})
+	}
+	if desired.UserStatus != "" && !strings.EqualFold(desired.UserStatus, live.UserStatus) {
+		changes = append(changes, FieldChange{Field: "userStatus", Old: live.UserStatus, New: desired.UserStatus})
+	}
+	if desired.Password != "" && live.Password != "" && desired.Password != live.Password {
+		changes = append(changes, FieldChange{Field: "password", Old: "******", New: "******"})
+	}
+	return changes
+}
+
+// aclChanges 对比 ACL 策略，策略和列表顺序不影响结果
+func aclChanges(desired, live *AclInfo) []FieldChange {
+	var changes []FieldChange
+	if desired.Description != "" && desired.Description != live.Description {
+		changes = append(changes, FieldChange{Field: "description", Old: live.Description, New: desired.Description})
+	}
+	if oldValue, newValue := normalizePolicies(live.Policies), normalizePolicies(desired.Policies); oldValue != newValue {
+		changes = append(changes, FieldChange{Field: "policies", Old: oldValue, New: newValue})
+	}
+	return changes
+}
+
+// normalizePolicies 将策略规范化为排序后的 JSON 字符串
+func normalizePolicies(policies []AclPolicy) string {
+	normalized := make([]AclPolicy, len(policies))
+	for i, p := range policies {
+		p.Actions = sortedUpper(p.Actions)
+		p.SourceIPs = sortedUpper(p.SourceIPs)
+		p.Effect = strings.ToUpper(p.Effect)
+		p.Decision = strings.ToUpper(p.Decision)
+		if p.Decision == "" {
+			p.Decision = p.Effect
+		}
+		p.Effect = ""
+		normalized[i] = p
+	}
+	sort.Slice(normalized, func(i, j int) bool {
+		if normalized[i].Resource != normalized[j].Resource {
+			return normalized[i].Resource < normalized[j].Resource
+		}
+		return normalized[i].Decision < normalized[j].Decision
+	})
+	data, _ := json.Marshal(normalized)
+	return string(data)
+}
+
+// sortedUpper 返回转为大写并排序的副本，空列表返回 nil
+func sortedUpper(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = strings.ToUpper(v)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// sortedKeys 返回排序后的 map key
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appendChange 值不同时追加字段变更
+func appendChange[V comparable](changes []FieldChange, field string, oldValue, newValue V) []FieldChange {
+	if oldValue == newValue {
+		return changes
+	}
+	return append(changes, FieldChange{Field: field, Old: fmt.Sprint(oldValue), New: fmt.Sprint(newValue)})
+}
+
+// isBuiltinGroup 判断是否为 Broker 内置订阅组
+func isBuiltinGroup(group string) bool {
+	return isSystemGroup(group) || strings.HasPrefix(group, "CID_RMQ_SYS_")
+}
+
+// sortActions 排序动作：先按 KV、Topic、订阅组、用户、ACL 顺序创建/更新，
+// 再按相反顺序删除，保证 ACL 引用的资源先于 ACL 创建、晚于 ACL 删除
+func sortActions(actions []*ConfigAction) {
+	rank := func(a *ConfigAction) int {
+		if a.Op == ActionDelete {
+			return 2*len(resourceOrder) - resourceOrder[a.Kind]
+		}
+		return resourceOrder[a.Kind]
+	}
+	sort.SliceStable(actions, func(i, j int) bool {
+		ri, rj := rank(actions[i]), rank(actions[j])
+		if ri != rj {
+			return ri < rj
+		}
+		if actions[i].Name != actions[j].Name {
+			return actions[i].Name < actions[j].Name
+		}
+		// 同一资源先创建后更新
+		return actions[i].Op < actions[j].Op
+	})
+}
+
+// actionID 由动作内容计算稳定的标识
+func actionID(a *ConfigAction) string {
+	content := *a
+	content.ID = ""
+	content.Changes = nil
+	data, _ := json.Marshal(content)
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// =============================================================================
+// 执行变更
+// =============================================================================
+
+// ApplyOptions 执行选项
+type ApplyOptions struct {
+	// DryRun 只输出将要执行的动作，不修改集群也不写执行日志
+	DryRun bool
+
+	// AllowPrune 允许执行删除动作，默认跳过全部删除
+	AllowPrune bool
+
+	// MaxPrune 删除动作数量上限，超过时拒绝执行整个计划，0 表示不限制
+	MaxPrune int
+
+	// LogFile 执行日志文件（JSON Lines），已成功执行的动作再次执行时跳过
+	LogFile string
+
+	// ContinueOnError 动作失败后继续执行后续动作
+	ContinueOnError bool
+}
+
+// ActionStatus 动作执行状态
+type ActionStatus string
+
+const (
+	StatusApplied ActionStatus = "applied" // 已执行
+	StatusDryRun  ActionStatus = "dry-run" // 演练，未执行
+	StatusSkipped ActionStatus = "skipped" // 跳过
+	StatusResumed ActionStatus = "resumed" // 执行日志中已完成，跳过
+	StatusFailed  ActionStatus = "failed"  // 执行失败
+)
+
+// ActionResult 动作执行结果
+type ActionResult struct {
+	Time   time.Time     `json:"time"`
+	ID     string        `json:"id"`
+	Kind   ResourceKind  `json:"kind"`
+	Op     ActionOp      `json:"op"`
+	Name   string        `json:"name"`
+	Status ActionStatus  `json:"status"`
+	Reason string        `json:"reason,omitempty"` // 跳过原因或错误信息
+	Action *ConfigAction `json:"-"`
+}
+
+// ApplyResult 执行结果
+type ApplyResult struct {
+	Results []ActionResult `json:"results"`
+}
+
+// Count 返回指定状态的动作数量
+func (r *ApplyResult) Count(status ActionStatus) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Apply 按顺序执行变更计划
+//
+// 删除动作需要开启 AllowPrune，且不会删除当前客户端 AccessKey 对应的用户
+// 及其 ACL。配置 LogFile 时每个动作的结果追加写入日志，中断后使用同一日志
+// 重新执行会跳过已成功的动作。动作失败时返回错误，已执行的结果保留在
+// ApplyResult 中。
+func (c *Client) Apply(ctx context.Context, plan *ConfigPlan, opts ApplyOptions) (*ApplyResult, error) {
+	if opts.AllowPrune && opts.MaxPrune > 0 {
+		if n := plan.Count(ActionDelete); n > opts.MaxPrune {
+			return nil, fmt.Errorf("计划删除 %d 个资源，超过上限 %d", n, opts.MaxPrune)
+		}
+	}
+
+	var done map[string]bool
+	var logFile *os.File
+	if opts.LogFile != "" && !opts.DryRun {
+		var err error
+		if done, err = readApplyLog(opts.LogFile); err != nil {
+			return nil, err
+		}
+		logFile, err = os.OpenFile(opts.LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("打开执行日志失败: %w", err)
+		}
+		defer logFile.Close()
+	}
+
+	result := &ApplyResult{}
+	var errs []error
+	for _, action := range plan.Actions {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		res := ActionResult{ID: action.ID, Kind: action.Kind, Op: action.Op, Name: action.Name, Action: action}
+		switch {
+		case done[action.ID]:
+			res.Status = StatusResumed
+		case action.Op == ActionDelete && !opts.AllowPrune:
+			res.Status, res.Reason = StatusSkipped, "未开启 AllowPrune"
+		case action.Op == ActionDelete && c.isSelfPrincipal(action):
+			res.Status, res.Reason = StatusSkipped, "当前客户端使用的用户"
+		case opts.DryRun:
+			res.Status = StatusDryRun
+		default:
+			if err := c.applyAction(ctx, plan, action); err != nil {
+				res.Status, res.Reason = StatusFailed, err.Error()
+				errs = append(errs, fmt.Errorf("%s: %w", action, err))
+			} else {
+				res.Status = StatusApplied
+			}
+		}
+		res.Time = time.Now()
+		result.Results = append(result.Results, res)
+
+		if logFile != nil && res.Status != StatusResumed {
+			line, _ := json.Marshal(res)
+			if _, err := logFile.Write(append(line, '\n')); err != nil {
+				return result, fmt.Errorf("写入执行日志失败: %w", err)
+			}
+		}
+		if res.Status == StatusFailed && !opts.ContinueOnError {
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return result, fmt.Errorf("执行变更失败: %w", errors.Join(errs...))
+	}
+	return result, nil
+}
+
+// readApplyLog 读取执行日志中已成功的动作 ID，日志不存在时返回空集合
+func readApplyLog(path string) (map[string]bool, error) {
+	done := make(map[string]bool)
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取执行日志失败: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry ActionResult
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// 中断时可能写入了不完整的最后一行
+			continue
+		}
+		if entry.Status == StatusApplied {
+			done[entry.ID] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取执行日志失败: %w", err)
+	}
+	return done, nil
+}
+
+// isSelfPrincipal 判断删除动作是否针对当前客户端的 AccessKey
+func (c *Client) isSelfPrincipal(a *ConfigAction) bool {
+	ak := c.opts.AccessKey
+	if ak == "" {
+		return false
+	}
+	switch a.Kind {
+	case ResourceUser:
+		return a.Name == ak
+	case ResourceAcl:
+		return a.Name == ak || a.Name == "User:"+ak
+	}
+	return false
+}
+
+// applyAction 在动作涉及的 Broker（或 NameServer）上执行变更
+func (c *Client) applyAction(ctx context.Context, plan *ConfigPlan, a *ConfigAction) error {
+	if a.Kind == ResourceKV {
+		if a.Op == ActionDelete {
+			return c.DeleteKVConfig(ctx, a.Namespace, a.Key)
+		}
+		return c.PutKVConfig(ctx, a.Namespace, a.Key, a.Value)
+	}
+
+	var errs []error
+	for _, broker := range a.Brokers {
+		addr, ok := plan.Brokers[broker]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %w", broker, ErrBrokerNotFound))
+			continue
+		}
+		if err := c.applyOnBroker(ctx, addr, a); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", broker, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	// Broker 上全部删除后再清理 NameServer 路由，只清理计划所在集群，其他集群的路由不受影响
+	if a.Kind == ResourceTopic && a.Op == ActionDelete {
+		return c.DeleteTopicInNameServerByCluster(ctx, a.Name, plan.Cluster)
+	}
+	return nil
+}
+
+// applyOnBroker 在单个 Broker 上执行变更
+func (c *Client) applyOnBroker(ctx context.Context, addr string, a *ConfigAction) error {
+	switch a.Kind {
+	case ResourceTopic:
+		if a.Op == ActionDelete {
+			return c.DeleteTopicInBroker(ctx, addr, a.Name)
+		}
+		return c.CreateTopic(ctx, addr, *a.Topic)
+	case ResourceGroup:
+		if a.Op == ActionDelete {
+			return c.DeleteSubscriptionGroup(ctx, addr, a.Name)
+		}
+		return c.CreateSubscriptionGroup(ctx, addr, *a.Group)
+	case ResourceUser:
+		switch a.Op {
+		case ActionCreate:
+			return c.CreateUser(ctx, addr, *a.User)
+		case ActionUpdate:
+			return c.UpdateUser(ctx, addr, *a.User)
+		default:
+			return c.DeleteUser(ctx, addr, a.Name)
+		}
+	case ResourceAcl:
+		switch a.Op {
+		case ActionCreate:
+			return c.CreateAcl(ctx, addr, *a.Acl)
+		case ActionUpdate:
+			return c.UpdateAcl(ctx, addr, *a.Acl)
+		default:
+			return c.DeleteAcl(ctx, addr, a.Name)
+		}
+	}
+	return fmt.Errorf("未知的资源类型: %s", a.Kind)
+}
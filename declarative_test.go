@@ -0,0 +1,441 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// 声明式配置单元测试
+// =============================================================================
+
+const testSpecYAML = `
+cluster: DefaultCluster
+topics:
+  - name: TopicA
+  - name: TopicB
+    readQueueNums: 4
+    writeQueueNums: 4
+    order: true
+groups:
+  - name: GroupA
+    consumeBroadcastEnable: false
+users:
+  - username: alice
+    password: secret
+    userType: Normal
+acls:
+  - subject: User:alice
+    policies:
+      - resource: Topic:TopicA
+        actions: [Pub, Sub]
+        decision: Allow
+kv:
+  ORDER_TOPIC_CONFIG:
+    TopicB: broker-a:4
+`
+
+// TestParseClusterSpec 测试解析 YAML/JSON 集群配置
+func TestParseClusterSpec(t *testing.T) {
+	spec, err := ParseClusterSpec([]byte(testSpecYAML))
+	if err != nil {
+		t.Fatalf("解析 YAML 失败: %v", err)
+	}
+	if spec.Cluster != "DefaultCluster" || len(spec.Topics) != 2 || len(spec.Groups) != 1 {
+		t.Fatalf("解析结果不正确: %+v", spec)
+	}
+	if topic := spec.Topics[0].config(); topic.ReadQueueNums != 8 || topic.WriteQueueNums != 8 || topic.Perm != 6 {
+		t.Errorf("Topic 默认值不正确: %+v", topic)
+	}
+	if group := spec.Groups[0].config(); !group.ConsumeEnable || group.ConsumeBroadcastEnable || group.RetryMaxTimes != 16 {
+		t.Errorf("订阅组默认值不正确: %+v", group)
+	}
+	if got := spec.Acls[0].Policies[0].Actions; len(got) != 2 || got[1] != "Sub" {
+		t.Errorf("ACL 动作不正确: %v", got)
+	}
+	if spec.KV["ORDER_TOPIC_CONFIG"]["TopicB"] != "broker-a:4" {
+		t.Errorf("KV 不正确: %v", spec.KV)
+	}
+
+	// JSON 与 YAML 等价
+	data, _ := json.Marshal(spec)
+	fromJSON, err := ParseClusterSpec(data)
+	if err != nil {
+		t.Fatalf("解析 JSON 失败: %v", err)
+	}
+	again, _ := json.Marshal(fromJSON)
+	if string(again) != string(data) {
+		t.Errorf("JSON 往返不一致:\n%s\n%s", data, again)
+	}
+
+	// 空列表表示纳入管理
+	spec, err = ParseClusterSpec([]byte("topics: []\n"))
+	if err != nil || spec.Topics == nil || spec.Groups != nil {
+		t.Errorf("空列表应纳入管理: %+v, %v", spec, err)
+	}
+
+	for _, bad := range []string{
+		"unknown: 1\n",
+		"topics:\n  - name: A\n  - name: A\n",
+		"groups:\n  - retryMaxTimes: 3\n",
+	} {
+		if _, err := ParseClusterSpec([]byte(bad)); err == nil {
+			t.Errorf("配置 %q 应解析失败", bad)
+		}
+	}
+}
+
+// TestAclChanges 测试 ACL 策略对比忽略顺序和大小写
+func TestAclChanges(t *testing.T) {
+	desired := &AclInfo{Subject: "User:alice", Policies: []AclPolicy{
+		{Resource: "Topic:B", Actions: []string{"Sub", "Pub"}, Decision: "Allow"},
+		{Resource: "Topic:A", Actions: []string{"Pub"}, Decision: "Allow"},
+	}}
+	live := &AclInfo{Subject: "User:alice", Policies: []AclPolicy{
+		{Resource: "Topic:A", Actions: []string{"PUB"}, Decision: "ALLOW"},
+		{Resource: "Topic:B", Actions: []string{"PUB", "SUB"}, Decision: "ALLOW"},
+	}}
+	if changes := aclChanges(desired, live); len(changes) != 0 {
+		t.Errorf("等价策略不应有差异: %+v", changes)
+	}
+
+	live.Policies[1].Decision = "DENY"
+	if changes := aclChanges(desired, live); len(changes) != 1 || changes[0].Field != "policies" {
+		t.Errorf("应检测到策略差异: %+v", changes)
+	}
+}
+
+// TestSortActions 测试动作执行顺序
+func TestSortActions(t *testing.T) {
+	actions := []*ConfigAction{
+		{Kind: ResourceTopic, Op: ActionDelete, Name: "T1"},
+		{Kind: ResourceAcl, Op: ActionCreate, Name: "User:a"},
+		{Kind: ResourceAcl, Op: ActionDelete, Name: "User:b"},
+		{Kind: ResourceTopic, Op: ActionUpdate, Name: "T2"},
+		{Kind: ResourceTopic, Op: ActionCreate, Name: "T2"},
+		{Kind: ResourceKV, Op: ActionCreate, Name: "ns/k"},
+		{Kind: ResourceUser, Op: ActionDelete, Name: "b"},
+	}
+	sortActions(actions)
+
+	want := []string{
+		"create kv ns/k", "create topic T2", "update topic T2", "create acl User:a",
+		"delete acl User:b", "delete user b", "delete topic T1",
+	}
+	for i, a := range actions {
+		if a.String() != want[i] {
+			t.Errorf("第 %d 个动作应为 %s, got %s", i, want[i], a)
+		}
+	}
+}
+
+// =============================================================================
+// 声明式配置端到端测试（本地模拟 NameServer/Broker）
+// =============================================================================
+
+// newDeclarativeCluster 启动带有现有配置的模拟集群
+func newDeclarativeCluster(t *testing.T) *remotingtest.Server {
+	t.Helper()
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+	handleClusterInfo(srv, "broker-a")
+
+	srv.Handle(remoting.GetAllTopicConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"topicConfigTable": map[string]any{
+			"TopicA":         map[string]any{"topicName": "TopicA", "readQueueNums": 4, "writeQueueNums": 4, "perm": 6},
+			"Legacy":         map[string]any{"topicName": "Legacy", "readQueueNums": 8, "writeQueueNums": 8, "perm": 6},
+			"TBW102":         map[string]any{"topicName": "TBW102", "readQueueNums": 8, "writeQueueNums": 8, "perm": 7},
+			"DefaultCluster": map[string]any{"topicName": "DefaultCluster", "readQueueNums": 16, "writeQueueNums": 16, "perm": 7},
+			"%RETRY%GroupA":  map[string]any{"topicName": "%RETRY%GroupA", "readQueueNums": 1, "writeQueueNums": 1, "perm": 6},
+		}})
+	})
+	srv.Handle(remoting.GetAllSubscriptionGroup, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"subscriptionGroupTable": map[string]any{
+			"GroupA": map[string]any{"groupName": "GroupA", "consumeEnable": true, "consumeFromMinEnable": true,
+				"consumeBroadcastEnable": false, "retryQueueNums": 1, "retryMaxTimes": 16, "whichBrokerWhenConsumeSlowly": 1,
+				"notifyConsumerIdsChangedEnable": true},
+			"OldGroup":       map[string]any{"groupName": "OldGroup"},
+			"TOOLS_CONSUMER": map[string]any{"groupName": "TOOLS_CONSUMER"},
+		}})
+	})
+	srv.Handle(remoting.ListUser, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(UserList{Users: []UserInfo{
+			{Username: "admin", UserType: "Super"},
+			{Username: "bob", UserType: "Normal"},
+		}})
+	})
+	srv.Handle(remoting.ListAcl, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(AclList{Acls: []AclInfo{{Subject: "User:bob", Policies: []AclPolicy{
+			{Resource: "Topic:Legacy", Actions: []string{"Pub"}, Decision: "Allow"},
+		}}}})
+	})
+	srv.Handle(remoting.GetKVListByNamespace, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		if req.ExtFields["namespace"] != "ORDER_TOPIC_CONFIG" {
+			return remotingtest.Error(remoting.QueryNotFound, "No config item")
+		}
+		return remotingtest.JSON(map[string]any{"table": map[string]string{"Legacy": "broker-a:8"}})
+	})
+
+	for _, code := range []int{
+		remoting.UpdateAndCreateTopic, remoting.DeleteTopicInBroker, remoting.DeleteTopicInNamesrv,
+		remoting.UpdateAndCreateSubscriptionGroup, remoting.DeleteSubscriptionGroup,
+		remoting.CreateUser, remoting.DeleteUser, remoting.CreateAcl, remoting.DeleteAcl,
+		remoting.PutKVConfig, remoting.DeleteKVConfig,
+	} {
+		srv.Handle(code, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+			return remotingtest.Success(nil)
+		})
+	}
+	return srv
+}
+
+// TestPlan 测试生成变更计划
+func TestPlan(t *testing.T) {
+	srv := newDeclarativeCluster(t)
+	client := newFakeClient(t, srv)
+
+	spec, err := ParseClusterSpec([]byte(testSpecYAML))
+	if err != nil {
+		t.Fatalf("解析配置失败: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	plan, err := client.Plan(ctx, spec)
+	if err != nil {
+		t.Fatalf("生成计划失败: %v", err)
+	}
+
+	var got []string
+	for _, a := range plan.Actions {
+		got = append(got, a.String())
+		if a.ID == "" {
+			t.Errorf("动作 %s 缺少 ID", a)
+		}
+	}
+	want := []string{
+		"create kv ORDER_TOPIC_CONFIG/TopicB",
+		"update topic TopicA [broker-a]",
+		"create topic TopicB [broker-a]",
+		"create user alice [broker-a]",
+		"create acl User:alice [broker-a]",
+		"delete acl User:bob [broker-a]",
+		"delete user admin [broker-a]",
+		"delete user bob [broker-a]",
+		"delete group OldGroup [broker-a]",
+		"delete topic Legacy [broker-a]",
+		"delete kv ORDER_TOPIC_CONFIG/Legacy",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("计划不正确:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	update := plan.Actions[1]
+	if len(update.Changes) != 2 || update.Changes[0] != (FieldChange{Field: "readQueueNums", Old: "4", New: "8"}) {
+		t.Errorf("Topic 差异不正确: %+v", update.Changes)
+	}
+
+	// 重新生成的计划 ID 不变
+	again, err := client.Plan(ctx, spec)
+	if err != nil {
+		t.Fatalf("生成计划失败: %v", err)
+	}
+	for i := range again.Actions {
+		if again.Actions[i].ID != plan.Actions[i].ID {
+			t.Errorf("动作 %s 的 ID 不稳定", again.Actions[i])
+		}
+	}
+
+	if _, err := client.Plan(ctx, &ClusterSpec{Cluster: "NoSuchCluster"}); err == nil {
+		t.Error("不存在的集群应返回错误")
+	}
+}
+
+// TestApply 测试执行计划：演练、删除保护和断点续执行
+func TestApply(t *testing.T) {
+	srv := newDeclarativeCluster(t)
+	client := newFakeClient(t, srv, WithACL("admin", "secret"))
+
+	spec, err := ParseClusterSpec([]byte(testSpecYAML))
+	if err != nil {
+		t.Fatalf("解析配置失败: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	plan, err := client.Plan(ctx, spec)
+	if err != nil {
+		t.Fatalf("生成计划失败: %v", err)
+	}
+
+	writes := func() int {
+		n := 0
+		for _, req := range srv.Requests(-1) {
+			switch req.Code {
+			case remoting.GetBrokerClusterInfo, remoting.GetAllTopicConfig, remoting.GetAllSubscriptionGroup,
+				remoting.ListUser, remoting.ListAcl, remoting.GetKVListByNamespace:
+			default:
+				n++
+			}
+		}
+		return n
+	}
+
+	// 演练不修改集群
+	result, err := client.Apply(ctx, plan, ApplyOptions{DryRun: true, AllowPrune: true})
+	if err != nil {
+		t.Fatalf("演练失败: %v", err)
+	}
+	if writes() != 0 || result.Count(StatusDryRun) != len(plan.Actions)-1 || result.Count(StatusSkipped) != 1 {
+		t.Fatalf("演练结果不正确: %+v", result.Results)
+	}
+
+	// 删除数量超过上限时拒绝执行
+	if _, err := client.Apply(ctx, plan, ApplyOptions{AllowPrune: true, MaxPrune: 2}); err == nil {
+		t.Fatal("删除数量超过上限应返回错误")
+	}
+	if writes() != 0 {
+		t.Fatal("拒绝执行时不应修改集群")
+	}
+
+	// 第一次执行在创建 ACL 时失败
+	var failAcl atomic.Bool
+	failAcl.Store(true)
+	srv.Handle(remoting.CreateAcl, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		if failAcl.Load() {
+			return remotingtest.Error(remoting.NoPermission, "no permission")
+		}
+		return remotingtest.Success(nil)
+	})
+
+	logFile := filepath.Join(t.TempDir(), "apply.log")
+	result, err = client.Apply(ctx, plan, ApplyOptions{AllowPrune: true, LogFile: logFile})
+	if err == nil {
+		t.Fatal("创建 ACL 失败时应返回错误")
+	}
+	if result.Count(StatusApplied) != 4 || result.Count(StatusFailed) != 1 || len(result.Results) != 5 {
+		t.Fatalf("失败后应停止执行: %+v", result.Results)
+	}
+
+	// 使用同一日志重新执行，已完成的动作跳过
+	failAcl.Store(false)
+	before := len(srv.Requests(remoting.UpdateAndCreateTopic))
+	result, err = client.Apply(ctx, plan, ApplyOptions{AllowPrune: true, LogFile: logFile})
+	if err != nil {
+		t.Fatalf("重新执行失败: %v", err)
+	}
+	if result.Count(StatusResumed) != 4 || len(srv.Requests(remoting.UpdateAndCreateTopic)) != before {
+		t.Errorf("已完成的动作应跳过: %+v", result.Results)
+	}
+
+	// 当前客户端使用的用户不会被删除
+	for _, req := range srv.Requests(remoting.DeleteUser) {
+		if strings.Contains(string(req.Body)+req.ExtFields["username"], "admin") {
+			t.Error("不应删除当前客户端使用的用户")
+		}
+	}
+	if len(srv.Requests(remoting.DeleteUser)) != 1 || len(srv.Requests(remoting.DeleteTopicInNamesrv)) != 1 ||
+		len(srv.Requests(remoting.DeleteKVConfig)) != 1 {
+		t.Error("删除动作执行次数不正确")
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("读取执行日志失败: %v", err)
+	}
+	// 失败的一次也记录在日志中
+	if lines := strings.Count(string(data), "\n"); lines != len(plan.Actions)+1 {
+		t.Errorf("执行日志应有 %d 行, got %d", len(plan.Actions)+1, lines)
+	}
+}
+
+// TestApplyDeleteTopicInCluster 测试限定集群时只删除该集群的 NameServer 路由，ClusterB 的路由保留
+func TestApplyDeleteTopicInCluster(t *testing.T) {
+	srv := newDeclarativeCluster(t)
+	client := newFakeClient(t, srv)
+
+	// broker-a 属于 DefaultCluster，broker-b 属于 ClusterB，两者都有 Legacy
+	srv.Handle(remoting.GetBrokerClusterInfo, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"brokerAddrTable":{` +
+			`"broker-a":{"cluster":"DefaultCluster","brokerName":"broker-a","brokerAddrs":{0:"` + srv.Addr + `"}},` +
+			`"broker-b":{"cluster":"ClusterB","brokerName":"broker-b","brokerAddrs":{0:"127.0.0.1:1"}}},` +
+			`"clusterAddrTable":{"DefaultCluster":["broker-a"],"ClusterB":["broker-b"]}}`))
+	})
+
+	spec, err := ParseClusterSpec([]byte(testSpecYAML))
+	if err != nil {
+		t.Fatalf("解析配置失败: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	plan, err := client.Plan(ctx, spec)
+	if err != nil {
+		t.Fatalf("生成计划失败: %v", err)
+	}
+	if len(plan.Brokers) != 1 || plan.Brokers["broker-a"] != srv.Addr {
+		t.Fatalf("计划应只包含 DefaultCluster 的 Broker: %v", plan.Brokers)
+	}
+	if _, err := client.Apply(ctx, plan, ApplyOptions{AllowPrune: true}); err != nil {
+		t.Fatalf("执行计划失败: %v", err)
+	}
+
+	reqs := srv.Requests(remoting.DeleteTopicInNamesrv)
+	if len(reqs) != 1 || reqs[0].ExtFields["topic"] != "Legacy" || reqs[0].ExtFields["clusterName"] != "DefaultCluster" {
+		t.Errorf("应只删除 DefaultCluster 的路由: %+v", reqs)
+	}
+}
+
+// =============================================================================
+// 声明式配置集成测试
+// =============================================================================
+
+// TestIntegration_PlanApply 测试在真实集群上创建并收敛 Topic
+func TestIntegration_PlanApply(t *testing.T) {
+	skipIfNoRocketMQ(t)
+	client := getTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := testContext()
+	defer cancel()
+
+	topic := getTestTopicName("declarative")
+	spec := &ClusterSpec{Topics: []TopicSpec{{Name: topic, ReadQueueNums: 4, WriteQueueNums: 4}}}
+
+	plan, err := client.Plan(ctx, spec)
+	if err != nil {
+		t.Fatalf("生成计划失败: %v", err)
+	}
+	if plan.Count(ActionCreate) == 0 {
+		t.Fatalf("计划应包含创建动作: %+v", plan.Actions)
+	}
+
+	if _, err := client.Apply(ctx, plan, ApplyOptions{}); err != nil {
+		t.Fatalf("执行计划失败: %v", err)
+	}
+	defer func() {
+		for _, addr := range plan.Brokers {
+			_ = client.DeleteTopicInBroker(ctx, addr, topic)
+		}
+		_ = client.DeleteTopicInNameServer(ctx, topic)
+	}()
+
+	plan, err = client.Plan(ctx, spec)
+	if err != nil {
+		t.Fatalf("生成计划失败: %v", err)
+	}
+	for _, a := range plan.Actions {
+		if a.Name == topic {
+			t.Errorf("执行后 Topic 应已收敛, got %s", a)
+		}
+	}
+}
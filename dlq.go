@@ -0,0 +1,389 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// 死信队列
+// =============================================================================
+
+// Topic 前缀（对应 Java MixAll）
+const (
+	RetryGroupTopicPrefix = "%RETRY%"
+	DLQGroupTopicPrefix   = "%DLQ%"
+)
+
+// 死信消息属性（对应 Java MessageConst，4.9.x 及以上版本写入）
+const (
+	PropertyDLQOriginTopic     = "DLQ_ORIGIN_TOPIC"
+	PropertyDLQOriginMessageId = "DLQ_ORIGIN_MESSAGE_ID"
+)
+
+// dlqPullBatchSize 浏览死信消息时单次拉取条数
+const dlqPullBatchSize = 32
+
+// GetRetryTopic 返回消费组的重试 Topic
+func GetRetryTopic(group string) string {
+	return RetryGroupTopicPrefix + group
+}
+
+// GetDLQTopic 返回消费组的死信 Topic
+func GetDLQTopic(group string) string {
+	return DLQGroupTopicPrefix + group
+}
+
+// DLQTopic 死信 Topic
+type DLQTopic struct {
+	ConsumerGroup string `json:"consumerGroup"` // 消费者组
+	Topic         string `json:"topic"`         // 死信 Topic
+}
+
+// ListDLQTopics 列出集群中所有消费组的死信 Topic
+func (c *Client) ListDLQTopics(ctx context.Context) ([]DLQTopic, error) {
+	topicList, err := c.FetchAllTopicList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []DLQTopic
+	for _, topic := range topicList.TopicList {
+		if !strings.HasPrefix(topic, DLQGroupTopicPrefix) {
+			continue
+		}
+		result = append(result, DLQTopic{
+			ConsumerGroup: strings.TrimPrefix(topic, DLQGroupTopicPrefix),
+			Topic:         topic,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ConsumerGroup < result[j].ConsumerGroup
+	})
+
+	return result, nil
+}
+
+// DLQQueueStats 死信队列统计
+type DLQQueueStats struct {
+	MessageQueue MessageQueue `json:"messageQueue"` // 队列
+	MinOffset    int64        `json:"minOffset"`    // 最小偏移
+	MaxOffset    int64        `json:"maxOffset"`    // 最大偏移
+	Count        int64        `json:"count"`        // 消息数
+}
+
+// DLQStats 死信统计
+type DLQStats struct {
+	ConsumerGroup string          `json:"consumerGroup"` // 消费者组
+	Topic         string          `json:"topic"`         // 死信 Topic
+	Total         int64           `json:"total"`         // 消息总数
+	Queues        []DLQQueueStats `json:"queues"`        // 各队列统计
+}
+
+// CountDLQMessages 统计消费组死信队列中的消息数
+func (c *Client) CountDLQMessages(ctx context.Context, group string) (*DLQStats, error) {
+	dlqTopic := GetDLQTopic(group)
+	statsTable, err := c.ExamineTopicStats(ctx, dlqTopic)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &DLQStats{
+		ConsumerGroup: group,
+		Topic:         dlqTopic,
+	}
+	for key, offset := range statsTable.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil {
+			continue
+		}
+		count := offset.MaxOffset - offset.MinOffset
+		stats.Queues = append(stats.Queues, DLQQueueStats{
+			MessageQueue: mq,
+			MinOffset:    offset.MinOffset,
+			MaxOffset:    offset.MaxOffset,
+			Count:        count,
+		})
+		stats.Total += count
+	}
+
+	sort.Slice(stats.Queues, func(i, j int) bool { return queueLess(stats.Queues[i].MessageQueue, stats.Queues[j].MessageQueue) })
+
+	return stats, nil
+}
+
+// DLQMessage 死信消息
+type DLQMessage struct {
+	Message     *MessageExt `json:"message"`     // 死信消息
+	OriginTopic string      `json:"originTopic"` // 原始 Topic
+	OriginMsgId string      `json:"originMsgId"` // 原始消息 ID
+	RetryCount  int         `json:"retryCount"`  // 重试次数
+}
+
+// newDLQMessage 从死信消息属性中提取原始信息
+func newDLQMessage(msg *MessageExt) *DLQMessage {
+	props := msg.Properties
+	dlqMsg := &DLQMessage{
+		Message:     msg,
+		OriginTopic: firstNonEmpty(props[PropertyDLQOriginTopic], props[PropertyRetryTopic], props[PropertyRealTopic]),
+		OriginMsgId: firstNonEmpty(props[PropertyDLQOriginMessageId], props[PropertyOriginMessageId], props[PropertyUniqKey], msg.MsgId),
+		RetryCount:  msg.ReconsumeTimes,
+	}
+	if v, err := strconv.Atoi(props[PropertyReconsumeTime]); err == nil {
+		dlqMsg.RetryCount = v
+	}
+	return dlqMsg
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// DLQBrowseOptions 浏览死信消息选项
+type DLQBrowseOptions struct {
+	MaxMessages    int   // 最大返回条数，<=0 时默认 32
+	BeginTimestamp int64 // 存储时间下限（毫秒），0 表示不限
+	EndTimestamp   int64 // 存储时间上限（毫秒），0 表示不限
+}
+
+// BrowseDLQMessages 浏览消费组死信队列中的消息，不影响消费进度
+// 注意：早期版本创建的死信 Topic 只有写权限，需要先开启读权限才能浏览
+func (c *Client) BrowseDLQMessages(ctx context.Context, group string, opts DLQBrowseOptions) ([]*DLQMessage, error) {
+	maxMessages := opts.MaxMessages
+	if maxMessages <= 0 {
+		maxMessages = dlqPullBatchSize
+	}
+
+	dlqTopic := GetDLQTopic(group)
+	routeData, err := c.ExamineTopicRouteInfo(ctx, dlqTopic)
+	if err != nil {
+		return nil, err
+	}
+
+	masters := masterAddrs(routeData)
+
+	var result []*DLQMessage
+	for _, queueData := range routeData.QueueDatas {
+		brokerAddr, ok := masters[queueData.BrokerName]
+		if !ok {
+			continue
+		}
+		if !queueData.Perm.Readable() {
+			return nil, fmt.Errorf("死信 Topic %s 在 %s 上不可读，请先开启读权限", dlqTopic, queueData.BrokerName)
+		}
+
+		for queueId := 0; queueId < queueData.ReadQueueNums; queueId++ {
+			mq := MessageQueue{Topic: dlqTopic, BrokerName: queueData.BrokerName, QueueId: queueId}
+			msgs, err := c.browseQueue(ctx, brokerAddr, mq, opts, maxMessages-len(result))
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, msgs...)
+			if len(result) >= maxMessages {
+				return result, nil
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// browseQueue 从队列最小偏移开始拉取，直到达到条数上限或队列末尾
+func (c *Client) browseQueue(ctx context.Context, brokerAddr string, mq MessageQueue, opts DLQBrowseOptions, limit int) ([]*DLQMessage, error) {
+	var result []*DLQMessage
+	offset := int64(0)
+	for len(result) < limit {
+		pullResult, err := c.PullMessage(ctx, brokerAddr, mq, "", offset, dlqPullBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("拉取 %s 死信消息失败: %w", mq.BrokerName, err)
+		}
+
+		switch pullResult.Status {
+		case PullStatusFound:
+			for _, msg := range pullResult.Messages {
+				if opts.BeginTimestamp > 0 && msg.StoreTimestamp < opts.BeginTimestamp {
+					continue
+				}
+				if opts.EndTimestamp > 0 && msg.StoreTimestamp > opts.EndTimestamp {
+					continue
+				}
+				result = append(result, newDLQMessage(msg))
+				if len(result) >= limit {
+					break
+				}
+			}
+		case PullStatusOffsetIllegal:
+			// 偏移小于最小偏移时从 Broker 建议的位置继续
+			if pullResult.NextBeginOffset <= offset {
+				return result, nil
+			}
+		case PullStatusNoMatchedMsg:
+		default:
+			return result, nil
+		}
+
+		if pullResult.NextBeginOffset <= offset {
+			return result, nil
+		}
+		offset = pullResult.NextBeginOffset
+	}
+
+	return result, nil
+}
+
+// =============================================================================
+// 死信重发
+// =============================================================================
+
+// DLQResendTarget 死信重发目标
+type DLQResendTarget string
+
+// 重发目标定义
+const (
+	DLQResendToOriginTopic DLQResendTarget = "ORIGIN" // 重发到原始 Topic
+	DLQResendToRetryTopic  DLQResendTarget = "RETRY"  // 重发到消费组的重试 Topic
+)
+
+// DLQResendOptions 死信重发选项
+type DLQResendOptions struct {
+	Target        DLQResendTarget // 重发目标，默认为原始 Topic
+	DryRun        bool            // 仅演练，不实际发送
+	RateLimit     int             // 每秒最多发送条数，<=0 表示不限速
+	ProducerGroup string          // 生产者组，为空时使用默认组
+}
+
+// DLQResendResult 单条死信重发结果
+type DLQResendResult struct {
+	OriginMsgId string      `json:"originMsgId"` // 原始消息 ID
+	TargetTopic string      `json:"targetTopic"` // 目标 Topic
+	DryRun      bool        `json:"dryRun"`      // 是否演练
+	SendResult  *SendResult `json:"sendResult"`  // 发送结果
+	Error       string      `json:"error"`       // 错误信息
+}
+
+// 重发或回放消息时不保留的系统属性
+var resendDroppedProperties = []string{
+	PropertyDLQOriginTopic,
+	PropertyDLQOriginMessageId,
+	PropertyRealTopic,
+	PropertyRealQueueId,
+	PropertyRetryTopic,
+	PropertyReconsumeTime,
+	PropertyMaxReconsumeTimes,
+	PropertyDelayLevel,
+	PropertyTransactionPrepared,
+	"MIN_OFFSET",
+	"MAX_OFFSET",
+	"CONSUME_START_TIME",
+}
+
+// ResendDLQMessages 将选中的死信消息重发到原始 Topic 或重试 Topic
+// 单条失败不会中断后续发送，失败信息记录在对应结果中；ctx 取消时返回已完成的结果
+func (c *Client) ResendDLQMessages(ctx context.Context, group string, msgs []*DLQMessage, opts DLQResendOptions) ([]DLQResendResult, error) {
+	limiter := newRateLimiter(opts.RateLimit)
+	defer limiter.stop()
+
+	results := make([]DLQResendResult, 0, len(msgs))
+	for _, dlqMsg := range msgs {
+		result := DLQResendResult{
+			OriginMsgId: dlqMsg.OriginMsgId,
+			DryRun:      opts.DryRun,
+		}
+
+		req, err := buildDLQResendRequest(group, dlqMsg, opts)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.TargetTopic = req.topic
+
+		if opts.DryRun {
+			results = append(results, result)
+			continue
+		}
+
+		if err := limiter.wait(ctx); err != nil {
+			return results, err
+		}
+
+		sendResult, err := c.sendMessage(ctx, req)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.SendResult = sendResult
+		}
+		results = append(results, result)
+
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+	}
+
+	return results, nil
+}
+
+// ResendDLQMessagesById 按原始消息 ID 查找死信消息并重发
+func (c *Client) ResendDLQMessagesById(ctx context.Context, group string, msgIds []string, opts DLQResendOptions) ([]DLQResendResult, error) {
+	dlqTopic := GetDLQTopic(group)
+
+	var msgs []*DLQMessage
+	var notFound []DLQResendResult
+	for _, msgId := range msgIds {
+		found, err := c.QueryMessageByUniqKey(ctx, dlqTopic, msgId, 0, 0)
+		if err != nil {
+			notFound = append(notFound, DLQResendResult{OriginMsgId: msgId, DryRun: opts.DryRun, Error: err.Error()})
+			continue
+		}
+		// 同一消息多次进入死信队列时只重发最新的一条
+		msgs = append(msgs, newDLQMessage(found[len(found)-1]))
+	}
+
+	results, err := c.ResendDLQMessages(ctx, group, msgs, opts)
+	return append(results, notFound...), err
+}
+
+// buildDLQResendRequest 构造死信重发请求
+func buildDLQResendRequest(group string, dlqMsg *DLQMessage, opts DLQResendOptions) (*sendRequest, error) {
+	if dlqMsg.OriginTopic == "" {
+		return nil, fmt.Errorf("消息 %s 缺少原始 Topic 信息", dlqMsg.OriginMsgId)
+	}
+
+	props := make(map[string]string, len(dlqMsg.Message.Properties)+2)
+	for k, v := range dlqMsg.Message.Properties {
+		props[k] = v
+	}
+	for _, k := range resendDroppedProperties {
+		delete(props, k)
+	}
+	props[PropertyOriginMessageId] = dlqMsg.OriginMsgId
+
+	req := &sendRequest{
+		topic:         dlqMsg.OriginTopic,
+		body:          dlqMsg.Message.Body,
+		properties:    props,
+		producerGroup: opts.ProducerGroup,
+	}
+
+	switch opts.Target {
+	case "", DLQResendToOriginTopic:
+	case DLQResendToRetryTopic:
+		// 消费者从重试 Topic 拉取后会根据 RETRY_TOPIC 还原原始 Topic
+		req.topic = GetRetryTopic(group)
+		props[PropertyRetryTopic] = dlqMsg.OriginTopic
+	default:
+		return nil, fmt.Errorf("不支持的重发目标: %s", opts.Target)
+	}
+
+	return req, nil
+}
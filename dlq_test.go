@@ -0,0 +1,167 @@
+package admin
+
+import (
+	"testing"
+)
+
+// =============================================================================
+// 死信队列单元测试
+// =============================================================================
+
+// TestNewDLQMessage 测试从死信消息属性中提取原始信息
+func TestNewDLQMessage(t *testing.T) {
+	msg := &MessageExt{
+		MsgId:          "DLQ_MSG",
+		ReconsumeTimes: 3,
+		Properties: map[string]string{
+			PropertyDLQOriginTopic:     "TopicTest",
+			PropertyDLQOriginMessageId: "ORIGIN_MSG",
+			PropertyRetryTopic:         "RetryTopic",
+			PropertyReconsumeTime:      "16",
+		},
+	}
+
+	dlqMsg := newDLQMessage(msg)
+	if dlqMsg.OriginTopic != "TopicTest" || dlqMsg.OriginMsgId != "ORIGIN_MSG" || dlqMsg.RetryCount != 16 {
+		t.Errorf("死信信息解析错误: %+v", dlqMsg)
+	}
+
+	// 旧版本 Broker 不写入 DLQ_ORIGIN_* 属性时回退到 RETRY_TOPIC / UNIQ_KEY
+	old := newDLQMessage(&MessageExt{
+		MsgId:          "DLQ_MSG",
+		ReconsumeTimes: 3,
+		Properties: map[string]string{
+			PropertyRetryTopic: "TopicTest",
+			PropertyUniqKey:    "UNIQ",
+		},
+	})
+	if old.OriginTopic != "TopicTest" || old.OriginMsgId != "UNIQ" || old.RetryCount != 3 {
+		t.Errorf("旧版本死信信息解析错误: %+v", old)
+	}
+}
+
+// TestBuildDLQResendRequest 测试构造死信重发请求
+func TestBuildDLQResendRequest(t *testing.T) {
+	dlqMsg := newDLQMessage(&MessageExt{
+		Body: []byte("hello"),
+		Properties: map[string]string{
+			PropertyDLQOriginTopic:     "TopicTest",
+			PropertyDLQOriginMessageId: "ORIGIN_MSG",
+			PropertyRealTopic:          "%DLQ%CG",
+			PropertyReconsumeTime:      "16",
+			PropertyTags:               "TagA",
+			"custom":                   "v",
+		},
+	})
+
+	req, err := buildDLQResendRequest("CG", dlqMsg, DLQResendOptions{})
+	if err != nil {
+		t.Fatalf("构造重发请求失败: %v", err)
+	}
+	if req.topic != "TopicTest" || string(req.body) != "hello" {
+		t.Errorf("重发目标错误: %s", req.topic)
+	}
+	if req.properties[PropertyTags] != "TagA" || req.properties["custom"] != "v" {
+		t.Errorf("用户属性应保留: %v", req.properties)
+	}
+	for _, k := range []string{PropertyRealTopic, PropertyReconsumeTime, PropertyDLQOriginTopic} {
+		if _, ok := req.properties[k]; ok {
+			t.Errorf("系统属性 %s 应被移除", k)
+		}
+	}
+	if req.properties[PropertyOriginMessageId] != "ORIGIN_MSG" {
+		t.Errorf("ORIGIN_MESSAGE_ID 未设置: %v", req.properties)
+	}
+
+	req, err = buildDLQResendRequest("CG", dlqMsg, DLQResendOptions{Target: DLQResendToRetryTopic})
+	if err != nil {
+		t.Fatalf("构造重发请求失败: %v", err)
+	}
+	if req.topic != "%RETRY%CG" || req.properties[PropertyRetryTopic] != "TopicTest" {
+		t.Errorf("重试 Topic 重发请求错误: %s %v", req.topic, req.properties)
+	}
+
+	if _, err := buildDLQResendRequest("CG", &DLQMessage{Message: &MessageExt{}}, DLQResendOptions{}); err == nil {
+		t.Error("缺少原始 Topic 时应返回错误")
+	}
+}
+
+// TestResendDLQMessages_DryRun 测试演练模式不发送消息
+func TestResendDLQMessages_DryRun(t *testing.T) {
+	client := &Client{}
+	msgs := []*DLQMessage{
+		{Message: &MessageExt{}, OriginTopic: "TopicTest", OriginMsgId: "M1"},
+		{Message: &MessageExt{}, OriginMsgId: "M2"},
+	}
+
+	results, err := client.ResendDLQMessages(t.Context(), "CG", msgs, DLQResendOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("演练重发失败: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("结果数量应为 2, got %d", len(results))
+	}
+	if !results[0].DryRun || results[0].TargetTopic != "TopicTest" || results[0].Error != "" {
+		t.Errorf("演练结果错误: %+v", results[0])
+	}
+	if results[1].Error == "" {
+		t.Errorf("缺少原始 Topic 的消息应记录错误: %+v", results[1])
+	}
+}
+
+// =============================================================================
+// 死信队列集成测试
+// =============================================================================
+
+// TestIntegration_ListDLQTopics 测试列出死信 Topic
+func TestIntegration_ListDLQTopics(t *testing.T) {
+	skipIfNoRocketMQ(t)
+	client := getTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := testContext()
+	defer cancel()
+
+	topics, err := client.ListDLQTopics(ctx)
+	if err != nil {
+		t.Fatalf("列出死信 Topic 失败: %v", err)
+	}
+
+	t.Logf("死信 Topic 数量: %d", len(topics))
+	for _, topic := range topics {
+		stats, err := client.CountDLQMessages(ctx, topic.ConsumerGroup)
+		if err != nil {
+			t.Logf("  - %s: 统计失败: %v", topic.Topic, err)
+			continue
+		}
+		t.Logf("  - %s: %d 条", topic.Topic, stats.Total)
+	}
+}
+
+// TestIntegration_BrowseDLQMessages 测试浏览死信消息
+func TestIntegration_BrowseDLQMessages(t *testing.T) {
+	skipIfNoRocketMQ(t)
+	client := getTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := testContext()
+	defer cancel()
+
+	topics, err := client.ListDLQTopics(ctx)
+	if err != nil {
+		t.Fatalf("列出死信 Topic 失败: %v", err)
+	}
+	if len(topics) == 0 {
+		t.Skip("集群中没有死信 Topic")
+	}
+
+	msgs, err := client.BrowseDLQMessages(ctx, topics[0].ConsumerGroup, DLQBrowseOptions{MaxMessages: 10})
+	if err != nil {
+		t.Skipf("浏览死信消息失败（死信 Topic 可能不可读）: %v", err)
+	}
+
+	t.Logf("死信消息数: %d", len(msgs))
+	for _, msg := range msgs {
+		t.Logf("  - %s <- %s, 重试 %d 次", msg.OriginMsgId, msg.OriginTopic, msg.RetryCount)
+	}
+}
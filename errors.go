@@ -1,6 +1,10 @@
 package admin
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // 预定义错误
 var (
@@ -33,6 +37,21 @@ var (
 
 	// ErrPermissionDenied 权限不足
 	ErrPermissionDenied = errors.New("权限不足")
+
+	// ErrMessageNotFound 消息未找到
+	ErrMessageNotFound = errors.New("消息未找到")
+
+	// ErrInvalidMessageData 消息数据格式错误
+	ErrInvalidMessageData = errors.New("消息数据格式错误")
+
+	// ErrConsumerOnline 消费者组有在线客户端
+	ErrConsumerOnline = errors.New("消费者组有在线客户端")
+
+	// ErrConcurrentModification 配置在读取后被并发修改
+	ErrConcurrentModification = errors.New("配置已被并发修改")
+
+	// ErrResetByQueueUnsupported Broker 不支持按队列重置 Offset
+	ErrResetByQueueUnsupported = errors.New("Broker 不支持按队列重置 Offset")
 )
 
 // AdminError 运维操作错误
@@ -54,3 +73,47 @@ func NewAdminError(code int, message string) *AdminError {
 	}
 }
 
+// IsResponseCode 判断 err 是否为指定响应码的 AdminError
+func IsResponseCode(err error, code int) bool {
+	var adminErr *AdminError
+	return errors.As(err, &adminErr) && adminErr.Code == code
+}
+
+// BrokerError 单个 Broker 上的操作失败
+type BrokerError struct {
+	BrokerName string // Broker 名称
+	Addr       string // Broker 地址
+	Err        error  // 失败原因
+}
+
+// Error 实现 error 接口
+func (e *BrokerError) Error() string {
+	return fmt.Sprintf("%s(%s): %v", e.BrokerName, e.Addr, e.Err)
+}
+
+// Unwrap 返回失败原因
+func (e *BrokerError) Unwrap() error {
+	return e.Err
+}
+
+// BrokerErrors 多个 Broker 上的操作失败，可通过 errors.As 取出各 Broker 的错误
+type BrokerErrors []*BrokerError
+
+// Error 实现 error 接口
+func (e BrokerErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d 个 Broker 执行失败: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Unwrap 返回各 Broker 的错误
+func (e BrokerErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
@@ -0,0 +1,353 @@
+package exporter
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+)
+
+// =============================================================================
+// 指标采集
+// =============================================================================
+
+// Broker 统计项名称（对应 Java BrokerStatsManager）
+const (
+	statsTopicPutNums = "TOPIC_PUT_NUMS"
+	statsTopicPutSize = "TOPIC_PUT_SIZE"
+	statsGroupGetNums = "GROUP_GET_NUMS"
+	statsGroupGetSize = "GROUP_GET_SIZE"
+)
+
+// runtimeMetric Broker 运行时统计项与指标的对应关系
+type runtimeMetric struct {
+	keys []string // 运行时统计 Key，按顺序取第一个存在的
+	name string
+	help string
+}
+
+// brokerRuntimeMetrics 从 FetchBrokerRuntimeStats 导出的指标
+var brokerRuntimeMetrics = []runtimeMetric{
+	{[]string{"putTps"}, "rocketmq_broker_put_tps", "Messages put per second (last 10s)."},
+	{[]string{"getTransferredTps", "getTransferedTps"}, "rocketmq_broker_get_tps", "Messages transferred to consumers per second (last 10s)."},
+	{[]string{"msgPutTotalTodayNow"}, "rocketmq_broker_msg_put_total_today", "Messages put since midnight."},
+	{[]string{"msgGetTotalTodayNow"}, "rocketmq_broker_msg_get_total_today", "Messages got since midnight."},
+	{[]string{"commitLogDiskRatio"}, "rocketmq_broker_commitlog_disk_ratio", "Disk usage ratio of the commit log directory."},
+	{[]string{"consumeQueueDiskRatio"}, "rocketmq_broker_consumequeue_disk_ratio", "Disk usage ratio of the consume queue directory."},
+	{[]string{"commitLogMaxOffset"}, "rocketmq_broker_commitlog_max_offset", "Max physical offset of the commit log."},
+	{[]string{"commitLogMinOffset"}, "rocketmq_broker_commitlog_min_offset", "Min physical offset of the commit log."},
+	{[]string{"dispatchBehindBytes"}, "rocketmq_broker_dispatch_behind_bytes", "Bytes not yet dispatched to consume queues."},
+	{[]string{"pageCacheLockTimeMills"}, "rocketmq_broker_pagecache_lock_time_ms", "Current page cache lock time in milliseconds."},
+	{[]string{"sendThreadPoolQueueSize"}, "rocketmq_broker_send_threadpool_queue_size", "Pending requests in the send thread pool."},
+	{[]string{"pullThreadPoolQueueSize"}, "rocketmq_broker_pull_threadpool_queue_size", "Pending requests in the pull thread pool."},
+	{[]string{"queryThreadPoolQueueSize"}, "rocketmq_broker_query_threadpool_queue_size", "Pending requests in the query thread pool."},
+	{[]string{"earliestMessageTimeStamp"}, "rocketmq_broker_earliest_message_timestamp", "Store timestamp of the earliest message in milliseconds."},
+}
+
+// brokerInfo Broker 实例
+type brokerInfo struct {
+	cluster    string
+	brokerName string
+	brokerId   string
+	addr       string
+}
+
+// labels 返回 Broker 标签
+func (b brokerInfo) labels() []string {
+	return []string{"cluster", b.cluster, "broker", b.brokerName, "broker_id", b.brokerId, "addr", b.addr}
+}
+
+// collection 单次采集上下文
+type collection struct {
+	e       *Exporter
+	metrics *metricSet
+	errors  atomic.Int64
+
+	// masters key 为 BrokerName
+	masters map[string]brokerInfo
+}
+
+// collectAll 执行一次完整采集
+func (e *Exporter) collectAll(ctx context.Context) *metricSet {
+	c := &collection{
+		e:       e,
+		metrics: newMetricSet(),
+		masters: make(map[string]brokerInfo),
+	}
+
+	clusterInfo, err := e.client.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		c.metrics.gauge("rocketmq_up", "Whether the NameServer could be reached.", 0)
+		c.metrics.gauge("rocketmq_exporter_scrape_errors", "Number of failed requests during the last collection.", 1)
+		return c.metrics
+	}
+	c.metrics.gauge("rocketmq_up", "Whether the NameServer could be reached.", 1)
+
+	var brokers []brokerInfo
+	for brokerName, brokerData := range clusterInfo.BrokerAddrTable {
+		for brokerId, addr := range brokerData.BrokerAddrs {
+			b := brokerInfo{cluster: brokerData.Cluster, brokerName: brokerName, brokerId: brokerId, addr: addr}
+			brokers = append(brokers, b)
+			if brokerId == "0" {
+				c.masters[brokerName] = b
+			}
+		}
+	}
+
+	var tasks []func(context.Context)
+	for _, b := range brokers {
+		tasks = append(tasks, func(ctx context.Context) { c.collectBrokerRuntime(ctx, b) })
+	}
+	for _, b := range c.masters {
+		tasks = append(tasks, func(ctx context.Context) { c.collectBrokerHA(ctx, b) })
+	}
+
+	if topicList, err := e.client.FetchAllTopicList(ctx); err != nil {
+		c.errors.Add(1)
+	} else {
+		for _, topic := range topicList.TopicList {
+			if !c.matchTopic(topic) {
+				continue
+			}
+			tasks = append(tasks, func(ctx context.Context) { c.collectTopic(ctx, topic) })
+		}
+	}
+
+	if groups, err := e.client.ListConsumerGroups(ctx); err != nil {
+		c.errors.Add(1)
+	} else {
+		for _, group := range groups {
+			if !e.groupFilter.match(group) {
+				continue
+			}
+			tasks = append(tasks, func(ctx context.Context) { c.collectGroup(ctx, group) })
+		}
+	}
+
+	runTasks(ctx, e.opts.Concurrency, tasks)
+
+	c.metrics.gauge("rocketmq_exporter_scrape_errors", "Number of failed requests during the last collection.", float64(c.errors.Load()))
+	return c.metrics
+}
+
+// runTasks 以固定并发执行任务，ctx 取消后不再启动新任务
+func runTasks(ctx context.Context, concurrency int, tasks []func(context.Context)) {
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			task(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// matchTopic 判断 Topic 是否需要采集
+func (c *collection) matchTopic(topic string) bool {
+	if !c.e.opts.IncludeSystemTopics && isSystemTopic(topic) {
+		return false
+	}
+	return c.e.topicFilter.match(topic)
+}
+
+// collectBrokerRuntime 采集 Broker 运行时统计
+func (c *collection) collectBrokerRuntime(ctx context.Context, b brokerInfo) {
+	stats, err := c.e.client.FetchBrokerRuntimeStats(ctx, b.addr)
+	if err != nil {
+		c.errors.Add(1)
+		c.metrics.gauge("rocketmq_broker_up", "Whether the broker could be reached.", 0, b.labels()...)
+		return
+	}
+	c.metrics.gauge("rocketmq_broker_up", "Whether the broker could be reached.", 1, b.labels()...)
+
+	for _, m := range brokerRuntimeMetrics {
+		for _, key := range m.keys {
+			raw, ok := stats.Table[key]
+			if !ok {
+				continue
+			}
+			if value, ok := parseRuntimeValue(raw); ok {
+				c.metrics.gauge(m.name, m.help, value, b.labels()...)
+			}
+			break
+		}
+	}
+}
+
+// collectBrokerHA 采集 Master 的主从同步状态
+func (c *collection) collectBrokerHA(ctx context.Context, b brokerInfo) {
+	status, err := c.e.client.GetBrokerHAStatus(ctx, b.addr)
+	if err != nil {
+		// 未开启主从复制或版本不支持时 Broker 返回错误，不计入采集失败
+		return
+	}
+
+	c.metrics.gauge("rocketmq_broker_ha_max_gap", "Max replication gap between master and slaves in bytes.", float64(status.HaMaxGap), b.labels()...)
+	c.metrics.gauge("rocketmq_broker_ha_in_sync_slaves", "Number of in-sync slaves.", float64(status.InSyncSlaveNum), b.labels()...)
+	for _, conn := range status.HaConnectionSet {
+		labels := append(b.labels(), "slave_addr", conn.Addr)
+		c.metrics.gauge("rocketmq_broker_ha_slave_diff", "Replication gap of the slave in bytes.", float64(conn.Diff), labels...)
+		c.metrics.gauge("rocketmq_broker_ha_slave_in_sync", "Whether the slave is in sync.", boolValue(conn.InSync), labels...)
+	}
+}
+
+// collectTopic 采集 Topic 偏移和生产统计
+func (c *collection) collectTopic(ctx context.Context, topic string) {
+	stats, err := c.e.client.ExamineTopicStats(ctx, topic)
+	if err != nil {
+		c.errors.Add(1)
+		return
+	}
+
+	type brokerOffset struct {
+		maxOffset   int64
+		minOffset   int64
+		queues      int
+		lastUpdated int64
+	}
+	offsets := make(map[string]*brokerOffset)
+	for key, offset := range stats.OffsetTable {
+		mq, err := admin.ParseMessageQueueKey(key)
+		if err != nil {
+			continue
+		}
+		agg, ok := offsets[mq.BrokerName]
+		if !ok {
+			agg = &brokerOffset{}
+			offsets[mq.BrokerName] = agg
+		}
+		agg.maxOffset += offset.MaxOffset
+		agg.minOffset += offset.MinOffset
+		agg.queues++
+		agg.lastUpdated = max(agg.lastUpdated, offset.LastUpdateTimestamp)
+	}
+
+	for brokerName, agg := range offsets {
+		b := c.master(brokerName)
+		labels := []string{"cluster", b.cluster, "broker", brokerName, "topic", topic}
+		c.metrics.gauge("rocketmq_producer_offset", "Sum of max offsets of the topic queues on the broker.", float64(agg.maxOffset), labels...)
+		c.metrics.gauge("rocketmq_topic_messages", "Messages retained by the topic on the broker.", float64(agg.maxOffset-agg.minOffset), labels...)
+		c.metrics.gauge("rocketmq_topic_queues", "Number of queues of the topic on the broker.", float64(agg.queues), labels...)
+		if agg.lastUpdated > 0 {
+			c.metrics.gauge("rocketmq_topic_last_update_timestamp", "Store timestamp of the latest message in milliseconds.", float64(agg.lastUpdated), labels...)
+		}
+
+		if c.e.opts.DisableBrokerStats || b.addr == "" {
+			continue
+		}
+		if data, err := c.e.client.ViewBrokerStatsData(ctx, b.addr, statsTopicPutNums, topic); err == nil {
+			c.metrics.gauge("rocketmq_producer_tps", "Messages put per second to the topic (last minute).", data.StatsMinute.Tps, labels...)
+		}
+		if data, err := c.e.client.ViewBrokerStatsData(ctx, b.addr, statsTopicPutSize, topic); err == nil {
+			c.metrics.gauge("rocketmq_producer_message_size", "Bytes put per second to the topic (last minute).", data.StatsMinute.Tps, labels...)
+		}
+	}
+}
+
+// collectGroup 采集消费组进度、堆积和消费统计
+func (c *collection) collectGroup(ctx context.Context, group string) {
+	stats, err := c.e.client.ExamineConsumeStats(ctx, group)
+	if err != nil {
+		c.errors.Add(1)
+		return
+	}
+
+	type groupOffset struct {
+		consumerOffset int64
+		lag            int64
+		timeLag        int64
+	}
+	type offsetKey struct {
+		topic      string
+		brokerName string
+	}
+	now := time.Now().UnixMilli()
+	offsets := make(map[offsetKey]*groupOffset)
+	for key, offset := range stats.OffsetTable {
+		mq, err := admin.ParseMessageQueueKey(key)
+		if err != nil || !c.matchTopic(mq.Topic) {
+			continue
+		}
+		k := offsetKey{topic: mq.Topic, brokerName: mq.BrokerName}
+		agg, ok := offsets[k]
+		if !ok {
+			agg = &groupOffset{}
+			offsets[k] = agg
+		}
+		lag := max(offset.BrokerOffset-offset.ConsumerOffset, 0)
+		agg.consumerOffset += offset.ConsumerOffset
+		agg.lag += lag
+		if lag > 0 && offset.LastTimestamp > 0 {
+			agg.timeLag = max(agg.timeLag, now-offset.LastTimestamp)
+		}
+	}
+
+	c.metrics.gauge("rocketmq_group_consume_tps", "Consume TPS reported by the brokers.", stats.ConsumeTps, "group", group)
+
+	for k, agg := range offsets {
+		b := c.master(k.brokerName)
+		labels := []string{"cluster", b.cluster, "broker", k.brokerName, "topic", k.topic, "group", group}
+		c.metrics.gauge("rocketmq_consumer_offset", "Sum of committed offsets of the group on the broker.", float64(agg.consumerOffset), labels...)
+		c.metrics.gauge("rocketmq_group_diff", "Message lag of the group on the broker.", float64(agg.lag), labels...)
+		c.metrics.gauge("rocketmq_group_time_lag_seconds", "Time lag of the group on the broker.", float64(agg.timeLag)/1000, labels...)
+
+		if c.e.opts.DisableBrokerStats || b.addr == "" {
+			continue
+		}
+		statsKey := k.topic + "@" + group
+		if data, err := c.e.client.ViewBrokerStatsData(ctx, b.addr, statsGroupGetNums, statsKey); err == nil {
+			c.metrics.gauge("rocketmq_consumer_tps", "Messages consumed per second (last minute).", data.StatsMinute.Tps, labels...)
+		}
+		if data, err := c.e.client.ViewBrokerStatsData(ctx, b.addr, statsGroupGetSize, statsKey); err == nil {
+			c.metrics.gauge("rocketmq_consumer_message_size", "Bytes consumed per second (last minute).", data.StatsMinute.Tps, labels...)
+		}
+	}
+}
+
+// master 返回 Broker 的 Master，不存在时只填充名称
+func (c *collection) master(brokerName string) brokerInfo {
+	if b, ok := c.masters[brokerName]; ok {
+		return b
+	}
+	return brokerInfo{brokerName: brokerName}
+}
+
+// parseRuntimeValue 解析运行时统计值，形如 "12.50 11.00 10.20" 时取第一个数
+func parseRuntimeValue(raw string) (float64, bool) {
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// boolValue 布尔值转换为指标值
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// isSystemTopic 判断是否为重试、死信或系统 Topic
+func isSystemTopic(topic string) bool {
+	return admin.IsSystemTopic(topic)
+}
@@ -0,0 +1,227 @@
+// Package exporter 以 Prometheus 文本格式暴露 RocketMQ 集群、Broker、Topic 和消费组指标
+//
+// 指标通过 admin.Client 采集，采集结果按 CacheTTL 缓存，并发抓取时共享同一次采集。
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+)
+
+// Options 导出器配置
+type Options struct {
+	// Concurrency 采集并发数
+	Concurrency int
+
+	// CacheTTL 采集结果缓存时间，0 表示每次抓取都重新采集
+	CacheTTL time.Duration
+
+	// ScrapeTimeout 单次采集超时时间
+	ScrapeTimeout time.Duration
+
+	// TopicAllow / TopicDeny Topic 允许/拒绝列表（正则，整串匹配，拒绝优先）
+	TopicAllow []string
+	TopicDeny  []string
+
+	// GroupAllow / GroupDeny 消费组允许/拒绝列表（正则，整串匹配，拒绝优先）
+	GroupAllow []string
+	GroupDeny  []string
+
+	// IncludeSystemTopics 是否采集重试、死信及系统 Topic
+	IncludeSystemTopics bool
+
+	// DisableBrokerStats 关闭 ViewBrokerStatsData 采集（每个 Topic、消费组都会产生额外请求）
+	DisableBrokerStats bool
+}
+
+// Option 导出器配置函数
+type Option func(*Options)
+
+// defaultOptions 返回默认配置
+func defaultOptions() *Options {
+	return &Options{
+		Concurrency:   8,
+		CacheTTL:      15 * time.Second,
+		ScrapeTimeout: 30 * time.Second,
+	}
+}
+
+// WithConcurrency 设置采集并发数
+func WithConcurrency(n int) Option {
+	return func(o *Options) {
+		o.Concurrency = n
+	}
+}
+
+// WithCacheTTL 设置采集结果缓存时间
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(o *Options) {
+		o.CacheTTL = ttl
+	}
+}
+
+// WithScrapeTimeout 设置单次采集超时时间
+func WithScrapeTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.ScrapeTimeout = timeout
+	}
+}
+
+// WithTopicFilter 设置 Topic 允许/拒绝列表
+func WithTopicFilter(allow, deny []string) Option {
+	return func(o *Options) {
+		o.TopicAllow = allow
+		o.TopicDeny = deny
+	}
+}
+
+// WithGroupFilter 设置消费组允许/拒绝列表
+func WithGroupFilter(allow, deny []string) Option {
+	return func(o *Options) {
+		o.GroupAllow = allow
+		o.GroupDeny = deny
+	}
+}
+
+// WithSystemTopics 设置是否采集系统 Topic
+func WithSystemTopics(include bool) Option {
+	return func(o *Options) {
+		o.IncludeSystemTopics = include
+	}
+}
+
+// WithoutBrokerStats 关闭 ViewBrokerStatsData 采集
+func WithoutBrokerStats() Option {
+	return func(o *Options) {
+		o.DisableBrokerStats = true
+	}
+}
+
+// Exporter Prometheus 指标导出器，实现 http.Handler
+type Exporter struct {
+	client      *admin.Client
+	opts        *Options
+	topicFilter *nameFilter
+	groupFilter *nameFilter
+
+	// collect 执行一次完整采集，测试时可替换
+	collect func(ctx context.Context) *metricSet
+
+	collectMu sync.Mutex
+	mu        sync.RWMutex
+	cached    []byte
+	cachedAt  time.Time
+}
+
+// New 创建导出器，client 需已启动
+func New(client *admin.Client, opts ...Option) (*Exporter, error) {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if client == nil {
+		return nil, errors.New("Client 不能为空")
+	}
+	if options.Concurrency <= 0 {
+		return nil, errors.New("采集并发数必须大于 0")
+	}
+
+	topicFilter, err := newNameFilter(options.TopicAllow, options.TopicDeny)
+	if err != nil {
+		return nil, err
+	}
+	groupFilter, err := newNameFilter(options.GroupAllow, options.GroupDeny)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Exporter{
+		client:      client,
+		opts:        options,
+		topicFilter: topicFilter,
+		groupFilter: groupFilter,
+	}
+	e.collect = e.collectAll
+	return e, nil
+}
+
+// ServeHTTP 输出 Prometheus 文本格式指标
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, err := e.Gather(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(data)
+}
+
+// Handler 返回挂载了 /metrics 的 HTTP 处理器
+func (e *Exporter) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<html><head><title>RocketMQ Exporter</title></head><body><h1>RocketMQ Exporter</h1><p><a href="/metrics">Metrics</a></p></body></html>`))
+	})
+	return mux
+}
+
+// Gather 返回 Prometheus 文本格式指标，缓存有效时直接返回缓存
+// 并发调用时只有一个调用方执行采集，其余等待并共享结果
+func (e *Exporter) Gather(ctx context.Context) ([]byte, error) {
+	if data, ok := e.fromCache(); ok {
+		return data, nil
+	}
+
+	e.collectMu.Lock()
+	defer e.collectMu.Unlock()
+
+	if data, ok := e.fromCache(); ok {
+		return data, nil
+	}
+
+	// 采集不随单个抓取请求取消，避免客户端断开导致其他等待方拿到不完整的结果
+	collectCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.opts.ScrapeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	metrics := e.collect(collectCtx)
+	metrics.gauge("rocketmq_exporter_scrape_duration_seconds", "Duration of the last collection.", time.Since(start).Seconds())
+
+	var buf bytes.Buffer
+	if err := metrics.writeTo(&buf); err != nil {
+		return nil, err
+	}
+
+	data := buf.Bytes()
+	e.mu.Lock()
+	e.cached = data
+	e.cachedAt = time.Now()
+	e.mu.Unlock()
+
+	return data, nil
+}
+
+// fromCache 读取未过期的缓存
+func (e *Exporter) fromCache() ([]byte, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.cached == nil || e.opts.CacheTTL <= 0 || time.Since(e.cachedAt) >= e.opts.CacheTTL {
+		return nil, false
+	}
+	return e.cached, true
+}
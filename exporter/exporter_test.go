@@ -0,0 +1,198 @@
+package exporter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+)
+
+// =============================================================================
+// 导出器单元测试
+// =============================================================================
+
+// TestMetricSetWriteTo 测试 Prometheus 文本格式输出
+func TestMetricSetWriteTo(t *testing.T) {
+	metrics := newMetricSet()
+	metrics.gauge("rocketmq_group_diff", "Message lag.", 10, "topic", "TopicB", "group", `G"1`)
+	metrics.gauge("rocketmq_group_diff", "Message lag.", 5, "topic", "TopicA", "group", "G\\2")
+	metrics.counter("rocketmq_a_total", "Line1\nLine2", 1.5)
+
+	var sb strings.Builder
+	if err := metrics.writeTo(&sb); err != nil {
+		t.Fatalf("输出指标失败: %v", err)
+	}
+
+	want := `# HELP rocketmq_a_total Line1\nLine2
+# TYPE rocketmq_a_total counter
+rocketmq_a_total 1.5
+# HELP rocketmq_group_diff Message lag.
+# TYPE rocketmq_group_diff gauge
+rocketmq_group_diff{topic="TopicA",group="G\\2"} 5
+rocketmq_group_diff{topic="TopicB",group="G\"1"} 10
+`
+	if sb.String() != want {
+		t.Errorf("输出不匹配:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
+
+// TestNameFilter 测试允许/拒绝列表
+func TestNameFilter(t *testing.T) {
+	f, err := newNameFilter([]string{"order_.*", "pay"}, []string{"order_test"})
+	if err != nil {
+		t.Fatalf("创建过滤器失败: %v", err)
+	}
+
+	cases := map[string]bool{
+		"order_created": true,
+		"order_test":    false,
+		"pay":           true,
+		"payment":       false,
+		"other":         false,
+	}
+	for name, want := range cases {
+		if got := f.match(name); got != want {
+			t.Errorf("match(%s) = %v, want %v", name, got, want)
+		}
+	}
+
+	if _, err := newNameFilter([]string{"("}, nil); err == nil {
+		t.Error("非法表达式应返回错误")
+	}
+}
+
+// TestParseRuntimeValue 测试解析运行时统计值
+func TestParseRuntimeValue(t *testing.T) {
+	if v, ok := parseRuntimeValue("12.50 11.00 10.20"); !ok || v != 12.5 {
+		t.Errorf("解析 putTps 失败: %v %v", v, ok)
+	}
+	if v, ok := parseRuntimeValue("1024"); !ok || v != 1024 {
+		t.Errorf("解析整数失败: %v %v", v, ok)
+	}
+	if _, ok := parseRuntimeValue("OK"); ok {
+		t.Error("非数字应解析失败")
+	}
+}
+
+// TestIsSystemTopic 测试系统 Topic 判断
+func TestIsSystemTopic(t *testing.T) {
+	for _, topic := range []string{"TBW102", "%RETRY%CG", "%DLQ%CG", "rmq_sys_wheel_timer"} {
+		if !isSystemTopic(topic) {
+			t.Errorf("%s 应为系统 Topic", topic)
+		}
+	}
+	if isSystemTopic("TopicTest") {
+		t.Error("TopicTest 不应为系统 Topic")
+	}
+}
+
+// TestExporter_Cache 测试缓存与并发抓取共享采集结果
+func TestExporter_Cache(t *testing.T) {
+	e, err := New(&admin.Client{}, WithCacheTTL(time.Minute))
+	if err != nil {
+		t.Fatalf("创建导出器失败: %v", err)
+	}
+
+	var calls atomic.Int32
+	e.collect = func(ctx context.Context) *metricSet {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		metrics := newMetricSet()
+		metrics.gauge("rocketmq_up", "up", 1)
+		return metrics
+	}
+
+	server := httptest.NewServer(e.Handler())
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get(server.URL + "/metrics")
+			if err != nil {
+				t.Errorf("抓取失败: %v", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if !strings.Contains(string(body), "rocketmq_up 1") {
+				t.Errorf("抓取结果不匹配: %s", body)
+			}
+			if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+				t.Errorf("Content-Type 不匹配: %s", ct)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("缓存有效期内应只采集 1 次, got %d", calls.Load())
+	}
+}
+
+// TestNew_Invalid 测试非法配置
+func TestNew_Invalid(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Error("Client 为空时应返回错误")
+	}
+	if _, err := New(&admin.Client{}, WithConcurrency(0)); err == nil {
+		t.Error("并发数为 0 时应返回错误")
+	}
+	if _, err := New(&admin.Client{}, WithTopicFilter([]string{"["}, nil)); err == nil {
+		t.Error("非法 Topic 过滤表达式应返回错误")
+	}
+}
+
+// =============================================================================
+// 导出器集成测试
+// =============================================================================
+
+// TestIntegration_Gather 测试对真实集群采集指标
+func TestIntegration_Gather(t *testing.T) {
+	if os.Getenv("ROCKETMQ_TEST_SKIP") == "true" {
+		t.Skip("跳过 RocketMQ 集成测试 (ROCKETMQ_TEST_SKIP=true)")
+	}
+	namesrv := os.Getenv("ROCKETMQ_NAMESRV_ADDR")
+	if namesrv == "" {
+		namesrv = "localhost:9876"
+	}
+
+	client, err := admin.NewClient(admin.WithNameServers([]string{namesrv}), admin.WithTimeout(3*time.Second))
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	if err := client.Start(); err != nil {
+		t.Fatalf("启动客户端失败: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if _, err := client.FetchAllTopicList(ctx); err != nil {
+		t.Skipf("跳过测试: RocketMQ 不可用: %v", err)
+	}
+
+	e, err := New(client, WithScrapeTimeout(10*time.Second))
+	if err != nil {
+		t.Fatalf("创建导出器失败: %v", err)
+	}
+
+	data, err := e.Gather(context.Background())
+	if err != nil {
+		t.Fatalf("采集指标失败: %v", err)
+	}
+	if !strings.Contains(string(data), "rocketmq_up 1") {
+		t.Errorf("采集结果应包含 rocketmq_up 1")
+	}
+	t.Logf("指标大小: %d 字节", len(data))
+}
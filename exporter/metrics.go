@@ -0,0 +1,198 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// =============================================================================
+// Prometheus 文本格式
+// =============================================================================
+
+// 指标类型
+const (
+	metricTypeGauge   = "gauge"
+	metricTypeCounter = "counter"
+)
+
+// label 指标标签
+type label struct {
+	name  string
+	value string
+}
+
+// sample 指标样本
+type sample struct {
+	labels []label
+	value  float64
+}
+
+// metricFamily 同名指标
+type metricFamily struct {
+	name       string
+	help       string
+	metricType string
+	samples    []sample
+}
+
+// metricSet 一次采集得到的全部指标，可并发写入
+type metricSet struct {
+	mu       sync.Mutex
+	families map[string]*metricFamily
+}
+
+// newMetricSet 创建指标集合
+func newMetricSet() *metricSet {
+	return &metricSet{families: make(map[string]*metricFamily)}
+}
+
+// add 添加样本，labels 按 name, value 成对传入
+func (s *metricSet) add(name, help, metricType string, value float64, labels ...string) {
+	sm := sample{value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		sm.labels = append(sm.labels, label{name: labels[i], value: labels[i+1]})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	family, ok := s.families[name]
+	if !ok {
+		family = &metricFamily{name: name, help: help, metricType: metricType}
+		s.families[name] = family
+	}
+	family.samples = append(family.samples, sm)
+}
+
+// gauge 添加 gauge 样本
+func (s *metricSet) gauge(name, help string, value float64, labels ...string) {
+	s.add(name, help, metricTypeGauge, value, labels...)
+}
+
+// counter 添加 counter 样本
+func (s *metricSet) counter(name, help string, value float64, labels ...string) {
+	s.add(name, help, metricTypeCounter, value, labels...)
+}
+
+// writeTo 按 Prometheus 文本格式输出，指标和样本均排序以保证输出稳定
+func (s *metricSet) writeTo(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.families))
+	for name := range s.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		family := s.families[name]
+		fmt.Fprintf(&sb, "# HELP %s %s\n", name, escapeHelp(family.help))
+		fmt.Fprintf(&sb, "# TYPE %s %s\n", name, family.metricType)
+
+		lines := make([]string, 0, len(family.samples))
+		for _, sm := range family.samples {
+			lines = append(lines, formatSample(name, sm))
+		}
+		sort.Strings(lines)
+		for _, line := range lines {
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// formatSample 格式化单个样本
+func formatSample(name string, sm sample) string {
+	var sb strings.Builder
+	sb.WriteString(name)
+	if len(sm.labels) > 0 {
+		sb.WriteByte('{')
+		for i, l := range sm.labels {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(l.name)
+			sb.WriteString(`="`)
+			sb.WriteString(escapeLabelValue(l.value))
+			sb.WriteByte('"')
+		}
+		sb.WriteByte('}')
+	}
+	sb.WriteByte(' ')
+	sb.WriteString(strconv.FormatFloat(sm.value, 'g', -1, 64))
+	return sb.String()
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+// escapeLabelValue 转义标签值
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
+
+// escapeHelp 转义帮助文本
+func escapeHelp(v string) string {
+	return helpEscaper.Replace(v)
+}
+
+// =============================================================================
+// 名称过滤
+// =============================================================================
+
+// nameFilter 基于正则的允许/拒绝列表，拒绝优先
+type nameFilter struct {
+	allow []*regexp.Regexp
+	deny  []*regexp.Regexp
+}
+
+// newNameFilter 编译允许/拒绝列表，允许列表为空表示全部允许
+// 每个表达式都按整串匹配
+func newNameFilter(allow, deny []string) (*nameFilter, error) {
+	f := &nameFilter{}
+	for _, pattern := range allow {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("允许列表表达式 %q 非法: %w", pattern, err)
+		}
+		f.allow = append(f.allow, re)
+	}
+	for _, pattern := range deny {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("拒绝列表表达式 %q 非法: %w", pattern, err)
+		}
+		f.deny = append(f.deny, re)
+	}
+	return f, nil
+}
+
+// match 判断名称是否需要采集
+func (f *nameFilter) match(name string) bool {
+	for _, re := range f.deny {
+		if re.MatchString(name) {
+			return false
+		}
+	}
+	if len(f.allow) == 0 {
+		return true
+	}
+	for _, re := range f.allow {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package gateway
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// =============================================================================
+// 认证与授权
+// =============================================================================
+
+// Access 访问级别
+type Access int
+
+const (
+	AccessNone      Access = iota // 无权限
+	AccessReadOnly                // 只能访问只读路由
+	AccessReadWrite               // 可以访问全部路由
+)
+
+// String 返回访问级别名称
+func (a Access) String() string {
+	switch a {
+	case AccessReadOnly:
+		return "read-only"
+	case AccessReadWrite:
+		return "read-write"
+	default:
+		return "none"
+	}
+}
+
+// ParseAccess 解析访问级别名称（read-only、read-write）
+func ParseAccess(s string) (Access, error) {
+	switch strings.ToLower(s) {
+	case "read-only", "ro":
+		return AccessReadOnly, nil
+	case "read-write", "rw":
+		return AccessReadWrite, nil
+	default:
+		return AccessNone, fmt.Errorf("未知的访问级别: %s", s)
+	}
+}
+
+// Principal 认证通过的调用方
+type Principal struct {
+	Name   string // 调用方名称，用于请求日志
+	Access Access // 访问级别
+}
+
+// Authenticator 认证器，返回错误时请求以 401 拒绝
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// AuthenticatorFunc 函数形式的认证器
+type AuthenticatorFunc func(r *http.Request) (*Principal, error)
+
+// Authenticate 实现 Authenticator 接口
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (*Principal, error) {
+	return f(r)
+}
+
+// ErrUnauthenticated 缺少或无效的凭据
+var ErrUnauthenticated = errors.New("缺少或无效的凭据")
+
+// NewTokenAuthenticator 创建 Bearer Token 认证器，key 为 Token
+func NewTokenAuthenticator(tokens map[string]Principal) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			return nil, ErrUnauthenticated
+		}
+		// 逐个常量时间比较，避免通过响应时间猜测 Token
+		var found *Principal
+		for t, p := range tokens {
+			if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				found = &p
+			}
+		}
+		if found == nil {
+			return nil, ErrUnauthenticated
+		}
+		return found, nil
+	})
+}
+
+// 未配置认证器时的匿名调用方，只有设置 WithAnonymousWrite 时才能访问读写路由
+var (
+	anonymousReader = &Principal{Name: "anonymous", Access: AccessReadOnly}
+	anonymousWriter = &Principal{Name: "anonymous", Access: AccessReadWrite}
+)
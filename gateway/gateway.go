@@ -0,0 +1,329 @@
+// Package gateway 以 REST/JSON 形式暴露 admin.Client 的运维接口
+//
+// 路由分为只读与读写两组，读写路由需要 AccessReadWrite 级别的调用方；
+// 认证器、附加中间件和请求日志均可配置，OpenAPI 文档由路由表生成，
+// 通过 GET /openapi.json 获取。
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+)
+
+// Options 网关配置
+type Options struct {
+	// Authenticator 认证器，为空时全部请求视为匿名调用方
+	Authenticator Authenticator
+
+	// AnonymousWrite 未配置认证器时允许匿名调用方访问读写路由，默认只能访问只读路由
+	AnonymousWrite bool
+
+	// ReadOnly 只注册只读路由
+	ReadOnly bool
+
+	// Middlewares 附加中间件，按顺序包裹在认证之外、请求日志之内
+	Middlewares []func(http.Handler) http.Handler
+
+	// Logger 请求日志，为空时不记录
+	Logger *log.Logger
+
+	// RequestTimeout 单个请求的超时时间
+	RequestTimeout time.Duration
+
+	// MaxBodyBytes 请求体大小上限
+	MaxBodyBytes int64
+}
+
+// Option 网关配置函数
+type Option func(*Options)
+
+// defaultOptions 返回默认配置
+func defaultOptions() *Options {
+	return &Options{
+		RequestTimeout: 30 * time.Second,
+		MaxBodyBytes:   4 << 20,
+	}
+}
+
+// WithAuthenticator 设置认证器
+func WithAuthenticator(a Authenticator) Option {
+	return func(o *Options) {
+		o.Authenticator = a
+	}
+}
+
+// WithAnonymousWrite 未配置认证器时允许匿名调用方访问读写路由
+//
+// 仅应在网关只对可信网络开放时使用。
+func WithAnonymousWrite() Option {
+	return func(o *Options) {
+		o.AnonymousWrite = true
+	}
+}
+
+// WithReadOnly 只开放只读路由
+func WithReadOnly() Option {
+	return func(o *Options) {
+		o.ReadOnly = true
+	}
+}
+
+// WithMiddleware 追加中间件
+func WithMiddleware(mw ...func(http.Handler) http.Handler) Option {
+	return func(o *Options) {
+		o.Middlewares = append(o.Middlewares, mw...)
+	}
+}
+
+// WithLogger 设置请求日志
+func WithLogger(l *log.Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+// WithRequestTimeout 设置单个请求的超时时间
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.RequestTimeout = timeout
+	}
+}
+
+// WithMaxBodyBytes 设置请求体大小上限
+func WithMaxBodyBytes(n int64) Option {
+	return func(o *Options) {
+		o.MaxBodyBytes = n
+	}
+}
+
+// Gateway REST/JSON 运维网关
+type Gateway struct {
+	client  *admin.Client
+	opts    *Options
+	routes  []*route
+	handler http.Handler
+	openapi []byte
+}
+
+// New 创建网关
+func New(client *admin.Client, opts ...Option) (*Gateway, error) {
+	if client == nil {
+		return nil, errors.New("Client 不能为空")
+	}
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.RequestTimeout <= 0 {
+		return nil, fmt.Errorf("请求超时时间必须大于 0: %s", options.RequestTimeout)
+	}
+
+	g := &Gateway{client: client, opts: options}
+	for _, r := range g.allRoutes() {
+		if options.ReadOnly && r.access == AccessReadWrite {
+			continue
+		}
+		g.routes = append(g.routes, r)
+	}
+
+	openapi, err := json.MarshalIndent(buildOpenAPI(g.routes, options.Authenticator != nil), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("生成 OpenAPI 文档失败: %w", err)
+	}
+	g.openapi = openapi
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", g.serveOpenAPI)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	for _, r := range g.routes {
+		mux.Handle(r.method+" "+r.path, g.authorize(r))
+	}
+
+	var h http.Handler = mux
+	for i := len(options.Middlewares) - 1; i >= 0; i-- {
+		h = options.Middlewares[i](h)
+	}
+	g.handler = g.logRequests(h)
+	return g, nil
+}
+
+// ServeHTTP 实现 http.Handler 接口
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.handler.ServeHTTP(w, r)
+}
+
+// serveOpenAPI 输出 OpenAPI 文档
+func (g *Gateway) serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(g.openapi)
+}
+
+// =============================================================================
+// 中间件
+// =============================================================================
+
+// principalKey 调用方在 context 中的 key
+type principalKey struct{}
+
+// PrincipalFromContext 返回当前请求的调用方，未认证时返回 nil
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// statusRecorder 记录响应状态码
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// requestInfo 认证结果，由 authorize 填写供请求日志使用
+type requestInfo struct {
+	principal *Principal
+}
+
+// requestInfoKey requestInfo 在 context 中的 key
+type requestInfoKey struct{}
+
+// logRequests 记录请求方法、路径、状态码、耗时和调用方
+func (g *Gateway) logRequests(next http.Handler) http.Handler {
+	if g.opts.Logger == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		info := &requestInfo{}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), requestInfoKey{}, info)))
+
+		name := "-"
+		if info.principal != nil {
+			name = info.principal.Name
+		}
+		g.opts.Logger.Printf("%s %s %d %s principal=%s remote=%s",
+			r.Method, r.URL.RequestURI(), rec.status, time.Since(start).Round(time.Microsecond), name, r.RemoteAddr)
+	})
+}
+
+// authorize 认证调用方并检查访问级别，通过后执行路由
+func (g *Gateway) authorize(rt *route) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal := anonymousReader
+		if g.opts.AnonymousWrite {
+			principal = anonymousWriter
+		}
+		if g.opts.Authenticator != nil {
+			p, err := g.opts.Authenticator.Authenticate(r)
+			if err != nil || p == nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="rocketmq-admin"`)
+				writeError(w, http.StatusUnauthorized, ErrUnauthenticated)
+				return
+			}
+			principal = p
+		}
+		if info, ok := r.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+			info.principal = principal
+		}
+		if principal.Access < rt.access {
+			writeError(w, http.StatusForbidden, fmt.Errorf("%s 需要 %s 权限", principal.Name, rt.access))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), g.opts.RequestTimeout)
+		defer cancel()
+		r = r.WithContext(context.WithValue(ctx, principalKey{}, principal))
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, g.opts.MaxBodyBytes)
+		}
+
+		result, err := rt.handle(r)
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		status := http.StatusOK
+		if s, ok := result.(interface{ statusCode() int }); ok {
+			status = s.statusCode()
+		}
+		writeJSON(w, status, result)
+	})
+}
+
+// =============================================================================
+// 响应与错误
+// =============================================================================
+
+// errorResponse 错误响应体
+type errorResponse struct {
+	Error string `json:"error"`          // 错误信息
+	Code  int    `json:"code,omitempty"` // RocketMQ 响应码
+}
+
+// badRequestError 请求参数错误
+type badRequestError struct {
+	msg string
+}
+
+func (e *badRequestError) Error() string {
+	return e.msg
+}
+
+// badRequest 创建请求参数错误
+func badRequest(format string, args ...any) error {
+	return &badRequestError{msg: fmt.Sprintf(format, args...)}
+}
+
+// errorStatus 将错误映射为 HTTP 状态码
+func errorStatus(err error) int {
+	var badReq *badRequestError
+	var maxBytes *http.MaxBytesError
+	var adminErr *admin.AdminError
+	switch {
+	case errors.As(err, &badReq):
+		return http.StatusBadRequest
+	case errors.As(err, &maxBytes):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, admin.ErrTopicNotFound), errors.Is(err, admin.ErrConsumerGroupNotFound),
+		errors.Is(err, admin.ErrBrokerNotFound), errors.Is(err, admin.ErrMessageNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.As(err, &adminErr):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError 输出错误响应
+func writeError(w http.ResponseWriter, status int, err error) {
+	resp := errorResponse{Error: err.Error()}
+	var adminErr *admin.AdminError
+	if errors.As(err, &adminErr) {
+		resp.Code = adminErr.Code
+	}
+	writeJSON(w, status, resp)
+}
+
+// writeJSON 输出 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
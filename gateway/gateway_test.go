@@ -0,0 +1,410 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// 网关单元测试
+// =============================================================================
+
+// TestOperationId 测试操作 ID 生成
+func TestOperationId(t *testing.T) {
+	cases := map[string]*route{
+		"getTopicsTopicRoute":        {method: "GET", path: "/api/v1/topics/{topic}/route"},
+		"postGroupsGroupResetOffset": {method: "POST", path: "/api/v1/groups/{group}/reset-offset"},
+		"getLag":                     {method: "GET", path: "/api/v1/lag"},
+	}
+	for want, r := range cases {
+		if got := operationId(r); got != want {
+			t.Errorf("operationId(%s %s) = %s, want %s", r.method, r.path, got, want)
+		}
+	}
+}
+
+// TestSchemaOf 测试匿名嵌入字段展开与基础类型映射
+func TestSchemaOf(t *testing.T) {
+	schema := schemaOf(reflect.TypeOf(TopicRequest{}))
+	props := schema["properties"].(map[string]any)
+	for _, name := range []string{"clusterName", "topicName", "readQueueNums", "order"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("TopicRequest 文档缺少字段 %s", name)
+		}
+	}
+	if typ := props["order"].(map[string]any)["type"]; typ != "boolean" {
+		t.Errorf("order 类型应为 boolean, got %v", typ)
+	}
+
+	body := schemaOf(reflect.TypeOf(admin.MessageExt{}))["properties"].(map[string]any)["body"].(map[string]any)
+	if body["format"] != "byte" {
+		t.Errorf("[]byte 应映射为 base64 字符串: %v", body)
+	}
+}
+
+// TestErrorStatus 测试错误到 HTTP 状态码的映射
+func TestErrorStatus(t *testing.T) {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{badRequest("x"), http.StatusBadRequest},
+		{admin.ErrTopicNotFound, http.StatusNotFound},
+		{admin.NewAdminError(1, "x"), http.StatusBadGateway},
+		{io.EOF, http.StatusInternalServerError},
+	}
+	for _, c := range cases {
+		if got := errorStatus(c.err); got != c.want {
+			t.Errorf("errorStatus(%v) = %d, want %d", c.err, got, c.want)
+		}
+	}
+}
+
+// TestBrokersResultStatus 测试多 Broker 结果的状态码
+func TestBrokersResultStatus(t *testing.T) {
+	if s := (&BrokersResult{Succeeded: 2}).statusCode(); s != http.StatusOK {
+		t.Errorf("全部成功应返回 200, got %d", s)
+	}
+	if s := (&BrokersResult{Succeeded: 1, Failed: 1}).statusCode(); s != http.StatusMultiStatus {
+		t.Errorf("部分失败应返回 207, got %d", s)
+	}
+	if s := (&BrokersResult{Failed: 2}).statusCode(); s != http.StatusBadGateway {
+		t.Errorf("全部失败应返回 502, got %d", s)
+	}
+}
+
+// TestResetOffsetResultStatus 测试重置位点结果的状态码
+func TestResetOffsetResultStatus(t *testing.T) {
+	queues := []admin.QueueOffsetResult{{NewOffset: 1}, {NewOffset: 2}}
+	if s := (&ResetOffsetResult{Queues: queues}).statusCode(); s != http.StatusOK {
+		t.Errorf("全部成功应返回 200, got %d", s)
+	}
+	if s := (&ResetOffsetResult{Queues: queues, Failed: []BrokerResult{{BrokerName: "broker-b"}}}).statusCode(); s != http.StatusMultiStatus {
+		t.Errorf("部分 Broker 失败应返回 207, got %d", s)
+	}
+	if s := (&ResetOffsetResult{Queues: []admin.QueueOffsetResult{{Error: "x"}}}).statusCode(); s != http.StatusBadGateway {
+		t.Errorf("全部队列失败应返回 502, got %d", s)
+	}
+}
+
+// =============================================================================
+// 网关端到端测试（本地模拟 NameServer/Broker）
+// =============================================================================
+
+// newFakeCluster 启动同时充当 NameServer 和 broker-a Master 的模拟服务端
+func newFakeCluster(t *testing.T) *remotingtest.Server {
+	t.Helper()
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.Handle(remoting.GetBrokerClusterInfo, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"brokerAddrTable":{"broker-a":{"cluster":"DefaultCluster","brokerName":"broker-a","brokerAddrs":{0:"` +
+			srv.Addr + `"}}},"clusterAddrTable":{"DefaultCluster":["broker-a"]}}`))
+	})
+	srv.Handle(remoting.GetAllTopicListFromNamesrv, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string][]string{"topicList": {"TopicB", "TopicA"}})
+	})
+	srv.Handle(remoting.GetRouteInfoByTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		if req.ExtFields["topic"] != "TopicA" {
+			return remotingtest.Error(remoting.TopicNotExist, "No topic route info")
+		}
+		return remotingtest.Success([]byte(`{"queueDatas":[{"brokerName":"broker-a","readQueueNums":2,"writeQueueNums":2,"perm":6}],` +
+			`"brokerDatas":[{"cluster":"DefaultCluster","brokerName":"broker-a","brokerAddrs":{0:"` + srv.Addr + `"}}]}`))
+	})
+	srv.Handle(remoting.GetTopicStatsInfo, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"offsetTable":{` +
+			`{"brokerName":"broker-a","queueId":1,"topic":"TopicA"}:{"minOffset":0,"maxOffset":7,"lastUpdateTimestamp":0},` +
+			`{"brokerName":"broker-a","queueId":0,"topic":"TopicA"}:{"minOffset":0,"maxOffset":10,"lastUpdateTimestamp":0}}}`))
+	})
+	srv.Handle(remoting.UpdateAndCreateTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		if req.ExtFields["topic"] == "Forbidden" {
+			return remotingtest.Error(remoting.NoPermission, "no permission")
+		}
+		return remotingtest.Success(nil)
+	})
+	srv.Handle(remoting.GetAllSubscriptionGroup, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"subscriptionGroupTable": map[string]any{
+			"GroupA":         map[string]any{"groupName": "GroupA"},
+			"TOOLS_CONSUMER": map[string]any{"groupName": "TOOLS_CONSUMER"},
+		}})
+	})
+	return srv
+}
+
+// newTestGateway 创建连接模拟集群的网关
+func newTestGateway(t *testing.T, srv *remotingtest.Server, opts ...Option) *httptest.Server {
+	t.Helper()
+	client, err := admin.NewClient(admin.WithNameServers([]string{srv.Addr}), admin.WithTimeout(2*time.Second))
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	if err := client.Start(); err != nil {
+		t.Fatalf("启动客户端失败: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	g, err := New(client, opts...)
+	if err != nil {
+		t.Fatalf("创建网关失败: %v", err)
+	}
+	server := httptest.NewServer(g)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// syncBuffer 并发安全的日志缓冲区
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// doRequest 发送请求并返回状态码和响应体
+func doRequest(t *testing.T, method, url, token string, body any) (int, []byte) {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		if s, ok := body.(string); ok {
+			reader = strings.NewReader(s)
+		} else {
+			data, _ := json.Marshal(body)
+			reader = bytes.NewReader(data)
+		}
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("创建请求失败: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, data
+}
+
+// TestGateway_ReadRoutes 测试只读路由
+func TestGateway_ReadRoutes(t *testing.T) {
+	srv := newFakeCluster(t)
+	server := newTestGateway(t, srv)
+
+	status, body := doRequest(t, "GET", server.URL+"/api/v1/topics", "", nil)
+	var topics []string
+	if status != http.StatusOK || json.Unmarshal(body, &topics) != nil || !reflect.DeepEqual(topics, []string{"TopicA", "TopicB"}) {
+		t.Errorf("Topic 列表不匹配: %d %s", status, body)
+	}
+
+	status, body = doRequest(t, "GET", server.URL+"/api/v1/topics/TopicA/stats", "", nil)
+	var queues []QueueStats
+	if status != http.StatusOK || json.Unmarshal(body, &queues) != nil || len(queues) != 2 {
+		t.Fatalf("Topic 统计不匹配: %d %s", status, body)
+	}
+	if queues[0].QueueId != 0 || queues[0].MaxOffset != 10 || queues[1].MaxOffset != 7 {
+		t.Errorf("队列应按 ID 排序: %+v", queues)
+	}
+
+	status, body = doRequest(t, "GET", server.URL+"/api/v1/topics/Missing/route", "", nil)
+	if status != http.StatusNotFound {
+		t.Errorf("不存在的 Topic 应返回 404, got %d %s", status, body)
+	}
+
+	status, body = doRequest(t, "GET", server.URL+"/api/v1/brokers", "", nil)
+	var brokers []BrokerInfo
+	if status != http.StatusOK || json.Unmarshal(body, &brokers) != nil || len(brokers) != 1 || brokers[0].Addr != srv.Addr {
+		t.Errorf("Broker 列表不匹配: %d %s", status, body)
+	}
+
+	status, body = doRequest(t, "GET", server.URL+"/api/v1/groups", "", nil)
+	if status != http.StatusOK || strings.TrimSpace(string(body)) != "[\n  \"GroupA\"\n]" {
+		t.Errorf("消费组列表应排除系统消费组: %d %s", status, body)
+	}
+
+	status, _ = doRequest(t, "GET", server.URL+"/api/v1/messages?topic=TopicA", "", nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("缺少 key 应返回 400, got %d", status)
+	}
+}
+
+// TestGateway_WriteRoutes 测试读写路由、请求体校验与多 Broker 结果
+func TestGateway_WriteRoutes(t *testing.T) {
+	srv := newFakeCluster(t)
+	server := newTestGateway(t, srv, WithAnonymousWrite())
+
+	// 未配置认证器时匿名调用方默认只能访问只读路由
+	anonymous := newTestGateway(t, srv)
+	if status, body := doRequest(t, "POST", anonymous.URL+"/api/v1/topics", "", TopicRequest{
+		TopicConfig: admin.TopicConfig{TopicName: "TopicC", ReadQueueNums: 4, WriteQueueNums: 4},
+	}); status != http.StatusForbidden {
+		t.Errorf("匿名调用方访问读写路由应返回 403, got %d %s", status, body)
+	}
+
+	status, body := doRequest(t, "POST", server.URL+"/api/v1/topics", "", TopicRequest{
+		ClusterName: "DefaultCluster",
+		TopicConfig: admin.TopicConfig{TopicName: "TopicC", ReadQueueNums: 4, WriteQueueNums: 4},
+	})
+	var result BrokersResult
+	if status != http.StatusOK || json.Unmarshal(body, &result) != nil || result.Succeeded != 1 {
+		t.Fatalf("创建 Topic 失败: %d %s", status, body)
+	}
+	reqs := srv.Requests(remoting.UpdateAndCreateTopic)
+	if len(reqs) != 1 || reqs[0].ExtFields["topic"] != "TopicC" || reqs[0].ExtFields["perm"] != "6" {
+		t.Errorf("Broker 收到的请求不匹配: %+v", reqs)
+	}
+
+	status, body = doRequest(t, "POST", server.URL+"/api/v1/topics", "", TopicRequest{
+		TopicConfig: admin.TopicConfig{TopicName: "Forbidden", ReadQueueNums: 4, WriteQueueNums: 4},
+	})
+	if status != http.StatusBadGateway || !strings.Contains(string(body), "no permission") {
+		t.Errorf("全部 Broker 失败应返回 502: %d %s", status, body)
+	}
+
+	status, _ = doRequest(t, "POST", server.URL+"/api/v1/topics", "", `{"topicName":"T","readQueueNums":1,"writeQueueNums":1,"unknown":1}`)
+	if status != http.StatusBadRequest {
+		t.Errorf("未知字段应返回 400, got %d", status)
+	}
+
+	status, _ = doRequest(t, "POST", server.URL+"/api/v1/topics", "", TopicRequest{ClusterName: "NoSuchCluster",
+		TopicConfig: admin.TopicConfig{TopicName: "T", ReadQueueNums: 1, WriteQueueNums: 1}})
+	if status != http.StatusBadRequest {
+		t.Errorf("不存在的集群应返回 400, got %d", status)
+	}
+}
+
+// TestGateway_Auth 测试认证、访问级别与请求日志
+func TestGateway_Auth(t *testing.T) {
+	srv := newFakeCluster(t)
+	logBuf := &syncBuffer{}
+	server := newTestGateway(t, srv,
+		WithAuthenticator(NewTokenAuthenticator(map[string]Principal{
+			"ro-token": {Name: "viewer", Access: AccessReadOnly},
+			"rw-token": {Name: "operator", Access: AccessReadWrite},
+		})),
+		WithLogger(log.New(logBuf, "", 0)),
+		WithMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Test-Middleware", "1")
+				next.ServeHTTP(w, r)
+			})
+		}),
+	)
+
+	if status, _ := doRequest(t, "GET", server.URL+"/api/v1/topics", "", nil); status != http.StatusUnauthorized {
+		t.Errorf("缺少 Token 应返回 401, got %d", status)
+	}
+	if status, _ := doRequest(t, "GET", server.URL+"/api/v1/topics", "bad", nil); status != http.StatusUnauthorized {
+		t.Errorf("错误 Token 应返回 401, got %d", status)
+	}
+	if status, _ := doRequest(t, "GET", server.URL+"/api/v1/topics", "ro-token", nil); status != http.StatusOK {
+		t.Errorf("只读 Token 访问只读路由应返回 200, got %d", status)
+	}
+
+	topic := TopicRequest{TopicConfig: admin.TopicConfig{TopicName: "TopicC", ReadQueueNums: 1, WriteQueueNums: 1}}
+	if status, _ := doRequest(t, "POST", server.URL+"/api/v1/topics", "ro-token", topic); status != http.StatusForbidden {
+		t.Errorf("只读 Token 访问读写路由应返回 403, got %d", status)
+	}
+	if status, body := doRequest(t, "POST", server.URL+"/api/v1/topics", "rw-token", topic); status != http.StatusOK {
+		t.Errorf("读写 Token 访问读写路由应返回 200, got %d %s", status, body)
+	}
+	if status, _ := doRequest(t, "GET", server.URL+"/healthz", "", nil); status != http.StatusOK {
+		t.Errorf("健康检查无需认证, got %d", status)
+	}
+
+	resp, err := http.Get(server.URL + "/openapi.json")
+	if err != nil {
+		t.Fatalf("获取 OpenAPI 文档失败: %v", err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("X-Test-Middleware") != "1" {
+		t.Error("附加中间件未生效")
+	}
+
+	// 日志在响应写出后记录，等待最后一个请求的日志落盘
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(logBuf.String(), "/openapi.json") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	logs := logBuf.String()
+	if !strings.Contains(logs, "POST /api/v1/topics 200") || !strings.Contains(logs, "principal=operator") {
+		t.Errorf("请求日志不匹配:\n%s", logs)
+	}
+	if !strings.Contains(logs, "GET /api/v1/topics 401") {
+		t.Errorf("请求日志应包含认证失败的请求:\n%s", logs)
+	}
+}
+
+// TestGateway_ReadOnly 测试只读模式与 OpenAPI 文档
+func TestGateway_ReadOnly(t *testing.T) {
+	srv := newFakeCluster(t)
+	full := newTestGateway(t, srv)
+	readOnly := newTestGateway(t, srv, WithReadOnly())
+
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	_, body := doRequest(t, "GET", full.URL+"/openapi.json", "", nil)
+	if err := json.Unmarshal(body, &doc); err != nil || doc.OpenAPI != "3.0.3" {
+		t.Fatalf("OpenAPI 文档无效: %v", err)
+	}
+	if _, ok := doc.Paths["/api/v1/topics"]["post"]; !ok {
+		t.Error("完整模式文档应包含 POST /api/v1/topics")
+	}
+	if _, ok := doc.Paths["/api/v1/groups/{group}/lag"]["get"]; !ok {
+		t.Error("文档应包含 GET /api/v1/groups/{group}/lag")
+	}
+
+	_, body = doRequest(t, "GET", readOnly.URL+"/openapi.json", "", nil)
+	doc.Paths = nil
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("OpenAPI 文档无效: %v", err)
+	}
+	if _, ok := doc.Paths["/api/v1/topics"]["post"]; ok {
+		t.Error("只读模式文档不应包含 POST /api/v1/topics")
+	}
+
+	topic := TopicRequest{TopicConfig: admin.TopicConfig{TopicName: "TopicC", ReadQueueNums: 1, WriteQueueNums: 1}}
+	if status, _ := doRequest(t, "POST", readOnly.URL+"/api/v1/topics", "", topic); status != http.StatusMethodNotAllowed {
+		t.Errorf("只读模式下读写路由应返回 405, got %d", status)
+	}
+}
+
+// TestParseAccess 测试访问级别解析
+func TestParseAccess(t *testing.T) {
+	if a, err := ParseAccess("read-only"); err != nil || a != AccessReadOnly {
+		t.Errorf("解析 read-only 失败: %v %v", a, err)
+	}
+	if a, err := ParseAccess("RW"); err != nil || a != AccessReadWrite {
+		t.Errorf("解析 RW 失败: %v %v", a, err)
+	}
+	if _, err := ParseAccess("admin"); err == nil {
+		t.Error("未知访问级别应返回错误")
+	}
+}
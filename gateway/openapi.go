@@ -0,0 +1,171 @@
+package gateway
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// =============================================================================
+// OpenAPI 文档生成
+// =============================================================================
+
+// pathParamPattern 匹配路径参数 {name}
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// buildOpenAPI 根据路由表生成 OpenAPI 3.0 文档
+func buildOpenAPI(routes []*route, secured bool) map[string]any {
+	paths := make(map[string]map[string]any)
+	for _, r := range routes {
+		op := map[string]any{
+			"summary":     r.summary,
+			"tags":        []string{r.tag},
+			"operationId": operationId(r),
+			"x-access":    r.access.String(),
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "成功",
+					"content":     jsonContent(schemaOf(reflect.TypeOf(r.response))),
+				},
+				"default": map[string]any{
+					"description": "错误",
+					"content":     jsonContent(schemaOf(reflect.TypeOf(errorResponse{}))),
+				},
+			},
+		}
+
+		var params []map[string]any
+		for _, m := range pathParamPattern.FindAllStringSubmatch(r.path, -1) {
+			params = append(params, map[string]any{
+				"name":     m[1],
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]any{"type": "string"},
+			})
+		}
+		for _, p := range r.query {
+			params = append(params, map[string]any{
+				"name":        p.name,
+				"in":          "query",
+				"description": p.desc,
+				"required":    p.required,
+				"schema":      map[string]any{"type": p.typ},
+			})
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+		if r.request != nil {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content":  jsonContent(schemaOf(reflect.TypeOf(r.request))),
+			}
+		}
+
+		if paths[r.path] == nil {
+			paths[r.path] = make(map[string]any)
+		}
+		paths[r.path][strings.ToLower(r.method)] = op
+	}
+
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "RocketMQ Admin API",
+			"version":     "v1",
+			"description": "RocketMQ 运维管理 REST/JSON 接口。x-access 为 read-write 的接口需要读写权限。",
+		},
+		"paths": paths,
+	}
+	if secured {
+		doc["components"] = map[string]any{
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		}
+		doc["security"] = []map[string][]string{{"bearerAuth": {}}}
+	}
+	return doc
+}
+
+// operationId 由方法和路径生成操作 ID，如 GET /api/v1/topics/{topic}/route -> getTopicsTopicRoute
+func operationId(r *route) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(r.method))
+	for _, seg := range strings.Split(strings.TrimPrefix(r.path, "/api/v1"), "/") {
+		seg = strings.Trim(seg, "{}")
+		for _, part := range strings.Split(seg, "-") {
+			if part != "" {
+				sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+			}
+		}
+	}
+	return sb.String()
+}
+
+// jsonContent 返回 application/json 内容描述
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// schemaOf 通过反射生成 JSON Schema，字段名取 json 标签，匿名嵌入字段展开
+func schemaOf(t reflect.Type) map[string]any {
+	return schemaOfDepth(t, 0)
+}
+
+func schemaOfDepth(t reflect.Type, depth int) map[string]any {
+	if t == nil || depth > 8 {
+		return map[string]any{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": schemaOfDepth(t.Elem(), depth+1)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOfDepth(t.Elem(), depth+1)}
+	case reflect.Struct:
+		props := make(map[string]any)
+		collectProperties(t, props, depth)
+		return map[string]any{"type": "object", "properties": props}
+	default:
+		return map[string]any{}
+	}
+}
+
+// collectProperties 收集结构体字段，匿名嵌入的结构体字段提升到当前层级
+func collectProperties(t reflect.Type, props map[string]any, depth int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			collectProperties(f.Type, props, depth)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = schemaOfDepth(f.Type, depth+1)
+	}
+}
@@ -0,0 +1,655 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	admin "github.com/codermast/rocketmq-admin-go"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+)
+
+// =============================================================================
+// 路由表
+// =============================================================================
+
+// route 单个 REST 路由，同时用于注册处理函数和生成 OpenAPI 文档
+type route struct {
+	method   string
+	path     string // 路径模式，路径参数形如 {topic}
+	access   Access // 所需访问级别
+	tag      string
+	summary  string
+	query    []param // query 参数
+	request  any     // 请求体类型（零值），用于生成文档
+	response any     // 响应体类型（零值），用于生成文档
+	handle   func(r *http.Request) (any, error)
+}
+
+// param query 参数
+type param struct {
+	name     string
+	typ      string // string、integer、boolean
+	desc     string
+	required bool
+}
+
+// BrokerInfo Broker 节点
+type BrokerInfo struct {
+	Cluster    string `json:"cluster"`    // 集群名称
+	BrokerName string `json:"brokerName"` // Broker 名称
+	BrokerId   string `json:"brokerId"`   // Broker ID，0 为 Master
+	Addr       string `json:"addr"`       // 地址
+}
+
+// QueueStats 队列偏移统计
+type QueueStats struct {
+	admin.MessageQueue
+	MinOffset           int64 `json:"minOffset"`           // 最小偏移
+	MaxOffset           int64 `json:"maxOffset"`           // 最大偏移
+	LastUpdateTimestamp int64 `json:"lastUpdateTimestamp"` // 最后更新时间（毫秒）
+}
+
+// TopicRequest 创建或更新 Topic 的请求
+// ClusterName 和 BrokerAddr 都为空时作用于全部集群的 Master
+type TopicRequest struct {
+	ClusterName string `json:"clusterName,omitempty"` // 集群名称
+	BrokerAddr  string `json:"brokerAddr,omitempty"`  // Broker 地址
+	admin.TopicConfig
+}
+
+// GroupRequest 创建或更新订阅组的请求
+// ClusterName 和 BrokerAddr 都为空时作用于全部集群的 Master
+type GroupRequest struct {
+	ClusterName string `json:"clusterName,omitempty"` // 集群名称
+	BrokerAddr  string `json:"brokerAddr,omitempty"`  // Broker 地址
+	admin.SubscriptionGroupConfig
+}
+
+// ResetOffsetRequest 按时间重置位点的请求
+type ResetOffsetRequest struct {
+	Topic     string `json:"topic"`     // Topic
+	Timestamp int64  `json:"timestamp"` // 目标时间（毫秒）
+	Force     bool   `json:"force"`     // 是否允许回退位点
+}
+
+// ResetOffsetResult 重置消费位点的结果
+// 全部成功返回 200，部分 Broker 或队列失败返回 207，全部失败返回 502
+type ResetOffsetResult struct {
+	Queues []admin.QueueOffsetResult `json:"queues"`           // 各队列的新旧位点
+	Failed []BrokerResult            `json:"failed,omitempty"` // 失败的 Broker
+}
+
+func (r *ResetOffsetResult) statusCode() int {
+	failedQueues := 0
+	for _, q := range r.Queues {
+		if q.Error != "" {
+			failedQueues++
+		}
+	}
+	switch {
+	case len(r.Failed) == 0 && failedQueues == 0:
+		return http.StatusOK
+	case len(r.Queues) == failedQueues:
+		return http.StatusBadGateway
+	default:
+		return http.StatusMultiStatus
+	}
+}
+
+// SendMessageRequest 发送消息的请求
+type SendMessageRequest struct {
+	Topic      string            `json:"topic"`                // Topic
+	Body       string            `json:"body"`                 // 消息体（UTF-8 文本）
+	Tags       string            `json:"tags,omitempty"`       // Tag
+	Keys       []string          `json:"keys,omitempty"`       // Key
+	DelayLevel int               `json:"delayLevel,omitempty"` // 延时等级
+	Properties map[string]string `json:"properties,omitempty"` // 自定义属性
+}
+
+// BrokerResult 单个 Broker 上的操作结果
+type BrokerResult struct {
+	BrokerName string `json:"brokerName"`      // Broker 名称
+	Addr       string `json:"addr"`            // Broker 地址
+	Error      string `json:"error,omitempty"` // 失败原因
+}
+
+// BrokersResult 多个 Broker 上的操作结果
+// 全部成功返回 200，部分失败返回 207，全部失败返回 502
+type BrokersResult struct {
+	Succeeded int            `json:"succeeded"` // 成功数
+	Failed    int            `json:"failed"`    // 失败数
+	Brokers   []BrokerResult `json:"brokers"`   // 各 Broker 结果
+}
+
+func (r *BrokersResult) statusCode() int {
+	switch {
+	case r.Failed == 0:
+		return http.StatusOK
+	case r.Succeeded == 0:
+		return http.StatusBadGateway
+	default:
+		return http.StatusMultiStatus
+	}
+}
+
+// allRoutes 返回全部路由
+func (g *Gateway) allRoutes() []*route {
+	const (
+		tagCluster = "cluster"
+		tagBroker  = "broker"
+		tagTopic   = "topic"
+		tagGroup   = "group"
+		tagMessage = "message"
+	)
+	return []*route{
+		// 只读路由
+		{method: "GET", path: "/api/v1/clusters", access: AccessReadOnly, tag: tagCluster,
+			summary: "查询集群信息", response: admin.ClusterInfo{}, handle: g.getClusters},
+		{method: "GET", path: "/api/v1/brokers", access: AccessReadOnly, tag: tagBroker,
+			summary: "查询全部 Broker 节点", response: []BrokerInfo{}, handle: g.listBrokers,
+			query: []param{{name: "cluster", typ: "string", desc: "只返回指定集群"}}},
+		{method: "GET", path: "/api/v1/brokers/{addr}/runtime", access: AccessReadOnly, tag: tagBroker,
+			summary: "查询 Broker 运行时统计", response: map[string]string{}, handle: g.getBrokerRuntime},
+		{method: "GET", path: "/api/v1/brokers/{addr}/config", access: AccessReadOnly, tag: tagBroker,
+			summary: "查询 Broker 配置", response: map[string]string{}, handle: g.getBrokerConfig},
+		{method: "GET", path: "/api/v1/topics", access: AccessReadOnly, tag: tagTopic,
+			summary: "查询 Topic 列表", response: []string{}, handle: g.listTopics,
+			query: []param{{name: "cluster", typ: "string", desc: "只返回指定集群的 Topic"}}},
+		{method: "GET", path: "/api/v1/topics/{topic}/route", access: AccessReadOnly, tag: tagTopic,
+			summary: "查询 Topic 路由", response: admin.TopicRouteData{}, handle: g.getTopicRoute},
+		{method: "GET", path: "/api/v1/topics/{topic}/stats", access: AccessReadOnly, tag: tagTopic,
+			summary: "查询 Topic 各队列偏移", response: []QueueStats{}, handle: g.getTopicStats},
+		{method: "GET", path: "/api/v1/groups", access: AccessReadOnly, tag: tagGroup,
+			summary: "查询全部非系统消费组", response: []string{}, handle: g.listGroups},
+		{method: "GET", path: "/api/v1/groups/{group}/connections", access: AccessReadOnly, tag: tagGroup,
+			summary: "查询消费者连接", response: admin.ConsumerConnection{}, handle: g.getGroupConnections},
+		{method: "GET", path: "/api/v1/groups/{group}/lag", access: AccessReadOnly, tag: tagGroup,
+			summary: "查询消费组堆积", response: admin.GroupLag{}, handle: g.getGroupLag,
+			query: []param{
+				{name: "topic", typ: "string", desc: "只统计指定 Topic"},
+				{name: "clients", typ: "boolean", desc: "解析每个队列分配的客户端"},
+				{name: "accurate", typ: "boolean", desc: "以首条未消费消息的存储时间计算时间延迟"},
+			}},
+		{method: "GET", path: "/api/v1/lag", access: AccessReadOnly, tag: tagGroup,
+			summary: "查询全部消费组堆积", response: []admin.GroupLag{}, handle: g.listLag,
+			query: []param{{name: "topic", typ: "string", desc: "只统计指定 Topic"}}},
+		{method: "GET", path: "/api/v1/messages", access: AccessReadOnly, tag: tagMessage,
+			summary: "按 Key 查询消息", response: []admin.MessageExt{}, handle: g.queryMessages,
+			query: []param{
+				{name: "topic", typ: "string", desc: "Topic", required: true},
+				{name: "key", typ: "string", desc: "消息 Key", required: true},
+				{name: "begin", typ: "integer", desc: "起始时间（毫秒），默认 0"},
+				{name: "end", typ: "integer", desc: "结束时间（毫秒），默认当前时间"},
+				{name: "max", typ: "integer", desc: "最大返回条数，默认 32"},
+			}},
+		{method: "GET", path: "/api/v1/messages/{msgId}", access: AccessReadOnly, tag: tagMessage,
+			summary: "按消息 ID 查询消息", response: admin.MessageExt{}, handle: g.viewMessage,
+			query: []param{{name: "topic", typ: "string", desc: "Topic", required: true}}},
+
+		// 读写路由
+		{method: "POST", path: "/api/v1/topics", access: AccessReadWrite, tag: tagTopic,
+			summary: "在集群内创建或更新 Topic", request: TopicRequest{}, response: BrokersResult{}, handle: g.upsertTopic},
+		{method: "DELETE", path: "/api/v1/topics/{topic}", access: AccessReadWrite, tag: tagTopic,
+			summary: "从集群删除 Topic", response: map[string]string{}, handle: g.deleteTopic,
+			query: []param{{name: "cluster", typ: "string", desc: "集群名称", required: true}}},
+		{method: "POST", path: "/api/v1/groups", access: AccessReadWrite, tag: tagGroup,
+			summary: "在集群内创建或更新订阅组", request: GroupRequest{}, response: BrokersResult{}, handle: g.upsertGroup},
+		{method: "DELETE", path: "/api/v1/groups/{group}", access: AccessReadWrite, tag: tagGroup,
+			summary: "从集群删除订阅组", response: BrokersResult{}, handle: g.deleteGroup,
+			query: []param{
+				{name: "cluster", typ: "string", desc: "集群名称，为空时作用于全部集群"},
+				{name: "broker", typ: "string", desc: "Broker 地址"},
+			}},
+		{method: "POST", path: "/api/v1/groups/{group}/reset-offset", access: AccessReadWrite, tag: tagGroup,
+			summary: "按时间重置消费位点", request: ResetOffsetRequest{}, response: ResetOffsetResult{}, handle: g.resetOffset},
+		{method: "PUT", path: "/api/v1/brokers/{addr}/config", access: AccessReadWrite, tag: tagBroker,
+			summary: "更新 Broker 配置", request: map[string]string{}, response: map[string]string{}, handle: g.updateBrokerConfig},
+		{method: "POST", path: "/api/v1/messages", access: AccessReadWrite, tag: tagMessage,
+			summary: "发送消息", request: SendMessageRequest{}, response: admin.SendResult{}, handle: g.sendMessage},
+	}
+}
+
+// =============================================================================
+// 只读处理函数
+// =============================================================================
+
+func (g *Gateway) getClusters(r *http.Request) (any, error) {
+	return g.client.ExamineBrokerClusterInfo(r.Context())
+}
+
+func (g *Gateway) listBrokers(r *http.Request) (any, error) {
+	clusterInfo, err := g.client.ExamineBrokerClusterInfo(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := r.URL.Query().Get("cluster")
+	brokers := []BrokerInfo{}
+	for _, brokerData := range clusterInfo.BrokerAddrTable {
+		if cluster != "" && brokerData.Cluster != cluster {
+			continue
+		}
+		for id, addr := range brokerData.BrokerAddrs {
+			brokers = append(brokers, BrokerInfo{
+				Cluster:    brokerData.Cluster,
+				BrokerName: brokerData.BrokerName,
+				BrokerId:   id,
+				Addr:       addr,
+			})
+		}
+	}
+	sort.Slice(brokers, func(i, j int) bool {
+		a, b := brokers[i], brokers[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.BrokerName != b.BrokerName {
+			return a.BrokerName < b.BrokerName
+		}
+		return a.BrokerId < b.BrokerId
+	})
+	return brokers, nil
+}
+
+func (g *Gateway) getBrokerRuntime(r *http.Request) (any, error) {
+	stats, err := g.client.FetchBrokerRuntimeStats(r.Context(), r.PathValue("addr"))
+	if err != nil {
+		return nil, err
+	}
+	return stats.Table, nil
+}
+
+func (g *Gateway) getBrokerConfig(r *http.Request) (any, error) {
+	return g.client.GetBrokerConfig(r.Context(), r.PathValue("addr"))
+}
+
+func (g *Gateway) listTopics(r *http.Request) (any, error) {
+	var (
+		topics *admin.TopicList
+		err    error
+	)
+	if cluster := r.URL.Query().Get("cluster"); cluster != "" {
+		topics, err = g.client.FetchTopicsByCluster(r.Context(), cluster)
+	} else {
+		topics, err = g.client.FetchAllTopicList(r.Context())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := append([]string{}, topics.TopicList...)
+	sort.Strings(names)
+	return names, nil
+}
+
+func (g *Gateway) getTopicRoute(r *http.Request) (any, error) {
+	return g.client.ExamineTopicRouteInfo(r.Context(), r.PathValue("topic"))
+}
+
+func (g *Gateway) getTopicStats(r *http.Request) (any, error) {
+	stats, err := g.client.ExamineTopicStats(r.Context(), r.PathValue("topic"))
+	if err != nil {
+		return nil, err
+	}
+
+	queues := make([]QueueStats, 0, len(stats.OffsetTable))
+	for key, offset := range stats.OffsetTable {
+		mq, err := admin.ParseMessageQueueKey(key)
+		if err != nil {
+			continue
+		}
+		queues = append(queues, QueueStats{
+			MessageQueue:        mq,
+			MinOffset:           offset.MinOffset,
+			MaxOffset:           offset.MaxOffset,
+			LastUpdateTimestamp: offset.LastUpdateTimestamp,
+		})
+	}
+	sort.Slice(queues, func(i, j int) bool {
+		return queueLess(queues[i].MessageQueue, queues[j].MessageQueue)
+	})
+	return queues, nil
+}
+
+func (g *Gateway) listGroups(r *http.Request) (any, error) {
+	groups, err := g.client.ListConsumerGroups(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	if groups == nil {
+		groups = []string{}
+	}
+	return groups, nil
+}
+
+func (g *Gateway) getGroupConnections(r *http.Request) (any, error) {
+	return g.client.ExamineConsumerConnectionInfo(r.Context(), r.PathValue("group"))
+}
+
+func (g *Gateway) getGroupLag(r *http.Request) (any, error) {
+	q := r.URL.Query()
+	clients, err := boolQuery(q.Get("clients"), "clients")
+	if err != nil {
+		return nil, err
+	}
+	accurate, err := boolQuery(q.Get("accurate"), "accurate")
+	if err != nil {
+		return nil, err
+	}
+	return g.client.ExamineConsumerLag(r.Context(), r.PathValue("group"), admin.LagOptions{
+		Topic:           q.Get("topic"),
+		ResolveClients:  clients,
+		AccurateTimeLag: accurate,
+	})
+}
+
+func (g *Gateway) listLag(r *http.Request) (any, error) {
+	return g.client.ExamineConsumerLagList(r.Context(), nil, admin.LagOptions{Topic: r.URL.Query().Get("topic")})
+}
+
+func (g *Gateway) queryMessages(r *http.Request) (any, error) {
+	q := r.URL.Query()
+	topic, key := q.Get("topic"), q.Get("key")
+	if topic == "" || key == "" {
+		return nil, badRequest("topic 和 key 不能为空")
+	}
+	begin, err := intQuery(q.Get("begin"), "begin", 0)
+	if err != nil {
+		return nil, err
+	}
+	end, err := intQuery(q.Get("end"), "end", time.Now().UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	maxNum, err := intQuery(q.Get("max"), "max", 32)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := g.client.QueryMessage(r.Context(), topic, key, int(maxNum), begin, end)
+	if err != nil {
+		return nil, err
+	}
+	if msgs == nil {
+		msgs = []*admin.MessageExt{}
+	}
+	return msgs, nil
+}
+
+func (g *Gateway) viewMessage(r *http.Request) (any, error) {
+	topic := r.URL.Query().Get("topic")
+	if topic == "" {
+		return nil, badRequest("topic 不能为空")
+	}
+	return g.client.ViewMessage(r.Context(), topic, r.PathValue("msgId"))
+}
+
+// =============================================================================
+// 读写处理函数
+// =============================================================================
+
+func (g *Gateway) upsertTopic(r *http.Request) (any, error) {
+	var req TopicRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	if req.TopicName == "" {
+		return nil, badRequest("topicName 不能为空")
+	}
+	if req.ReadQueueNums <= 0 || req.WriteQueueNums <= 0 {
+		return nil, badRequest("readQueueNums 和 writeQueueNums 必须大于 0")
+	}
+	if req.Perm == 0 {
+		req.Perm = 6
+	}
+	if req.TopicFilterType == "" {
+		req.TopicFilterType = "SINGLE_TAG"
+	}
+
+	return g.onMasters(r.Context(), req.ClusterName, req.BrokerAddr, func(ctx context.Context, addr string) error {
+		return g.client.CreateTopic(ctx, addr, req.TopicConfig)
+	})
+}
+
+func (g *Gateway) deleteTopic(r *http.Request) (any, error) {
+	cluster := r.URL.Query().Get("cluster")
+	if cluster == "" {
+		return nil, badRequest("cluster 不能为空")
+	}
+	topic := r.PathValue("topic")
+	if err := g.client.DeleteTopic(r.Context(), topic, cluster); err != nil {
+		return nil, err
+	}
+	return map[string]string{"topic": topic, "cluster": cluster}, nil
+}
+
+func (g *Gateway) upsertGroup(r *http.Request) (any, error) {
+	req := GroupRequest{
+		SubscriptionGroupConfig: admin.SubscriptionGroupConfig{
+			ConsumeEnable:                  true,
+			ConsumeFromMinEnable:           true,
+			ConsumeBroadcastEnable:         true,
+			RetryQueueNums:                 1,
+			RetryMaxTimes:                  16,
+			WhichBrokerWhenConsumeSlowly:   1,
+			NotifyConsumerIdsChangedEnable: true,
+		},
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	if req.GroupName == "" {
+		return nil, badRequest("groupName 不能为空")
+	}
+
+	return g.onMasters(r.Context(), req.ClusterName, req.BrokerAddr, func(ctx context.Context, addr string) error {
+		return g.client.CreateSubscriptionGroup(ctx, addr, req.SubscriptionGroupConfig)
+	})
+}
+
+func (g *Gateway) deleteGroup(r *http.Request) (any, error) {
+	q := r.URL.Query()
+	group := r.PathValue("group")
+	return g.onMasters(r.Context(), q.Get("cluster"), q.Get("broker"), func(ctx context.Context, addr string) error {
+		return g.client.DeleteSubscriptionGroup(ctx, addr, group)
+	})
+}
+
+func (g *Gateway) resetOffset(r *http.Request) (any, error) {
+	var req ResetOffsetRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	if req.Topic == "" || req.Timestamp <= 0 {
+		return nil, badRequest("topic 和 timestamp 不能为空")
+	}
+
+	// 消费组不在线时改为直接改写已提交的位点
+	group := r.PathValue("group")
+	queues, err := g.client.ResetOffsetByTimestamp(r.Context(), req.Topic, group, req.Timestamp, req.Force)
+	if admin.IsResponseCode(err, remoting.ConsumerNotOnline) {
+		queues, err = g.client.ResetOffsetByTimestampOld(r.Context(), group, req.Topic, req.Timestamp, req.Force)
+	}
+	var brokerErrs admin.BrokerErrors
+	if err != nil && !errors.As(err, &brokerErrs) && queues == nil {
+		return nil, err
+	}
+	result := &ResetOffsetResult{Queues: queues}
+	for _, e := range brokerErrs {
+		result.Failed = append(result.Failed, BrokerResult{BrokerName: e.BrokerName, Addr: e.Addr, Error: e.Err.Error()})
+	}
+	if result.Queues == nil {
+		result.Queues = []admin.QueueOffsetResult{}
+	}
+	return result, nil
+}
+
+func (g *Gateway) updateBrokerConfig(r *http.Request) (any, error) {
+	var properties map[string]string
+	if err := decodeBody(r, &properties); err != nil {
+		return nil, err
+	}
+	if len(properties) == 0 {
+		return nil, badRequest("配置项不能为空")
+	}
+	if err := g.client.UpdateBrokerConfig(r.Context(), r.PathValue("addr"), properties); err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
+func (g *Gateway) sendMessage(r *http.Request) (any, error) {
+	var req SendMessageRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	if req.Topic == "" || req.Body == "" {
+		return nil, badRequest("topic 和 body 不能为空")
+	}
+	return g.client.SendMessage(r.Context(), req.Topic, []byte(req.Body), &admin.SendMessageOptions{
+		Tags:       req.Tags,
+		Keys:       req.Keys,
+		DelayLevel: req.DelayLevel,
+		Properties: req.Properties,
+	})
+}
+
+// =============================================================================
+// 辅助函数
+// =============================================================================
+
+// masters 解析操作目标：指定 Broker 地址、指定集群的全部 Master，或全部集群的 Master
+// 返回 key 为地址、value 为 Broker 名称
+func (g *Gateway) masters(ctx context.Context, clusterName, brokerAddr string) (map[string]string, error) {
+	if brokerAddr != "" {
+		return map[string]string{brokerAddr: brokerAddr}, nil
+	}
+
+	clusterInfo, err := g.client.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if clusterName != "" {
+		if _, ok := clusterInfo.ClusterAddrTable[clusterName]; !ok {
+			return nil, badRequest("集群 %s 不存在", clusterName)
+		}
+	}
+
+	result := make(map[string]string)
+	for cluster, brokerNames := range clusterInfo.ClusterAddrTable {
+		if clusterName != "" && cluster != clusterName {
+			continue
+		}
+		for _, brokerName := range brokerNames {
+			brokerData, ok := clusterInfo.BrokerAddrTable[brokerName]
+			if !ok {
+				continue
+			}
+			if addr, ok := brokerData.BrokerAddrs["0"]; ok {
+				result[addr] = brokerName
+			}
+		}
+	}
+	if len(result) == 0 {
+		return nil, admin.ErrBrokerNotFound
+	}
+	return result, nil
+}
+
+// onMasters 在目标 Master 上并行执行操作并汇总结果
+func (g *Gateway) onMasters(ctx context.Context, clusterName, brokerAddr string, fn func(ctx context.Context, addr string) error) (*BrokersResult, error) {
+	masters, err := g.masters(ctx, clusterName, brokerAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BrokersResult{Brokers: make([]BrokerResult, 0, len(masters))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for addr, brokerName := range masters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			br := BrokerResult{BrokerName: brokerName, Addr: addr}
+			if err := fn(ctx, addr); err != nil {
+				br.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Brokers = append(result.Brokers, br)
+			if br.Error == "" {
+				result.Succeeded++
+			} else {
+				result.Failed++
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(result.Brokers, func(i, j int) bool {
+		return result.Brokers[i].BrokerName < result.Brokers[j].BrokerName
+	})
+	return result, nil
+}
+
+// decodeBody 解析 JSON 请求体，拒绝未知字段
+func decodeBody(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var maxBytes *http.MaxBytesError
+		if errors.As(err, &maxBytes) {
+			return err
+		}
+		if errors.Is(err, io.EOF) {
+			return badRequest("请求体不能为空")
+		}
+		return badRequest("解析请求体失败: %v", err)
+	}
+	return nil
+}
+
+// intQuery 解析整数 query 参数，为空时返回默认值
+func intQuery(value, name string, def int64) (int64, error) {
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, badRequest("参数 %s 不是整数: %s", name, value)
+	}
+	return n, nil
+}
+
+// boolQuery 解析布尔 query 参数，为空时返回 false
+func boolQuery(value, name string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, badRequest("参数 %s 不是布尔值: %s", name, value)
+	}
+	return b, nil
+}
+
+// queueLess 按 Topic、Broker 名称、队列 ID 排序
+func queueLess(a, b admin.MessageQueue) bool {
+	if a.Topic != b.Topic {
+		return a.Topic < b.Topic
+	}
+	if a.BrokerName != b.BrokerName {
+		return a.BrokerName < b.BrokerName
+	}
+	return a.QueueId < b.QueueId
+}
@@ -0,0 +1,286 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+)
+
+// =============================================================================
+// 集群级订阅组管理
+// =============================================================================
+
+// BrokerGroupResult 单个 Broker 上的订阅组操作结果
+type BrokerGroupResult struct {
+	BrokerName string `json:"brokerName"`
+	Addr       string `json:"addr"`
+	Slave      bool   `json:"slave,omitempty"` // Slave 上的清理结果，失败不影响整体结果
+	Error      string `json:"error,omitempty"`
+}
+
+// CreateSubscriptionGroupInCluster 在集群的全部 Master 上创建或更新订阅组，clusterName 为空时作用于全部集群
+//
+// 各 Master 并行写入，写入后重新读取配置校验，与期望不一致的 Broker 记录为失败并返回 BrokerErrors。
+func (c *Client) CreateSubscriptionGroupInCluster(ctx context.Context, clusterName string, config SubscriptionGroupConfig) ([]BrokerGroupResult, error) {
+	if config.GroupName == "" {
+		return nil, fmt.Errorf("订阅组名称不能为空")
+	}
+	clusterInfo, err := c.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取集群信息失败: %w", err)
+	}
+	masters, err := planBrokers(clusterInfo, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	results := groupTargets(masters, nil)
+	c.onGroupBrokers(ctx, results, func(ctx context.Context, addr string) error {
+		if err := c.CreateSubscriptionGroup(ctx, addr, config); err != nil {
+			return err
+		}
+		groups, err := c.GetAllSubscriptionGroup(ctx, addr)
+		if err != nil {
+			return fmt.Errorf("校验失败: %w", err)
+		}
+		live, ok := groups[config.GroupName]
+		if !ok {
+			return fmt.Errorf("校验失败: 订阅组不存在")
+		}
+		if changes := groupChanges(&config, live); len(changes) > 0 {
+			return fmt.Errorf("校验失败: %s", formatFieldChanges(changes))
+		}
+		return nil
+	})
+	return results, groupResultErrors(results)
+}
+
+// DeleteSubscriptionGroupInCluster 在集群的全部 Broker 上删除订阅组，clusterName 为空时作用于全部集群
+//
+// 先在各 Master 上删除并校验订阅组已不存在，再清理 Slave 上的副本；Slave 清理失败只记录在结果中。
+// cleanOffset 为 true 时 Broker 同时删除该订阅组的消费进度。
+func (c *Client) DeleteSubscriptionGroupInCluster(ctx context.Context, clusterName, group string, cleanOffset bool) ([]BrokerGroupResult, error) {
+	clusterInfo, err := c.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取集群信息失败: %w", err)
+	}
+	masters, err := planBrokers(clusterInfo, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	results := groupTargets(masters, clusterInfo)
+	c.onGroupBrokers(ctx, results, func(ctx context.Context, addr string) error {
+		if err := c.deleteSubscriptionGroup(ctx, addr, group, cleanOffset); err != nil {
+			return err
+		}
+		groups, err := c.GetAllSubscriptionGroup(ctx, addr)
+		if err != nil {
+			return fmt.Errorf("校验失败: %w", err)
+		}
+		if _, ok := groups[group]; ok {
+			return fmt.Errorf("校验失败: 订阅组仍然存在")
+		}
+		return nil
+	})
+	return results, groupResultErrors(results)
+}
+
+// deleteSubscriptionGroup 在单个 Broker 上删除订阅组，cleanOffset 为 true 时同时删除消费进度
+func (c *Client) deleteSubscriptionGroup(ctx context.Context, addr, group string, cleanOffset bool) error {
+	extFields := map[string]string{
+		"groupName":   group,
+		"cleanOffset": fmt.Sprintf("%t", cleanOffset),
+	}
+	cmd := remoting.NewRequest(remoting.DeleteSubscriptionGroup, extFields)
+
+	resp, err := c.invokeBroker(ctx, addr, cmd)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != remoting.Success {
+		return NewAdminError(resp.Code, resp.Remark)
+	}
+
+	return nil
+}
+
+// groupTargets 返回各 Master 的操作目标，clusterInfo 不为空时同时包含各 Master 的 Slave，按 Broker 名称排序
+func groupTargets(masters map[string]string, clusterInfo *ClusterInfo) []BrokerGroupResult {
+	var targets []BrokerGroupResult
+	for _, brokerName := range sortedKeys(masters) {
+		targets = append(targets, BrokerGroupResult{BrokerName: brokerName, Addr: masters[brokerName]})
+		if clusterInfo == nil {
+			continue
+		}
+		addrs := clusterInfo.BrokerAddrTable[brokerName].BrokerAddrs
+		for _, id := range sortedKeys(addrs) {
+			if id != "0" {
+				targets = append(targets, BrokerGroupResult{BrokerName: brokerName, Addr: addrs[id], Slave: true})
+			}
+		}
+	}
+	return targets
+}
+
+// onGroupBrokers 在各目标 Broker 上并行执行 fn，失败原因写入对应结果。
+// Slave 在全部 Master 执行完后执行，Master 失败时跳过其 Slave，避免主从不一致
+func (c *Client) onGroupBrokers(ctx context.Context, targets []BrokerGroupResult, fn func(ctx context.Context, addr string) error) {
+	var wg sync.WaitGroup
+	for _, slave := range []bool{false, true} {
+		for i := range targets {
+			t := &targets[i]
+			if t.Slave != slave {
+				continue
+			}
+			if slave && !masterSucceeded(targets, t.BrokerName) {
+				t.Error = "Master 执行失败，跳过"
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := fn(ctx, t.Addr); err != nil {
+					t.Error = err.Error()
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+// masterSucceeded 返回 Broker 的 Master 是否执行成功
+func masterSucceeded(targets []BrokerGroupResult, brokerName string) bool {
+	for _, t := range targets {
+		if !t.Slave && t.BrokerName == brokerName {
+			return t.Error == ""
+		}
+	}
+	return false
+}
+
+// groupResultErrors 汇总 Master 上的失败
+func groupResultErrors(results []BrokerGroupResult) error {
+	var brokerErrs BrokerErrors
+	for _, r := range results {
+		if r.Error != "" && !r.Slave {
+			brokerErrs = append(brokerErrs, &BrokerError{BrokerName: r.BrokerName, Addr: r.Addr, Err: fmt.Errorf("%s", r.Error)})
+		}
+	}
+	if len(brokerErrs) > 0 {
+		return brokerErrs
+	}
+	return nil
+}
+
+// formatFieldChanges 格式化字段变更，如 "retryMaxTimes: 16 -> 3"
+func formatFieldChanges(changes []FieldChange) string {
+	parts := make([]string, len(changes))
+	for i, ch := range changes {
+		parts[i] = fmt.Sprintf("%s: %s -> %s", ch.Field, ch.Old, ch.New)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// =============================================================================
+// 订阅组一致性检查
+// =============================================================================
+
+// GroupBrokerState 订阅组在单个 Master 上的配置
+type GroupBrokerState struct {
+	BrokerName string                   `json:"brokerName"`
+	Addr       string                   `json:"addr"`
+	Config     *SubscriptionGroupConfig `json:"config,omitempty"`  // 为空表示该 Broker 上没有此订阅组
+	Diff       []FieldChange            `json:"diff,omitempty"`    // 与多数 Broker 不同的字段，Old 为多数配置
+	Error      string                   `json:"error,omitempty"`   // 读取失败的原因
+	Missing    bool                     `json:"missing,omitempty"` // 该 Broker 上没有此订阅组
+}
+
+// GroupDescription 订阅组在集群中的配置汇总
+type GroupDescription struct {
+	Group   string                   `json:"group"`
+	Config  *SubscriptionGroupConfig `json:"config"` // 多数 Broker 上的配置
+	Brokers []GroupBrokerState       `json:"brokers"`
+}
+
+// Consistent 返回订阅组是否在全部 Broker 上存在且配置一致
+func (d *GroupDescription) Consistent() bool {
+	for _, b := range d.Brokers {
+		if b.Missing || b.Error != "" || len(b.Diff) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// DescribeSubscriptionGroup 汇总订阅组在集群全部 Master 上的配置，clusterName 为空时检查全部集群
+//
+// 以出现次数最多的配置为准，标记缺少该订阅组或配置不同的 Broker。
+// 订阅组在所有 Broker 上都不存在时返回 ErrConsumerGroupNotFound。
+func (c *Client) DescribeSubscriptionGroup(ctx context.Context, clusterName, group string) (*GroupDescription, error) {
+	clusterInfo, err := c.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取集群信息失败: %w", err)
+	}
+	masters, err := planBrokers(clusterInfo, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := groupTargets(masters, nil)
+	desc := &GroupDescription{Group: group, Brokers: make([]GroupBrokerState, len(targets))}
+	for i, t := range targets {
+		desc.Brokers[i] = GroupBrokerState{BrokerName: t.BrokerName, Addr: t.Addr}
+	}
+	c.onGroupBrokers(ctx, targets, func(ctx context.Context, addr string) error {
+		groups, err := c.GetAllSubscriptionGroup(ctx, addr)
+		if err != nil {
+			return err
+		}
+		for i := range desc.Brokers {
+			if b := &desc.Brokers[i]; b.Addr == addr {
+				b.Config, b.Missing = groups[group], groups[group] == nil
+			}
+		}
+		return nil
+	})
+	for i, t := range targets {
+		desc.Brokers[i].Error = t.Error
+	}
+
+	// 出现次数最多的配置作为基准，次数相同时取 Broker 名称靠前的
+	counts := make(map[string]int)
+	best := 0
+	for _, b := range desc.Brokers {
+		if b.Config == nil {
+			continue
+		}
+		key, err := json.Marshal(b.Config)
+		if err != nil {
+			return desc, fmt.Errorf("序列化订阅组配置失败: %w", err)
+		}
+		counts[string(key)]++
+		if n := counts[string(key)]; n > best {
+			best, desc.Config = n, b.Config
+		}
+	}
+	if desc.Config == nil {
+		for _, b := range desc.Brokers {
+			if b.Error != "" {
+				return desc, fmt.Errorf("读取 %s 订阅组失败: %s", b.BrokerName, b.Error)
+			}
+		}
+		return desc, fmt.Errorf("%w: %s", ErrConsumerGroupNotFound, group)
+	}
+	for i := range desc.Brokers {
+		if b := &desc.Brokers[i]; b.Config != nil {
+			b.Diff = groupChanges(b.Config, desc.Config)
+		}
+	}
+	return desc, nil
+}
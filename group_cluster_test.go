@@ -0,0 +1,133 @@
+package admin
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// 集群级订阅组管理单元测试
+// =============================================================================
+
+// groupBroker 模拟保存订阅组配置的 Broker
+type groupBroker struct {
+	*remotingtest.Server
+	mu     sync.Mutex
+	groups map[string]*SubscriptionGroupConfig
+}
+
+// newGroupBroker 启动模拟 Broker，rewrite 不为空时在保存前修改配置，模拟 Broker 未按请求生效
+func newGroupBroker(t *testing.T, rewrite func(*SubscriptionGroupConfig)) *groupBroker {
+	t.Helper()
+	b := &groupBroker{Server: remotingtest.NewServer(), groups: make(map[string]*SubscriptionGroupConfig)}
+	t.Cleanup(b.Close)
+
+	b.Handle(remoting.UpdateAndCreateSubscriptionGroup, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		f := req.ExtFields
+		config := &SubscriptionGroupConfig{
+			GroupName:                      f["groupName"],
+			ConsumeEnable:                  f["consumeEnable"] == "true",
+			ConsumeFromMinEnable:           f["consumeFromMinEnable"] == "true",
+			ConsumeBroadcastEnable:         f["consumeBroadcastEnable"] == "true",
+			RetryQueueNums:                 atoi(f["retryQueueNums"]),
+			RetryMaxTimes:                  atoi(f["retryMaxTimes"]),
+			BrokerId:                       int64(atoi(f["brokerId"])),
+			WhichBrokerWhenConsumeSlowly:   int64(atoi(f["whichBrokerWhenConsumeSlowly"])),
+			NotifyConsumerIdsChangedEnable: f["notifyConsumerIdsChangedEnable"] == "true",
+		}
+		if rewrite != nil {
+			rewrite(config)
+		}
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.groups[config.GroupName] = config
+		return remotingtest.Success(nil)
+	})
+	b.Handle(remoting.DeleteSubscriptionGroup, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.groups, req.ExtFields["groupName"])
+		return remotingtest.Success(nil)
+	})
+	b.Handle(remoting.GetAllSubscriptionGroup, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return remotingtest.JSON(map[string]any{"subscriptionGroupTable": b.groups})
+	})
+	return b
+}
+
+// newGroupCluster 启动三个 Master 的集群，broker-a 带一个 Slave，NameServer 与 broker-a 共用
+func newGroupCluster(t *testing.T) (a, slave, b, c *groupBroker) {
+	t.Helper()
+	a, slave, c = newGroupBroker(t, nil), newGroupBroker(t, nil), newGroupBroker(t, nil)
+	b = newGroupBroker(t, func(config *SubscriptionGroupConfig) { config.RetryMaxTimes = min(config.RetryMaxTimes, 3) })
+	a.Handle(remoting.GetBrokerClusterInfo, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{
+			"brokerAddrTable": map[string]any{
+				"broker-a": map[string]any{"cluster": "DefaultCluster", "brokerName": "broker-a", "brokerAddrs": map[string]string{"0": a.Addr, "1": slave.Addr}},
+				"broker-b": map[string]any{"cluster": "DefaultCluster", "brokerName": "broker-b", "brokerAddrs": map[string]string{"0": b.Addr}},
+				"broker-c": map[string]any{"cluster": "DefaultCluster", "brokerName": "broker-c", "brokerAddrs": map[string]string{"0": c.Addr}},
+			},
+			"clusterAddrTable": map[string]any{"DefaultCluster": []string{"broker-a", "broker-b", "broker-c"}},
+		})
+	})
+	return a, slave, b, c
+}
+
+// TestSubscriptionGroupInCluster 测试集群级创建、校验、查看与删除订阅组
+func TestSubscriptionGroupInCluster(t *testing.T) {
+	a, slave, _, c := newGroupCluster(t)
+	client := newFakeClient(t, a.Server)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	config := SubscriptionGroupConfig{GroupName: "GroupA", ConsumeEnable: true, RetryQueueNums: 1, RetryMaxTimes: 16}
+	results, err := client.CreateSubscriptionGroupInCluster(ctx, "DefaultCluster", config)
+	var brokerErrs BrokerErrors
+	if !errors.As(err, &brokerErrs) || len(brokerErrs) != 1 || brokerErrs[0].BrokerName != "broker-b" {
+		t.Fatalf("broker-b 应校验失败, got %v", err)
+	}
+	if len(results) != 3 || results[0].Error != "" || results[1].Error != "校验失败: retryMaxTimes: 3 -> 16" {
+		t.Errorf("创建结果错误: %+v", results)
+	}
+
+	// broker-c 上删除后查看：broker-b 配置不同，broker-c 缺失
+	c.mu.Lock()
+	delete(c.groups, "GroupA")
+	c.mu.Unlock()
+	desc, err := client.DescribeSubscriptionGroup(ctx, "", "GroupA")
+	if err != nil {
+		t.Fatalf("查看订阅组失败: %v", err)
+	}
+	if desc.Consistent() || desc.Config.RetryMaxTimes != 16 {
+		t.Errorf("应以 broker-a 的配置为准且不一致: %+v", desc)
+	}
+	if got := desc.Brokers[1]; len(got.Diff) != 1 || got.Diff[0].Field != "retryMaxTimes" || got.Diff[0].New != "3" {
+		t.Errorf("broker-b 应标记 retryMaxTimes 不同: %+v", got)
+	}
+	if !desc.Brokers[2].Missing {
+		t.Errorf("broker-c 应标记缺失: %+v", desc.Brokers[2])
+	}
+
+	slave.mu.Lock()
+	slave.groups["GroupA"] = &config
+	slave.mu.Unlock()
+	results, err = client.DeleteSubscriptionGroupInCluster(ctx, "DefaultCluster", "GroupA", true)
+	if err != nil {
+		t.Fatalf("删除订阅组失败: %v", err)
+	}
+	if len(results) != 4 || !results[1].Slave || results[1].Addr != slave.Addr || results[1].Error != "" {
+		t.Errorf("应同时清理 Slave: %+v", results)
+	}
+	if reqs := slave.Requests(remoting.DeleteSubscriptionGroup); len(reqs) != 1 || reqs[0].ExtFields["cleanOffset"] != "true" {
+		t.Errorf("删除请求应携带 cleanOffset: %+v", reqs)
+	}
+	if _, err := client.DescribeSubscriptionGroup(ctx, "", "GroupA"); !errors.Is(err, ErrConsumerGroupNotFound) {
+		t.Errorf("删除后应返回 ErrConsumerGroupNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+)
+
+// =============================================================================
+// 订阅组暂停与恢复
+// =============================================================================
+
+// PauseGroupOptions 暂停/恢复订阅组的选项
+type PauseGroupOptions struct {
+	// Topics 同时禁止/允许读取的 Topic，需要 Broker 支持按 Topic 禁止读取（RocketMQ 5.x）
+	Topics []string
+}
+
+// PauseGroup 在集群全部 Master 上暂停订阅组消费，clusterName 为空时作用于全部集群
+//
+// 只将 consumeEnable 修改为 false，订阅组的其他配置保持不变；Broker 随后拒绝该订阅组的拉取请求，
+// 消费者进程无需停止。opts.Topics 不为空时同时禁止订阅组读取这些 Topic。
+// 各 Broker 的结果中 Changes 为空表示已处于暂停状态。
+func (c *Client) PauseGroup(ctx context.Context, clusterName, group string, opts PauseGroupOptions) ([]BrokerPatchResult, error) {
+	return c.setGroupPaused(ctx, clusterName, group, true, opts)
+}
+
+// ResumeGroup 在集群全部 Master 上恢复订阅组消费，clusterName 为空时作用于全部集群
+func (c *Client) ResumeGroup(ctx context.Context, clusterName, group string, opts PauseGroupOptions) ([]BrokerPatchResult, error) {
+	return c.setGroupPaused(ctx, clusterName, group, false, opts)
+}
+
+// setGroupPaused 修改各 Master 上订阅组的 consumeEnable 及 Topic 读取权限
+func (c *Client) setGroupPaused(ctx context.Context, clusterName, group string, paused bool, opts PauseGroupOptions) ([]BrokerPatchResult, error) {
+	readable := !paused
+	return c.patchInCluster(ctx, clusterName, func(ctx context.Context, addr string) ([]FieldChange, error) {
+		changes, err := c.PatchSubscriptionGroupConfig(ctx, addr, group, SubscriptionGroupConfigPatch{ConsumeEnable: Ptr(readable)})
+		if err != nil {
+			return nil, err
+		}
+		for _, topic := range opts.Topics {
+			old, err := c.groupTopicReadable(ctx, addr, group, topic, nil)
+			if err != nil {
+				return changes, err
+			}
+			if old == readable {
+				continue
+			}
+			current, err := c.groupTopicReadable(ctx, addr, group, topic, &readable)
+			if err != nil {
+				return changes, err
+			}
+			if current != readable {
+				return changes, fmt.Errorf("校验失败: Topic %s 读取权限未生效", topic)
+			}
+			changes = appendChange(changes, "readable["+topic+"]", old, current)
+		}
+		return changes, nil
+	})
+}
+
+// groupTopicReadable 查询或修改订阅组对 Topic 的读取权限，readable 为空时只查询
+func (c *Client) groupTopicReadable(ctx context.Context, addr, group, topic string, readable *bool) (bool, error) {
+	extFields := map[string]string{
+		"group": group,
+		"topic": topic,
+	}
+	if readable != nil {
+		extFields["readable"] = strconv.FormatBool(*readable)
+	}
+	cmd := remoting.NewRequest(remoting.UpdateAndGetGroupForbidden, extFields)
+
+	resp, err := c.invokeBroker(ctx, addr, cmd)
+	if err != nil {
+		return false, err
+	}
+
+	if resp.Code != remoting.Success {
+		return false, fmt.Errorf("Topic %s 读取权限: %w", topic, NewAdminError(resp.Code, resp.Remark))
+	}
+
+	var forbidden struct {
+		Readable bool `json:"readable"`
+	}
+	if err := json.Unmarshal(resp.Body, &forbidden); err != nil {
+		return false, fmt.Errorf("解析 Topic %s 读取权限失败: %w", topic, err)
+	}
+	return forbidden.Readable, nil
+}
+
+// =============================================================================
+// 暂停状态
+// =============================================================================
+
+// GroupPauseState 订阅组在单个 Master 上的暂停状态
+type GroupPauseState struct {
+	BrokerName      string   `json:"brokerName"`
+	Addr            string   `json:"addr"`
+	Paused          bool     `json:"paused"`                    // consumeEnable 为 false
+	ForbiddenTopics []string `json:"forbiddenTopics,omitempty"` // 禁止读取的 Topic
+	Error           string   `json:"error,omitempty"`
+}
+
+// GetGroupPauseState 查询订阅组在集群全部 Master 上的暂停状态，clusterName 为空时查询全部集群
+//
+// topics 为需要检查读取权限的 Topic；Broker 不支持按 Topic 禁止读取时忽略。
+// 读取失败或订阅组不存在的 Broker 汇总为 BrokerErrors。
+func (c *Client) GetGroupPauseState(ctx context.Context, clusterName, group string, topics []string) ([]GroupPauseState, error) {
+	clusterInfo, err := c.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取集群信息失败: %w", err)
+	}
+	masters, err := planBrokers(clusterInfo, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := groupTargets(masters, nil)
+	states := make([]GroupPauseState, len(targets))
+	for i, t := range targets {
+		states[i] = GroupPauseState{BrokerName: t.BrokerName, Addr: t.Addr}
+	}
+	c.onGroupBrokers(ctx, targets, func(ctx context.Context, addr string) error {
+		groups, err := c.GetAllSubscriptionGroup(ctx, addr)
+		if err != nil {
+			return err
+		}
+		config, ok := groups[group]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrConsumerGroupNotFound, group)
+		}
+
+		var forbidden []string
+		for _, topic := range topics {
+			readable, err := c.groupTopicReadable(ctx, addr, group, topic, nil)
+			if IsResponseCode(err, remoting.RequestCodeNotSupported) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if !readable {
+				forbidden = append(forbidden, topic)
+			}
+		}
+		for i := range states {
+			if s := &states[i]; s.Addr == addr {
+				s.Paused, s.ForbiddenTopics = !config.ConsumeEnable, forbidden
+			}
+		}
+		return nil
+	})
+	for i, t := range targets {
+		states[i].Error = t.Error
+	}
+	return states, groupResultErrors(targets)
+}
+
+// ClientPullState 消费者客户端的拉取状态
+type ClientPullState struct {
+	ClientId     string `json:"clientId"`
+	LastPullTime int64  `json:"lastPullTime"` // 各队列最后一次拉取时间的最大值，毫秒
+	Stopped      bool   `json:"stopped"`      // since 之后没有拉取
+	Error        string `json:"error,omitempty"`
+}
+
+// ConfirmGroupPullStopped 通过消费者运行时信息确认订阅组的在线客户端在 since 之后已停止拉取
+//
+// 通常在 PauseGroup 之后等待一个拉取周期再调用。订阅组没有在线客户端时返回空结果；
+// 任一客户端仍在拉取或无法获取运行时信息时返回错误。
+func (c *Client) ConfirmGroupPullStopped(ctx context.Context, group string, since time.Time) ([]ClientPullState, error) {
+	conn, err := c.ExamineConsumerConnectionInfo(ctx, group)
+	if errors.Is(err, ErrConsumerGroupNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询消费组 %s 连接失败: %w", group, err)
+	}
+
+	var (
+		states  []ClientPullState
+		pulling int
+	)
+	for _, client := range conn.ConnectionSet {
+		state := ClientPullState{ClientId: client.ClientId}
+		info, err := c.GetConsumerRunningInfo(ctx, group, client.ClientId, false)
+		if err != nil {
+			state.Error = err.Error()
+			pulling++
+			states = append(states, state)
+			continue
+		}
+		for _, pq := range info.MqTable {
+			state.LastPullTime = max(state.LastPullTime, pq.LastPullTime)
+		}
+		state.Stopped = state.LastPullTime < since.UnixMilli()
+		if !state.Stopped {
+			pulling++
+		}
+		states = append(states, state)
+	}
+	if pulling > 0 {
+		return states, fmt.Errorf("消费组 %s 仍有 %d/%d 个客户端在拉取或状态未知", group, pulling, len(states))
+	}
+	return states, nil
+}
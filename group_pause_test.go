@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// 订阅组暂停与恢复单元测试
+// =============================================================================
+
+// handleGroupForbidden 为模拟 Broker 添加按 Topic 禁止读取的支持
+func handleGroupForbidden(b *groupBroker) {
+	var (
+		mu        sync.Mutex
+		forbidden = make(map[string]bool)
+	)
+	b.Handle(remoting.UpdateAndGetGroupForbidden, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		mu.Lock()
+		defer mu.Unlock()
+		key := req.ExtFields["group"] + "@" + req.ExtFields["topic"]
+		if readable, ok := req.ExtFields["readable"]; ok {
+			forbidden[key] = readable != "true"
+		}
+		return remotingtest.JSON(map[string]any{"group": req.ExtFields["group"], "topic": req.ExtFields["topic"], "readable": !forbidden[key]})
+	})
+}
+
+// TestPauseGroup 测试暂停、查询与恢复订阅组
+func TestPauseGroup(t *testing.T) {
+	a, _, b, c := newGroupCluster(t)
+	handleGroupForbidden(a)
+	handleGroupForbidden(b)
+	for _, broker := range []*groupBroker{a, b, c} {
+		broker.groups["GroupA"] = &SubscriptionGroupConfig{GroupName: "GroupA", ConsumeEnable: true, RetryQueueNums: 1, RetryMaxTimes: 3}
+	}
+	client := newFakeClient(t, a.Server)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	// broker-c 不支持按 Topic 禁止读取，但 consumeEnable 仍被修改
+	results, err := client.PauseGroup(ctx, "DefaultCluster", "GroupA", PauseGroupOptions{Topics: []string{"TopicA"}})
+	var brokerErrs BrokerErrors
+	if !errors.As(err, &brokerErrs) || len(brokerErrs) != 1 || brokerErrs[0].BrokerName != "broker-c" {
+		t.Fatalf("broker-c 应失败, got %v", err)
+	}
+	if !IsResponseCode(err, remoting.RequestCodeNotSupported) {
+		t.Errorf("应保留 Broker 返回的响应码: %v", err)
+	}
+	if got := results[0].Changes; len(got) != 2 || got[0].Field != "consumeEnable" || got[1].Field != "readable[TopicA]" {
+		t.Errorf("broker-a 应修改 consumeEnable 与 TopicA 读取权限: %+v", got)
+	}
+
+	states, err := client.GetGroupPauseState(ctx, "", "GroupA", []string{"TopicA"})
+	if err != nil {
+		t.Fatalf("查询暂停状态失败: %v", err)
+	}
+	for _, s := range states {
+		if !s.Paused || (s.BrokerName == "broker-c") != (len(s.ForbiddenTopics) == 0) {
+			t.Errorf("暂停状态错误: %+v", s)
+		}
+	}
+
+	if _, err := client.ResumeGroup(ctx, "DefaultCluster", "GroupA", PauseGroupOptions{}); err != nil {
+		t.Fatalf("恢复消费失败: %v", err)
+	}
+	c.mu.Lock()
+	if got := c.groups["GroupA"]; !got.ConsumeEnable || got.RetryQueueNums != 1 || got.RetryMaxTimes != 3 {
+		t.Errorf("恢复后应只修改 consumeEnable: %+v", got)
+	}
+	c.mu.Unlock()
+
+	a.mu.Lock()
+	delete(a.groups, "GroupA")
+	a.mu.Unlock()
+	states, err = client.GetGroupPauseState(ctx, "", "GroupA", nil)
+	if !errors.As(err, &brokerErrs) || len(brokerErrs) != 1 || brokerErrs[0].BrokerName != "broker-a" || states[1].Paused {
+		t.Errorf("broker-a 缺少订阅组应失败: %v %+v", err, states)
+	}
+}
+
+// TestConfirmGroupPullStopped 测试根据客户端运行时信息确认已停止拉取
+func TestConfirmGroupPullStopped(t *testing.T) {
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+	handleClusterInfo(srv, "broker-a")
+	var lastPull atomic.Int64
+	srv.Handle(remoting.GetConsumerConnectionList, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"connectionSet": []map[string]any{{"clientId": "10.0.0.3@1"}}})
+	})
+	srv.Handle(remoting.GetConsumerRunningInfo, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success(fmt.Appendf(nil, `{"mqTable":{`+
+			`{"brokerName":"broker-a","queueId":0,"topic":"TopicA"}:{"lastPullTime":%d},`+
+			`{"brokerName":"broker-a","queueId":1,"topic":"TopicA"}:{"lastPullTime":1}}}`, lastPull.Load()))
+	})
+	client := newFakeClient(t, srv)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	since := time.Now()
+	lastPull.Store(since.Add(-time.Second).UnixMilli())
+	states, err := client.ConfirmGroupPullStopped(ctx, "GroupA", since)
+	if err != nil || len(states) != 1 || !states[0].Stopped || states[0].LastPullTime != lastPull.Load() {
+		t.Fatalf("客户端应已停止拉取: %v %+v", err, states)
+	}
+
+	lastPull.Store(since.Add(time.Second).UnixMilli())
+	if states, err := client.ConfirmGroupPullStopped(ctx, "GroupA", since); err == nil || states[0].Stopped {
+		t.Errorf("客户端仍在拉取时应返回错误: %+v", states)
+	}
+}
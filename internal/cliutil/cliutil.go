@@ -0,0 +1,26 @@
+// Package cliutil 提供命令行工具共用的参数处理函数
+package cliutil
+
+import (
+	"os"
+	"strings"
+)
+
+// EnvOr 读取环境变量，不存在时返回默认值
+func EnvOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// SplitList 按分隔符拆分并去除空项
+func SplitList(s, sep string) []string {
+	var result []string
+	for _, item := range strings.Split(s, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,28 @@
+package cliutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestEnvOr 测试读取环境变量与默认值
+func TestEnvOr(t *testing.T) {
+	t.Setenv("CLIUTIL_TEST_SET", "value")
+	t.Setenv("CLIUTIL_TEST_EMPTY", "")
+	if got := EnvOr("CLIUTIL_TEST_SET", "def"); got != "value" {
+		t.Errorf("应返回环境变量, got %s", got)
+	}
+	if got := EnvOr("CLIUTIL_TEST_EMPTY", "def"); got != "def" {
+		t.Errorf("环境变量为空时应返回默认值, got %s", got)
+	}
+}
+
+// TestSplitList 测试拆分并去除空项
+func TestSplitList(t *testing.T) {
+	if got := SplitList(" a; b ;;c ", ";"); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("拆分结果错误: %q", got)
+	}
+	if got := SplitList(" , ", ","); got != nil {
+		t.Errorf("全部为空项时应返回 nil, got %q", got)
+	}
+}
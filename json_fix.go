@@ -1,6 +1,8 @@
 package admin
 
 import (
+	"bytes"
+	"encoding/json"
 	"regexp"
 )
 
@@ -11,6 +13,8 @@ import (
 // RocketMQ 返回的响应中可能包含非标准 JSON：
 // 1. 数字 key 没有引号: {"brokerAddrs":{0:"192.168.1.1:10911"}}
 // 2. 字符串属性名没有引号: {topic:xxx,brokerName:xxx,queueId:0}
+// 3. 对象作为 key（fastjson 序列化 Map<MessageQueue, ...>）:
+//    {"offsetTable":{{"brokerName":"a","queueId":0,"topic":"t"}:{...}}}
 // 需要转换为标准 JSON 格式
 
 // 匹配非标准 JSON 数字 key 的正则表达式
@@ -30,5 +34,100 @@ func fixJSONBody(body []byte) []byte {
 	// 2. 替换字符串 key：{topic: -> {"topic": 或 ,brokerName: -> ,"brokerName":
 	result = unquotedStrKeyRegex.ReplaceAll(result, []byte(`$1"$2":`))
 
+	// 3. 对象 key 转换为字符串 key：{{"topic":"t"}:1} -> {"{\"topic\":\"t\"}":1}
+	result = stringifyObjectKeys(result)
+
 	return result
 }
+
+// jsonFrame 记录 JSON 扫描时所处的容器
+type jsonFrame struct {
+	isObject  bool // 是否为对象（否则为数组）
+	expectKey bool // 对象中下一个值是否为 key
+}
+
+// stringifyObjectKeys 将对象类型的 key 序列化为 JSON 字符串
+func stringifyObjectKeys(body []byte) []byte {
+	if !bytes.Contains(body, []byte("{{")) && !bytes.Contains(body, []byte(",{")) {
+		return body
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(body))
+
+	var stack []jsonFrame
+	for i := 0; i < len(body); i++ {
+		ch := body[i]
+		inKey := len(stack) > 0 && stack[len(stack)-1].isObject && stack[len(stack)-1].expectKey
+
+		switch ch {
+		case '"':
+			end := skipJSONString(body, i)
+			out.Write(body[i:end])
+			i = end - 1
+			continue
+		case '{':
+			if inKey {
+				end := matchJSONBrace(body, i)
+				if end < 0 {
+					return body
+				}
+				key, _ := json.Marshal(string(body[i : end+1]))
+				out.Write(key)
+				stack[len(stack)-1].expectKey = false
+				i = end
+				continue
+			}
+			stack = append(stack, jsonFrame{isObject: true, expectKey: true})
+		case '[':
+			stack = append(stack, jsonFrame{})
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ':':
+			if len(stack) > 0 && stack[len(stack)-1].isObject {
+				stack[len(stack)-1].expectKey = false
+			}
+		case ',':
+			if len(stack) > 0 && stack[len(stack)-1].isObject {
+				stack[len(stack)-1].expectKey = true
+			}
+		}
+		out.WriteByte(ch)
+	}
+
+	return out.Bytes()
+}
+
+// skipJSONString 返回从 start 处开始的字符串字面量之后的位置
+func skipJSONString(body []byte, start int) int {
+	for i := start + 1; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(body)
+}
+
+// matchJSONBrace 返回与 start 处 '{' 匹配的 '}' 位置，未找到返回 -1
+func matchJSONBrace(body []byte, start int) int {
+	depth := 0
+	for i := start; i < len(body); i++ {
+		switch body[i] {
+		case '"':
+			i = skipJSONString(body, i) - 1
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// =============================================================================
+// JSON 响应预处理单元测试
+// =============================================================================
+
+// TestFixJSONBody_ObjectKeys 测试对象 key 的转换
+func TestFixJSONBody_ObjectKeys(t *testing.T) {
+	body := []byte(`{"offsetTable":{{"brokerName":"broker-a","queueId":0,"topic":"TopicTest"}:{"brokerOffset":10,"consumerOffset":8},{"brokerName":"broker-a","queueId":1,"topic":"TopicTest"}:{"brokerOffset":5,"consumerOffset":5}},"consumeTps":1.5}`)
+
+	var stats ConsumeStats
+	if err := json.Unmarshal(fixJSONBody(body), &stats); err != nil {
+		t.Fatalf("解析消费统计失败: %v", err)
+	}
+	if len(stats.OffsetTable) != 2 {
+		t.Fatalf("OffsetTable 大小应为 2, got %d", len(stats.OffsetTable))
+	}
+
+	for key, wrapper := range stats.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil {
+			t.Fatalf("解析队列 key 失败: %v", err)
+		}
+		if mq.Topic != "TopicTest" || mq.BrokerName != "broker-a" {
+			t.Errorf("队列解析错误: %+v", mq)
+		}
+		if mq.QueueId == 0 && wrapper.BrokerOffset != 10 {
+			t.Errorf("队列 0 的 BrokerOffset 应为 10, got %d", wrapper.BrokerOffset)
+		}
+	}
+}
+
+// TestParseMessageQueueKey 测试解析各种格式的队列 key
+func TestParseMessageQueueKey(t *testing.T) {
+	want := MessageQueue{Topic: "TopicTest", BrokerName: "broker-a", QueueId: 3}
+	keys := []string{
+		`{"brokerName":"broker-a","queueId":3,"topic":"TopicTest"}`,
+		`{topic:TopicTest,brokerName:broker-a,queueId:3}`,
+		`TopicTest@broker-a@3`,
+		`MessageQueue [topic=TopicTest, brokerName=broker-a, queueId=3]`,
+	}
+
+	for _, key := range keys {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil {
+			t.Errorf("解析 %s 失败: %v", key, err)
+			continue
+		}
+		if mq != want {
+			t.Errorf("解析 %s 结果不匹配: %+v", key, mq)
+		}
+	}
+
+	if _, err := ParseMessageQueueKey("invalid"); err == nil {
+		t.Error("非法 key 应返回错误")
+	}
+}
@@ -105,6 +105,14 @@ func (c *Client) GetKVListByNamespace(ctx context.Context, namespace string) (ma
 		return nil, NewAdminError(resp.Code, resp.Remark)
 	}
 
+	// NameServer 返回 KVTable 结构 {"table":{...}}，兼容直接返回 map 的实现
+	var wrapper struct {
+		Table map[string]string `json:"table"`
+	}
+	if err := json.Unmarshal(resp.Body, &wrapper); err == nil && wrapper.Table != nil {
+		return wrapper.Table, nil
+	}
+
 	result := make(map[string]string)
 	if err := json.Unmarshal(resp.Body, &result); err != nil {
 		return nil, fmt.Errorf("解析 KV 列表失败: %w", err)
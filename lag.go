@@ -0,0 +1,280 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// 消费堆积
+// =============================================================================
+
+// LagSortBy 堆积排序方式
+type LagSortBy string
+
+// 堆积排序方式定义
+const (
+	LagSortByMessage LagSortBy = "lag"     // 按消息堆积量降序（默认）
+	LagSortByTime    LagSortBy = "timeLag" // 按时间延迟降序
+)
+
+// LagOptions 堆积查询选项
+type LagOptions struct {
+	Topic           string    // 只统计指定 Topic，为空时统计全部
+	ResolveClients  bool      // 通过消费者运行时信息解析每个队列的分配客户端
+	AccurateTimeLag bool      // 拉取首条未消费消息，以其存储时间计算时间延迟
+	SortBy          LagSortBy // 排序方式
+}
+
+// QueueLag 单个队列的堆积
+type QueueLag struct {
+	MessageQueue   MessageQueue `json:"messageQueue"`   // 队列
+	BrokerOffset   int64        `json:"brokerOffset"`   // Broker 最大偏移
+	ConsumerOffset int64        `json:"consumerOffset"` // 消费偏移
+	Lag            int64        `json:"lag"`            // 消息堆积量
+	LastTimestamp  int64        `json:"lastTimestamp"`  // 最后消费消息的存储时间（毫秒）
+	TimeLag        int64        `json:"timeLag"`        // 时间延迟（毫秒）
+	ClientId       string       `json:"clientId"`       // 分配的客户端
+}
+
+// TopicLag 单个 Topic 的堆积汇总
+type TopicLag struct {
+	Topic      string     `json:"topic"`      // Topic
+	Lag        int64      `json:"lag"`        // 消息堆积总量
+	MaxTimeLag int64      `json:"maxTimeLag"` // 最大时间延迟（毫秒）
+	Queues     []QueueLag `json:"queues"`     // 各队列堆积
+}
+
+// GroupLag 消费组的堆积汇总
+type GroupLag struct {
+	ConsumerGroup string     `json:"consumerGroup"` // 消费者组
+	Lag           int64      `json:"lag"`           // 消息堆积总量
+	MaxTimeLag    int64      `json:"maxTimeLag"`    // 最大时间延迟（毫秒）
+	ConsumeTps    float64    `json:"consumeTps"`    // 消费 TPS
+	OnlineClients int        `json:"onlineClients"` // 在线客户端数
+	Topics        []TopicLag `json:"topics"`        // 各 Topic 堆积
+	CollectTime   int64      `json:"collectTime"`   // 统计时间（毫秒）
+}
+
+// ExamineConsumerLag 查询消费组在各队列上的消息堆积和时间延迟
+// 结果中的 Topic 和队列均按堆积程度降序排列
+func (c *Client) ExamineConsumerLag(ctx context.Context, consumerGroup string, opts LagOptions) (*GroupLag, error) {
+	stats, err := c.ExamineConsumeStats(ctx, consumerGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	groupLag := &GroupLag{
+		ConsumerGroup: consumerGroup,
+		ConsumeTps:    stats.ConsumeTps,
+		CollectTime:   now,
+	}
+
+	var clients map[MessageQueue]string
+	connInfo, err := c.ExamineConsumerConnectionInfo(ctx, consumerGroup)
+	if err == nil {
+		groupLag.OnlineClients = len(connInfo.ConnectionSet)
+		if opts.ResolveClients {
+			clients = c.queueAllocation(ctx, consumerGroup, connInfo)
+		}
+	} else if !errors.Is(err, ErrConsumerGroupNotFound) {
+		return nil, err
+	}
+
+	var masters map[string]string
+	topics := make(map[string]*TopicLag)
+	for key, offset := range stats.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil {
+			continue
+		}
+		if opts.Topic != "" && mq.Topic != opts.Topic {
+			continue
+		}
+
+		queueLag := QueueLag{
+			MessageQueue:   mq,
+			BrokerOffset:   offset.BrokerOffset,
+			ConsumerOffset: offset.ConsumerOffset,
+			Lag:            max(offset.BrokerOffset-offset.ConsumerOffset, 0),
+			LastTimestamp:  offset.LastTimestamp,
+			ClientId:       clients[mq],
+		}
+
+		if queueLag.Lag > 0 {
+			if opts.AccurateTimeLag {
+				if masters == nil {
+					masters, err = c.brokerMasterAddrs(ctx)
+					if err != nil {
+						return nil, err
+					}
+				}
+				if storeTime, err := c.firstUnconsumedStoreTime(ctx, masters[mq.BrokerName], mq, offset.ConsumerOffset); err == nil {
+					queueLag.TimeLag = max(now-storeTime, 0)
+				}
+			}
+			if queueLag.TimeLag == 0 && offset.LastTimestamp > 0 {
+				queueLag.TimeLag = max(now-offset.LastTimestamp, 0)
+			}
+		}
+
+		topicLag, ok := topics[mq.Topic]
+		if !ok {
+			topicLag = &TopicLag{Topic: mq.Topic}
+			topics[mq.Topic] = topicLag
+		}
+		topicLag.Queues = append(topicLag.Queues, queueLag)
+		topicLag.Lag += queueLag.Lag
+		topicLag.MaxTimeLag = max(topicLag.MaxTimeLag, queueLag.TimeLag)
+	}
+
+	for _, topicLag := range topics {
+		sortQueueLags(topicLag.Queues, opts.SortBy)
+		groupLag.Topics = append(groupLag.Topics, *topicLag)
+		groupLag.Lag += topicLag.Lag
+		groupLag.MaxTimeLag = max(groupLag.MaxTimeLag, topicLag.MaxTimeLag)
+	}
+	sort.Slice(groupLag.Topics, func(i, j int) bool {
+		a, b := groupLag.Topics[i], groupLag.Topics[j]
+		return lagLess(b.Lag, b.MaxTimeLag, a.Lag, a.MaxTimeLag, opts.SortBy, a.Topic < b.Topic)
+	})
+
+	return groupLag, nil
+}
+
+// ExamineConsumerLagList 查询多个消费组的堆积，groups 为空时查询集群中全部非系统消费组
+// 单个消费组查询失败时跳过，结果按堆积程度降序排列
+func (c *Client) ExamineConsumerLagList(ctx context.Context, groups []string, opts LagOptions) ([]*GroupLag, error) {
+	if len(groups) == 0 {
+		var err error
+		groups, err = c.ListConsumerGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]*GroupLag, 0, len(groups))
+	for _, group := range groups {
+		groupLag, err := c.ExamineConsumerLag(ctx, group, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		result = append(result, groupLag)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		return lagLess(b.Lag, b.MaxTimeLag, a.Lag, a.MaxTimeLag, opts.SortBy, a.ConsumerGroup < b.ConsumerGroup)
+	})
+
+	return result, nil
+}
+
+// ListConsumerGroups 列出集群中全部非系统消费组（汇总所有 Master 上的订阅组配置）
+func (c *Client) ListConsumerGroups(ctx context.Context) ([]string, error) {
+	masters, err := c.brokerMasterAddrs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var lastErr error
+	for brokerName, addr := range masters {
+		groups, err := c.GetUserSubscriptionGroup(ctx, addr)
+		if err != nil {
+			lastErr = fmt.Errorf("获取 %s 订阅组失败: %w", brokerName, err)
+			continue
+		}
+		for group := range groups {
+			seen[group] = true
+		}
+	}
+	if len(seen) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	result := make([]string, 0, len(seen))
+	for group := range seen {
+		result = append(result, group)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// brokerMasterAddrs 返回集群中各 Broker 的 Master 地址，key 为 BrokerName
+func (c *Client) brokerMasterAddrs(ctx context.Context) (map[string]string, error) {
+	clusterInfo, err := c.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	masters := make(map[string]string, len(clusterInfo.BrokerAddrTable))
+	for brokerName, brokerData := range clusterInfo.BrokerAddrTable {
+		if addr := brokerData.BrokerAddrs["0"]; addr != "" {
+			masters[brokerName] = addr
+		}
+	}
+	return masters, nil
+}
+
+// queueAllocation 通过各客户端的运行时信息获取队列分配，获取失败的客户端被忽略
+func (c *Client) queueAllocation(ctx context.Context, consumerGroup string, connInfo *ConsumerConnection) map[MessageQueue]string {
+	result := make(map[MessageQueue]string)
+	for _, conn := range connInfo.ConnectionSet {
+		runningInfo, err := c.GetConsumerRunningInfo(ctx, consumerGroup, conn.ClientId, false)
+		if err != nil {
+			continue
+		}
+		for key := range runningInfo.MqTable {
+			if mq, err := ParseMessageQueueKey(key); err == nil {
+				result[mq] = conn.ClientId
+			}
+		}
+	}
+	return result
+}
+
+// firstUnconsumedStoreTime 拉取消费偏移处的消息，返回其存储时间
+func (c *Client) firstUnconsumedStoreTime(ctx context.Context, brokerAddr string, mq MessageQueue, offset int64) (int64, error) {
+	if brokerAddr == "" {
+		return 0, fmt.Errorf("%w: %s", ErrBrokerNotFound, mq.BrokerName)
+	}
+	pullResult, err := c.PullMessage(ctx, brokerAddr, mq, "", offset, 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(pullResult.Messages) == 0 {
+		return 0, ErrMessageNotFound
+	}
+	return pullResult.Messages[0].StoreTimestamp, nil
+}
+
+// sortQueueLags 队列按堆积程度降序排列
+func sortQueueLags(queues []QueueLag, sortBy LagSortBy) {
+	sort.Slice(queues, func(i, j int) bool {
+		a, b := queues[i], queues[j]
+		return lagLess(b.Lag, b.TimeLag, a.Lag, a.TimeLag, sortBy, queueLess(a.MessageQueue, b.MessageQueue))
+	})
+}
+
+// lagLess 比较两组堆积指标，主次指标都相同时使用 tie
+func lagLess(lagA, timeLagA, lagB, timeLagB int64, sortBy LagSortBy, tie bool) bool {
+	primaryA, primaryB, secondaryA, secondaryB := lagA, lagB, timeLagA, timeLagB
+	if sortBy == LagSortByTime {
+		primaryA, primaryB, secondaryA, secondaryB = timeLagA, timeLagB, lagA, lagB
+	}
+	if primaryA != primaryB {
+		return primaryA < primaryB
+	}
+	if secondaryA != secondaryB {
+		return secondaryA < secondaryB
+	}
+	return tie
+}
@@ -0,0 +1,443 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// 消费堆积监控
+// =============================================================================
+
+// LagAlertType 堆积告警类型
+type LagAlertType string
+
+// 堆积告警类型定义
+const (
+	LagAlertTypeLag              LagAlertType = "LAG"                // 消息堆积超过阈值
+	LagAlertTypeTimeLag          LagAlertType = "TIME_LAG"           // 时间延迟超过阈值
+	LagAlertTypeLagGrowing       LagAlertType = "LAG_GROWING"        // 堆积持续增长
+	LagAlertTypeNoOnlineConsumer LagAlertType = "NO_ONLINE_CONSUMER" // 没有在线消费者
+)
+
+// LagAlertStatus 告警状态
+type LagAlertStatus string
+
+// 告警状态定义
+const (
+	LagAlertStatusFiring   LagAlertStatus = "FIRING"   // 告警中
+	LagAlertStatusResolved LagAlertStatus = "RESOLVED" // 已恢复
+)
+
+// LagRule 堆积告警规则，字段为零值时对应规则不生效
+type LagRule struct {
+	MaxLag              int64         // 消息堆积阈值
+	MaxTimeLag          time.Duration // 时间延迟阈值
+	GrowingIntervals    int           // 堆积连续增长的轮数阈值
+	AlertOnNoConsumer   bool          // 没有在线消费者时告警
+	MinLagForNoConsumer int64         // 没有在线消费者且堆积不低于该值时才告警
+}
+
+// LagAlert 堆积告警
+type LagAlert struct {
+	ConsumerGroup string         `json:"consumerGroup"` // 消费者组
+	Type          LagAlertType   `json:"type"`          // 告警类型
+	Status        LagAlertStatus `json:"status"`        // 告警状态
+	Message       string         `json:"message"`       // 告警描述
+	Lag           *GroupLag      `json:"lag"`           // 触发时的堆积数据
+	FiredAt       time.Time      `json:"firedAt"`       // 首次触发时间
+	ResolvedAt    time.Time      `json:"resolvedAt"`    // 恢复时间
+}
+
+// LagAlertHandler 告警回调，在监控协程中同步调用
+type LagAlertHandler func(alert LagAlert)
+
+// LagMonitorOptions 堆积监控配置
+type LagMonitorOptions struct {
+	// Groups 监控的消费组，为空时每轮监控集群中全部非系统消费组
+	Groups []string
+
+	// Interval 轮询间隔
+	Interval time.Duration
+
+	// Rule 默认告警规则
+	Rule LagRule
+
+	// GroupRules 按消费组覆盖的告警规则
+	GroupRules map[string]LagRule
+
+	// LagOptions 查询堆积的选项
+	LagOptions LagOptions
+
+	// Handlers 告警回调
+	Handlers []LagAlertHandler
+
+	// RepeatInterval 告警持续期间重复通知的间隔，0 表示只通知一次
+	RepeatInterval time.Duration
+
+	// ErrorHandler 查询失败回调
+	ErrorHandler func(group string, err error)
+}
+
+// LagMonitorOption 堆积监控配置函数
+type LagMonitorOption func(*LagMonitorOptions)
+
+// WithMonitorGroups 设置监控的消费组
+func WithMonitorGroups(groups ...string) LagMonitorOption {
+	return func(o *LagMonitorOptions) {
+		o.Groups = groups
+	}
+}
+
+// WithMonitorInterval 设置轮询间隔
+func WithMonitorInterval(interval time.Duration) LagMonitorOption {
+	return func(o *LagMonitorOptions) {
+		o.Interval = interval
+	}
+}
+
+// WithLagRule 设置默认告警规则
+func WithLagRule(rule LagRule) LagMonitorOption {
+	return func(o *LagMonitorOptions) {
+		o.Rule = rule
+	}
+}
+
+// WithGroupLagRule 为指定消费组设置告警规则
+func WithGroupLagRule(group string, rule LagRule) LagMonitorOption {
+	return func(o *LagMonitorOptions) {
+		if o.GroupRules == nil {
+			o.GroupRules = make(map[string]LagRule)
+		}
+		o.GroupRules[group] = rule
+	}
+}
+
+// WithMonitorLagOptions 设置查询堆积的选项
+func WithMonitorLagOptions(opts LagOptions) LagMonitorOption {
+	return func(o *LagMonitorOptions) {
+		o.LagOptions = opts
+	}
+}
+
+// WithAlertHandler 添加告警回调
+func WithAlertHandler(handler LagAlertHandler) LagMonitorOption {
+	return func(o *LagMonitorOptions) {
+		o.Handlers = append(o.Handlers, handler)
+	}
+}
+
+// WithAlertRepeatInterval 设置告警重复通知间隔
+func WithAlertRepeatInterval(interval time.Duration) LagMonitorOption {
+	return func(o *LagMonitorOptions) {
+		o.RepeatInterval = interval
+	}
+}
+
+// WithMonitorErrorHandler 设置查询失败回调
+func WithMonitorErrorHandler(handler func(group string, err error)) LagMonitorOption {
+	return func(o *LagMonitorOptions) {
+		o.ErrorHandler = handler
+	}
+}
+
+// LagGroupState 消费组监控状态
+type LagGroupState struct {
+	ConsumerGroup string         `json:"consumerGroup"` // 消费者组
+	LastLag       *GroupLag      `json:"lastLag"`       // 最近一次堆积数据
+	GrowingCount  int            `json:"growingCount"`  // 堆积连续增长轮数
+	Firing        []LagAlertType `json:"firing"`        // 告警中的类型
+	LastCheck     time.Time      `json:"lastCheck"`     // 最近检查时间
+	LastError     string         `json:"lastError"`     // 最近一次查询错误
+}
+
+// groupMonitorState 消费组内部监控状态
+type groupMonitorState struct {
+	lastLag      *GroupLag
+	growingCount int
+	firing       map[LagAlertType]*LagAlert
+	notifiedAt   map[LagAlertType]time.Time
+	lastCheck    time.Time
+	lastError    string
+}
+
+// LagMonitor 消费堆积监控器
+type LagMonitor struct {
+	client *Client
+	opts   *LagMonitorOptions
+
+	// fetch 查询单个消费组堆积，测试时可替换
+	fetch func(ctx context.Context, group string) (*GroupLag, error)
+
+	mu      sync.Mutex
+	states  map[string]*groupMonitorState
+	cancel  context.CancelFunc
+	done    chan struct{}
+	running bool
+}
+
+// NewLagMonitor 创建消费堆积监控器
+func NewLagMonitor(client *Client, opts ...LagMonitorOption) (*LagMonitor, error) {
+	options := &LagMonitorOptions{
+		Interval: time.Minute,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if client == nil {
+		return nil, errors.New("Client 不能为空")
+	}
+	if options.Interval <= 0 {
+		return nil, errors.New("轮询间隔必须大于 0")
+	}
+
+	m := &LagMonitor{
+		client: client,
+		opts:   options,
+		states: make(map[string]*groupMonitorState),
+	}
+	m.fetch = func(ctx context.Context, group string) (*GroupLag, error) {
+		return m.client.ExamineConsumerLag(ctx, group, m.opts.LagOptions)
+	}
+	return m, nil
+}
+
+// Start 启动后台轮询，立即执行一轮检查
+func (m *LagMonitor) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.running {
+		return ErrAlreadyStarted
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+	m.done = make(chan struct{})
+	m.running = true
+
+	go m.run(ctx, m.done)
+	return nil
+}
+
+// Stop 停止轮询并等待当前一轮检查结束
+func (m *LagMonitor) Stop() {
+	m.mu.Lock()
+	if !m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.running = false
+	cancel, done := m.cancel, m.done
+	m.mu.Unlock()
+
+	cancel()
+	<-done
+}
+
+// run 轮询主循环
+func (m *LagMonitor) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	// 父 ctx 取消时循环自行退出，需要清除运行状态以便再次 Start
+	defer func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.done == done {
+			m.running = false
+			m.cancel()
+		}
+	}()
+
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		_ = m.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check 执行一轮检查，单个消费组查询失败不会中断其他消费组
+func (m *LagMonitor) Check(ctx context.Context) error {
+	groups := m.opts.Groups
+	if len(groups) == 0 {
+		var err error
+		groups, err = m.client.ListConsumerGroups(ctx)
+		if err != nil {
+			m.reportError("", err)
+			return err
+		}
+		m.pruneStates(groups)
+	}
+
+	for _, group := range groups {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		groupLag, err := m.fetch(ctx, group)
+		now := time.Now()
+		if err != nil {
+			m.mu.Lock()
+			state := m.state(group)
+			state.lastCheck = now
+			state.lastError = err.Error()
+			m.mu.Unlock()
+			m.reportError(group, err)
+			continue
+		}
+
+		for _, alert := range m.evaluate(group, groupLag, now) {
+			for _, handler := range m.opts.Handlers {
+				handler(alert)
+			}
+		}
+	}
+
+	return nil
+}
+
+// States 返回各消费组的监控状态，按消费组名称排序
+func (m *LagMonitor) States() []LagGroupState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]LagGroupState, 0, len(m.states))
+	for group, state := range m.states {
+		snapshot := LagGroupState{
+			ConsumerGroup: group,
+			LastLag:       state.lastLag,
+			GrowingCount:  state.growingCount,
+			LastCheck:     state.lastCheck,
+			LastError:     state.lastError,
+		}
+		for alertType := range state.firing {
+			snapshot.Firing = append(snapshot.Firing, alertType)
+		}
+		sort.Slice(snapshot.Firing, func(i, j int) bool { return snapshot.Firing[i] < snapshot.Firing[j] })
+		result = append(result, snapshot)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].ConsumerGroup < result[j].ConsumerGroup })
+	return result
+}
+
+// evaluate 根据最新堆积数据更新消费组状态，返回需要通知的告警
+func (m *LagMonitor) evaluate(group string, groupLag *GroupLag, now time.Time) []LagAlert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := m.state(group)
+	if state.lastLag != nil && groupLag.Lag > state.lastLag.Lag {
+		state.growingCount++
+	} else {
+		state.growingCount = 0
+	}
+	state.lastLag = groupLag
+	state.lastCheck = now
+	state.lastError = ""
+
+	rule := m.opts.Rule
+	if groupRule, ok := m.opts.GroupRules[group]; ok {
+		rule = groupRule
+	}
+
+	violations := make(map[LagAlertType]string)
+	if rule.MaxLag > 0 && groupLag.Lag > rule.MaxLag {
+		violations[LagAlertTypeLag] = fmt.Sprintf("消费组 %s 堆积 %d 条，超过阈值 %d", group, groupLag.Lag, rule.MaxLag)
+	}
+	if rule.MaxTimeLag > 0 && time.Duration(groupLag.MaxTimeLag)*time.Millisecond > rule.MaxTimeLag {
+		violations[LagAlertTypeTimeLag] = fmt.Sprintf("消费组 %s 延迟 %s，超过阈值 %s",
+			group, time.Duration(groupLag.MaxTimeLag)*time.Millisecond, rule.MaxTimeLag)
+	}
+	if rule.GrowingIntervals > 0 && state.growingCount >= rule.GrowingIntervals {
+		violations[LagAlertTypeLagGrowing] = fmt.Sprintf("消费组 %s 堆积连续 %d 轮增长，当前 %d 条", group, state.growingCount, groupLag.Lag)
+	}
+	if rule.AlertOnNoConsumer && groupLag.OnlineClients == 0 && groupLag.Lag >= rule.MinLagForNoConsumer {
+		violations[LagAlertTypeNoOnlineConsumer] = fmt.Sprintf("消费组 %s 没有在线消费者，堆积 %d 条", group, groupLag.Lag)
+	}
+
+	var alerts []LagAlert
+	for alertType, message := range violations {
+		alert, firing := state.firing[alertType]
+		if !firing {
+			alert = &LagAlert{
+				ConsumerGroup: group,
+				Type:          alertType,
+				Status:        LagAlertStatusFiring,
+				FiredAt:       now,
+			}
+			state.firing[alertType] = alert
+		}
+		alert.Message = message
+		alert.Lag = groupLag
+
+		lastNotified, notified := state.notifiedAt[alertType]
+		if !notified || (m.opts.RepeatInterval > 0 && now.Sub(lastNotified) >= m.opts.RepeatInterval) {
+			state.notifiedAt[alertType] = now
+			alerts = append(alerts, *alert)
+		}
+	}
+
+	for alertType, alert := range state.firing {
+		if _, ok := violations[alertType]; ok {
+			continue
+		}
+		resolved := *alert
+		resolved.Status = LagAlertStatusResolved
+		resolved.ResolvedAt = now
+		resolved.Lag = groupLag
+		resolved.Message = fmt.Sprintf("消费组 %s 的 %s 告警已恢复", group, alertType)
+		alerts = append(alerts, resolved)
+		delete(state.firing, alertType)
+		delete(state.notifiedAt, alertType)
+	}
+
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Type < alerts[j].Type })
+	return alerts
+}
+
+// state 返回消费组状态，不存在时创建（调用方需持有锁）
+func (m *LagMonitor) state(group string) *groupMonitorState {
+	state, ok := m.states[group]
+	if !ok {
+		state = &groupMonitorState{
+			firing:     make(map[LagAlertType]*LagAlert),
+			notifiedAt: make(map[LagAlertType]time.Time),
+		}
+		m.states[group] = state
+	}
+	return state
+}
+
+// pruneStates 清理已不存在的消费组状态
+func (m *LagMonitor) pruneStates(groups []string) {
+	current := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		current[group] = true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for group := range m.states {
+		if !current[group] {
+			delete(m.states, group)
+		}
+	}
+}
+
+// reportError 调用查询失败回调
+func (m *LagMonitor) reportError(group string, err error) {
+	if m.opts.ErrorHandler != nil {
+		m.opts.ErrorHandler(group, err)
+	}
+}
@@ -0,0 +1,222 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// 消费堆积监控单元测试
+// =============================================================================
+
+// newTestLagMonitor 创建使用固定堆积序列的监控器
+func newTestLagMonitor(t *testing.T, lags map[string][]*GroupLag, opts ...LagMonitorOption) (*LagMonitor, *[]LagAlert) {
+	t.Helper()
+
+	var alerts []LagAlert
+	opts = append(opts, WithAlertHandler(func(alert LagAlert) {
+		alerts = append(alerts, alert)
+	}))
+	monitor, err := NewLagMonitor(&Client{}, opts...)
+	if err != nil {
+		t.Fatalf("创建监控器失败: %v", err)
+	}
+
+	rounds := make(map[string]int)
+	monitor.fetch = func(ctx context.Context, group string) (*GroupLag, error) {
+		seq := lags[group]
+		i := rounds[group]
+		rounds[group]++
+		if i >= len(seq) || seq[i] == nil {
+			return nil, errors.New("查询失败")
+		}
+		return seq[i], nil
+	}
+	return monitor, &alerts
+}
+
+// TestLagMonitor_ThresholdAndRecovery 测试阈值告警去重与恢复通知
+func TestLagMonitor_ThresholdAndRecovery(t *testing.T) {
+	lags := map[string][]*GroupLag{
+		"CG": {
+			{ConsumerGroup: "CG", Lag: 200, OnlineClients: 1},
+			{ConsumerGroup: "CG", Lag: 300, OnlineClients: 1},
+			{ConsumerGroup: "CG", Lag: 10, OnlineClients: 1},
+		},
+	}
+	monitor, alerts := newTestLagMonitor(t, lags,
+		WithMonitorGroups("CG"),
+		WithLagRule(LagRule{MaxLag: 100}),
+	)
+
+	ctx := t.Context()
+	for i := 0; i < 3; i++ {
+		if err := monitor.Check(ctx); err != nil {
+			t.Fatalf("检查失败: %v", err)
+		}
+	}
+
+	if len(*alerts) != 2 {
+		t.Fatalf("应产生 1 次告警和 1 次恢复, got %+v", *alerts)
+	}
+	if (*alerts)[0].Type != LagAlertTypeLag || (*alerts)[0].Status != LagAlertStatusFiring {
+		t.Errorf("第一条应为堆积告警: %+v", (*alerts)[0])
+	}
+	if (*alerts)[1].Status != LagAlertStatusResolved || (*alerts)[1].FiredAt != (*alerts)[0].FiredAt {
+		t.Errorf("第二条应为恢复通知: %+v", (*alerts)[1])
+	}
+
+	states := monitor.States()
+	if len(states) != 1 || len(states[0].Firing) != 0 || states[0].LastLag.Lag != 10 {
+		t.Errorf("监控状态不匹配: %+v", states)
+	}
+}
+
+// TestLagMonitor_GrowingAndNoConsumer 测试持续增长和无在线消费者告警
+func TestLagMonitor_GrowingAndNoConsumer(t *testing.T) {
+	lags := map[string][]*GroupLag{
+		"CG": {
+			{ConsumerGroup: "CG", Lag: 1, OnlineClients: 1},
+			{ConsumerGroup: "CG", Lag: 2, OnlineClients: 1},
+			{ConsumerGroup: "CG", Lag: 3, OnlineClients: 1},
+		},
+		"OFFLINE": {
+			{ConsumerGroup: "OFFLINE", Lag: 5},
+			nil,
+			{ConsumerGroup: "OFFLINE", Lag: 5},
+		},
+	}
+	var errGroups []string
+	monitor, alerts := newTestLagMonitor(t, lags,
+		WithMonitorGroups("CG", "OFFLINE"),
+		WithLagRule(LagRule{AlertOnNoConsumer: true}),
+		WithGroupLagRule("CG", LagRule{GrowingIntervals: 2}),
+		WithMonitorErrorHandler(func(group string, err error) {
+			errGroups = append(errGroups, group)
+		}),
+	)
+
+	ctx := t.Context()
+	for i := 0; i < 3; i++ {
+		_ = monitor.Check(ctx)
+	}
+
+	var growing, offline int
+	for _, alert := range *alerts {
+		switch alert.Type {
+		case LagAlertTypeLagGrowing:
+			growing++
+		case LagAlertTypeNoOnlineConsumer:
+			offline++
+		}
+	}
+	if growing != 1 {
+		t.Errorf("堆积增长告警应为 1 次, got %d", growing)
+	}
+	// 查询失败不改变告警状态，不会重复通知
+	if offline != 1 {
+		t.Errorf("无在线消费者告警应为 1 次, got %d", offline)
+	}
+	if len(errGroups) != 1 || errGroups[0] != "OFFLINE" {
+		t.Errorf("查询失败回调不匹配: %v", errGroups)
+	}
+}
+
+// TestLagMonitor_Repeat 测试告警重复通知
+func TestLagMonitor_Repeat(t *testing.T) {
+	monitor, _ := newTestLagMonitor(t, nil, WithAlertRepeatInterval(time.Minute), WithLagRule(LagRule{MaxLag: 1}))
+
+	now := time.Now()
+	lag := &GroupLag{ConsumerGroup: "CG", Lag: 10, OnlineClients: 1}
+	if alerts := monitor.evaluate("CG", lag, now); len(alerts) != 1 {
+		t.Fatalf("首次应通知, got %d", len(alerts))
+	}
+	if alerts := monitor.evaluate("CG", lag, now.Add(30*time.Second)); len(alerts) != 0 {
+		t.Errorf("重复间隔内不应通知, got %d", len(alerts))
+	}
+	if alerts := monitor.evaluate("CG", lag, now.Add(time.Minute)); len(alerts) != 1 {
+		t.Errorf("超过重复间隔应再次通知, got %d", len(alerts))
+	}
+}
+
+// TestLagMonitor_StartStop 测试启动后立即检查并可停止
+func TestLagMonitor_StartStop(t *testing.T) {
+	lags := map[string][]*GroupLag{"CG": {{ConsumerGroup: "CG", Lag: 10}}}
+	monitor, _ := newTestLagMonitor(t, lags, WithMonitorGroups("CG"), WithMonitorInterval(time.Hour))
+
+	if err := monitor.Start(t.Context()); err != nil {
+		t.Fatalf("启动监控失败: %v", err)
+	}
+	if err := monitor.Start(t.Context()); !errors.Is(err, ErrAlreadyStarted) {
+		t.Errorf("重复启动应返回 ErrAlreadyStarted, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(monitor.States()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	monitor.Stop()
+	monitor.Stop()
+
+	if states := monitor.States(); len(states) != 1 || states[0].LastLag == nil {
+		t.Errorf("启动后应立即执行一轮检查: %+v", states)
+	}
+
+	// 父 ctx 取消后循环退出，可以再次启动
+	ctx, cancel := context.WithCancel(t.Context())
+	if err := monitor.Start(ctx); err != nil {
+		t.Fatalf("停止后应可再次启动: %v", err)
+	}
+	cancel()
+	deadline = time.Now().Add(time.Second)
+	err := monitor.Start(t.Context())
+	for errors.Is(err, ErrAlreadyStarted) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		err = monitor.Start(t.Context())
+	}
+	if err != nil {
+		t.Errorf("父 ctx 取消后应可再次启动, got %v", err)
+	}
+	monitor.Stop()
+}
+
+// TestNewLagMonitor_Invalid 测试非法配置
+func TestNewLagMonitor_Invalid(t *testing.T) {
+	if _, err := NewLagMonitor(nil); err == nil {
+		t.Error("Client 为空时应返回错误")
+	}
+	if _, err := NewLagMonitor(&Client{}, WithMonitorInterval(0)); err == nil {
+		t.Error("轮询间隔为 0 时应返回错误")
+	}
+}
+
+// =============================================================================
+// 消费堆积监控集成测试
+// =============================================================================
+
+// TestIntegration_LagMonitor 测试对真实集群执行一轮检查
+func TestIntegration_LagMonitor(t *testing.T) {
+	skipIfNoRocketMQ(t)
+	client := getTestClient(t)
+	defer client.Close()
+
+	monitor, err := NewLagMonitor(client,
+		WithLagRule(LagRule{MaxLag: 1}),
+		WithAlertHandler(func(alert LagAlert) {
+			t.Logf("[%s] %s", alert.Status, alert.Message)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("创建监控器失败: %v", err)
+	}
+
+	ctx, cancel := testContext()
+	defer cancel()
+
+	if err := monitor.Check(ctx); err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	t.Logf("监控消费组数量: %d", len(monitor.States()))
+}
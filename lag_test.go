@@ -0,0 +1,94 @@
+package admin
+
+import (
+	"fmt"
+	"testing"
+)
+
+// =============================================================================
+// 消费堆积单元测试
+// =============================================================================
+
+// TestSortQueueLags 测试队列按堆积程度排序
+func TestSortQueueLags(t *testing.T) {
+	queues := []QueueLag{
+		{MessageQueue: MessageQueue{BrokerName: "broker-a", QueueId: 0}, Lag: 10, TimeLag: 5000},
+		{MessageQueue: MessageQueue{BrokerName: "broker-a", QueueId: 1}, Lag: 50, TimeLag: 1000},
+		{MessageQueue: MessageQueue{BrokerName: "broker-b", QueueId: 0}, Lag: 10, TimeLag: 5000},
+		{MessageQueue: MessageQueue{BrokerName: "broker-a", QueueId: 2}, Lag: 10, TimeLag: 9000},
+	}
+
+	sortQueueLags(queues, LagSortByMessage)
+	want := []string{"broker-a/1", "broker-a/2", "broker-a/0", "broker-b/0"}
+	for i, q := range queues {
+		if got := fmt.Sprintf("%s/%d", q.MessageQueue.BrokerName, q.MessageQueue.QueueId); got != want[i] {
+			t.Errorf("按消息堆积排序第 %d 位应为 %s, got %s", i, want[i], got)
+		}
+	}
+
+	sortQueueLags(queues, LagSortByTime)
+	want = []string{"broker-a/2", "broker-a/0", "broker-b/0", "broker-a/1"}
+	for i, q := range queues {
+		if got := fmt.Sprintf("%s/%d", q.MessageQueue.BrokerName, q.MessageQueue.QueueId); got != want[i] {
+			t.Errorf("按时间延迟排序第 %d 位应为 %s, got %s", i, want[i], got)
+		}
+	}
+}
+
+// =============================================================================
+// 消费堆积集成测试
+// =============================================================================
+
+// TestIntegration_ExamineConsumerLag 测试查询消费组堆积
+func TestIntegration_ExamineConsumerLag(t *testing.T) {
+	skipIfNoRocketMQ(t)
+	client := getTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := testContext()
+	defer cancel()
+
+	groups, err := client.ListConsumerGroups(ctx)
+	if err != nil {
+		t.Fatalf("列出消费组失败: %v", err)
+	}
+	if len(groups) == 0 {
+		t.Skip("集群中没有用户消费组")
+	}
+
+	groupLag, err := client.ExamineConsumerLag(ctx, groups[0], LagOptions{ResolveClients: true, AccurateTimeLag: true})
+	if err != nil {
+		t.Fatalf("查询消费堆积失败: %v", err)
+	}
+
+	t.Logf("消费组 %s: 堆积 %d, 最大延迟 %dms, 在线客户端 %d",
+		groupLag.ConsumerGroup, groupLag.Lag, groupLag.MaxTimeLag, groupLag.OnlineClients)
+	for _, topicLag := range groupLag.Topics {
+		for _, q := range topicLag.Queues {
+			t.Logf("  - %s@%s@%d: lag=%d timeLag=%dms client=%s",
+				q.MessageQueue.Topic, q.MessageQueue.BrokerName, q.MessageQueue.QueueId, q.Lag, q.TimeLag, q.ClientId)
+		}
+	}
+}
+
+// TestIntegration_ExamineConsumerLagList 测试查询全部消费组堆积
+func TestIntegration_ExamineConsumerLagList(t *testing.T) {
+	skipIfNoRocketMQ(t)
+	client := getTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := testContext()
+	defer cancel()
+
+	groupLags, err := client.ExamineConsumerLagList(ctx, nil, LagOptions{})
+	if err != nil {
+		t.Fatalf("查询消费堆积失败: %v", err)
+	}
+
+	for i := 1; i < len(groupLags); i++ {
+		if groupLags[i-1].Lag < groupLags[i].Lag {
+			t.Errorf("结果应按堆积降序排列: %d < %d", groupLags[i-1].Lag, groupLags[i].Lag)
+		}
+	}
+	t.Logf("消费组数量: %d", len(groupLags))
+}
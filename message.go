@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
 )
@@ -229,6 +233,15 @@ func (c *Client) SearchOffset(ctx context.Context, brokerAddr, topic string, que
 		return 0, NewAdminError(resp.Code, resp.Remark)
 	}
 
+	// Broker 通过 ExtFields 返回 offset，兼容通过 Body 返回的实现
+	if value, ok := resp.ExtFields["offset"]; ok {
+		offset, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("解析偏移结果失败: %w", err)
+		}
+		return offset, nil
+	}
+
 	var result struct {
 		Offset int64 `json:"offset"`
 	}
@@ -245,12 +258,65 @@ func (c *Client) SearchOffset(ctx context.Context, brokerAddr, topic string, que
 
 // QueryMessage 按 Key 查询消息
 func (c *Client) QueryMessage(ctx context.Context, topic, key string, maxNum int, begin, end int64) ([]*MessageExt, error) {
+	return c.queryMessage(ctx, topic, key, maxNum, begin, end, false)
+}
+
+// QueryMessageByUniqKey 按客户端消息 ID（UNIQ_KEY）查询消息
+// 优先使用 Broker 的唯一键索引，查询起点由消息 ID 中携带的时间推算；
+// 若消息 ID 无法解析或索引未命中，则退化为 [begin, end] 范围内的普通 Key 查询
+func (c *Client) QueryMessageByUniqKey(ctx context.Context, topic, msgId string, begin, end int64) ([]*MessageExt, error) {
+	if nearlyTime, err := uniqKeyNearlyTime(msgId, time.Now()); err == nil {
+		msgs, err := c.queryMessage(ctx, topic, msgId, uniqKeyQueryMaxNum, nearlyTime.UnixMilli()-1000, math.MaxInt64, true)
+		if err != nil {
+			return nil, err
+		}
+		if len(msgs) > 0 {
+			return msgs, nil
+		}
+	}
+
+	if end <= 0 {
+		end = time.Now().UnixMilli()
+	}
+	msgs, err := c.queryMessage(ctx, topic, msgId, uniqKeyQueryMaxNum, begin, end, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// 普通 Key 索引基于哈希，需要按消息 ID 精确过滤
+	matched := msgs[:0]
+	for _, msg := range msgs {
+		if msg.MsgId == msgId {
+			matched = append(matched, msg)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMessageNotFound, msgId)
+	}
+
+	return matched, nil
+}
+
+// uniqKeyQueryMaxNum 按唯一键查询时的最大返回条数（与 Java 客户端一致）
+const uniqKeyQueryMaxNum = 32
+
+// uniqueKeyQueryFlag 唯一键查询标志（对应 Java MixAll.UNIQUE_MSG_QUERY_FLAG）
+const uniqueKeyQueryFlag = "_UNIQUE_KEY_QUERY"
+
+// queryMessage 向 Topic 所在的所有 Broker 查询索引，结果去重后按存储时间排序
+// 部分 Broker 失败时忽略其结果；没有任何 Broker 应答时返回 BrokerErrors
+func (c *Client) queryMessage(ctx context.Context, topic, key string, maxNum int, begin, end int64, isUniqKey bool) ([]*MessageExt, error) {
 	routeData, err := c.ExamineTopicRouteInfo(ctx, topic)
 	if err != nil {
 		return nil, err
 	}
 
-	var allMessages []*MessageExt
+	var (
+		seen        = make(map[string]bool)
+		allMessages []*MessageExt
+		brokerErrs  BrokerErrors
+		answered    bool
+	)
 	for _, brokerData := range routeData.BrokerDatas {
 		// 仅查询 Master
 		brokerAddr := brokerData.BrokerAddrs["0"]
@@ -259,29 +325,65 @@ func (c *Client) QueryMessage(ctx context.Context, topic, key string, maxNum int
 		}
 
 		extFields := map[string]string{
-			"topic":  topic,
-			"key":    key,
-			"maxNum": fmt.Sprintf("%d", maxNum),
-			"begin":  fmt.Sprintf("%d", begin),
-			"end":    fmt.Sprintf("%d", end),
+			"topic":            topic,
+			"key":              key,
+			"maxNum":           fmt.Sprintf("%d", maxNum),
+			"beginTimestamp":   fmt.Sprintf("%d", begin),
+			"endTimestamp":     fmt.Sprintf("%d", end),
+			uniqueKeyQueryFlag: fmt.Sprintf("%t", isUniqKey),
 		}
 
 		cmd := remoting.NewRequest(remoting.QueryMessage, extFields)
 		resp, err := c.invokeBroker(ctx, brokerAddr, cmd)
+		if err == nil && resp.Code != remoting.Success {
+			if resp.Code == remoting.QueryNotFound {
+				// 索引中没有该 Key
+				answered = true
+				continue
+			}
+			err = NewAdminError(resp.Code, resp.Remark)
+		}
 		if err != nil {
+			brokerErrs = append(brokerErrs, &BrokerError{BrokerName: brokerData.BrokerName, Addr: brokerAddr, Err: err})
 			continue
 		}
 
-		if resp.Code != remoting.Success {
+		msgs, err := decodeMessages(resp.Body)
+		if err != nil {
+			brokerErrs = append(brokerErrs, &BrokerError{BrokerName: brokerData.BrokerName, Addr: brokerAddr, Err: err})
 			continue
 		}
+		answered = true
 
-		var msgs []*MessageExt
-		if err := json.Unmarshal(resp.Body, &msgs); err == nil {
-			allMessages = append(allMessages, msgs...)
+		for _, msg := range msgs {
+			// 唯一键索引同样基于哈希，需要按消息 ID 精确过滤
+			if isUniqKey && msg.MsgId != key {
+				continue
+			}
+			if msg.BrokerName == "" {
+				msg.BrokerName = brokerData.BrokerName
+			}
+
+			dedupKey := msg.OffsetMsgId
+			if dedupKey == "" {
+				dedupKey = fmt.Sprintf("%s@%s@%d@%d", msg.Topic, msg.BrokerName, msg.QueueId, msg.QueueOffset)
+			}
+			if seen[dedupKey] {
+				continue
+			}
+			seen[dedupKey] = true
+			allMessages = append(allMessages, msg)
 		}
 	}
 
+	if !answered && len(brokerErrs) > 0 {
+		return nil, fmt.Errorf("查询消息失败: %w", brokerErrs)
+	}
+
+	sort.SliceStable(allMessages, func(i, j int) bool {
+		return allMessages[i].StoreTimestamp < allMessages[j].StoreTimestamp
+	})
+
 	return allMessages, nil
 }
 
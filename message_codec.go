@@ -0,0 +1,435 @@
+package admin
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// 消息二进制编解码
+// =============================================================================
+
+// Broker 对 QUERY_MESSAGE、VIEW_MESSAGE_BY_ID、PULL_MESSAGE 等请求返回的是
+// CommitLog 中的原始消息格式（对应 Java MessageDecoder），而不是 JSON
+
+// 消息魔数
+const (
+	messageMagicCodeV1 int32 = -626843481
+	messageMagicCodeV2 int32 = -626843477
+)
+
+// 消息系统标志位
+const (
+	sysFlagCompressed   = 0x1
+	sysFlagBornHostV6   = 0x1 << 4
+	sysFlagStoreHostV6  = 0x1 << 5
+	sysFlagCompressType = 0x7 << 8
+	compressTypeZlib    = 0x3 << 8
+)
+
+// 消息属性分隔符
+const (
+	nameValueSeparator = '\x01'
+	propertySeparator  = '\x02'
+)
+
+// 常用消息属性 Key（对应 Java MessageConst）
+const (
+	PropertyKeys                = "KEYS"
+	PropertyTags                = "TAGS"
+	PropertyUniqKey             = "UNIQ_KEY"
+	PropertyDelayLevel          = "DELAY"
+	PropertyRetryTopic          = "RETRY_TOPIC"
+	PropertyRealTopic           = "REAL_TOPIC"
+	PropertyRealQueueId         = "REAL_QID"
+	PropertyOriginMessageId     = "ORIGIN_MESSAGE_ID"
+	PropertyReconsumeTime       = "RECONSUME_TIME"
+	PropertyMaxReconsumeTimes   = "MAX_RECONSUME_TIMES"
+	PropertyWaitStoreMsgOK      = "WAIT"
+	PropertyProducerGroup       = "PGROUP"
+	PropertyTransactionPrepared = "TRAN_MSG"
+)
+
+// decodeMessages 解码 Broker 返回的消息列表
+// 优先按二进制格式解析，失败时回退到 JSON 格式（兼容代理或旧版实现）
+func decodeMessages(body []byte) ([]*MessageExt, error) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	msgs, err := decodeBinaryMessages(body)
+	if err == nil {
+		return msgs, nil
+	}
+
+	var jsonMsgs []*MessageExt
+	if jsonErr := json.Unmarshal(body, &jsonMsgs); jsonErr == nil {
+		return jsonMsgs, nil
+	}
+
+	return nil, err
+}
+
+// decodeBinaryMessages 解码连续存放的二进制消息
+func decodeBinaryMessages(body []byte) ([]*MessageExt, error) {
+	var msgs []*MessageExt
+	for len(body) > 0 {
+		if len(body) < 4 {
+			return nil, ErrInvalidMessageData
+		}
+		totalSize := int(int32(binary.BigEndian.Uint32(body)))
+		if totalSize <= 0 || totalSize > len(body) {
+			return nil, ErrInvalidMessageData
+		}
+
+		msg, err := decodeMessage(body[:totalSize])
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+		body = body[totalSize:]
+	}
+	return msgs, nil
+}
+
+// messageReader 按大端序顺序读取消息字段
+type messageReader struct {
+	buf []byte
+	pos int
+	err error
+}
+
+func (r *messageReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.pos+n > len(r.buf) {
+		r.err = ErrInvalidMessageData
+		return nil
+	}
+	b := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *messageReader) int16() int16 {
+	b := r.next(2)
+	if b == nil {
+		return 0
+	}
+	return int16(binary.BigEndian.Uint16(b))
+}
+
+func (r *messageReader) int32() int32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+func (r *messageReader) int64() int64 {
+	b := r.next(8)
+	if b == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+// host 读取 IP + 端口，v6 表示 IPv6 地址
+func (r *messageReader) host(v6 bool) (ip []byte, port int32) {
+	ipLen := 4
+	if v6 {
+		ipLen = 16
+	}
+	ip = r.next(ipLen)
+	port = r.int32()
+	return ip, port
+}
+
+// decodeMessage 解码单条二进制消息
+func decodeMessage(data []byte) (*MessageExt, error) {
+	r := &messageReader{buf: data}
+	msg := &MessageExt{}
+
+	r.int32() // TOTALSIZE
+	magicCode := r.int32()
+	if r.err == nil && magicCode != messageMagicCodeV1 && magicCode != messageMagicCodeV2 {
+		return nil, ErrInvalidMessageData
+	}
+	msg.BodyCRC = r.int32()
+	msg.QueueId = int(r.int32())
+	msg.Flag = int(r.int32())
+	msg.QueueOffset = r.int64()
+	msg.CommitLogOffset = r.int64()
+	msg.SysFlag = int(r.int32())
+	msg.BornTimestamp = r.int64()
+	bornIP, bornPort := r.host(msg.SysFlag&sysFlagBornHostV6 != 0)
+	msg.StoreTimestamp = r.int64()
+	storeIP, storePort := r.host(msg.SysFlag&sysFlagStoreHostV6 != 0)
+	msg.ReconsumeTimes = int(r.int32())
+	msg.PreparedTransactionOffset = r.int64()
+
+	bodyLen := r.int32()
+	body := r.next(int(bodyLen))
+
+	var topicLen int
+	if magicCode == messageMagicCodeV2 {
+		topicLen = int(r.int16())
+	} else {
+		b := r.next(1)
+		if b != nil {
+			topicLen = int(b[0])
+		}
+	}
+	topic := r.next(topicLen)
+
+	propsLen := r.int16()
+	props := r.next(int(propsLen))
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	msg.Topic = string(topic)
+	msg.Properties = string2MessageProperties(string(props))
+	msg.BornHost = formatHost(bornIP, bornPort)
+	msg.StoreHost = formatHost(storeIP, storePort)
+	msg.OffsetMsgId = createOffsetMessageId(storeIP, storePort, msg.CommitLogOffset)
+	msg.MsgId = msg.OffsetMsgId
+	if uniqKey := msg.Properties[PropertyUniqKey]; uniqKey != "" {
+		msg.MsgId = uniqKey
+	}
+
+	msg.Body = append([]byte(nil), body...)
+	if msg.SysFlag&sysFlagCompressed != 0 {
+		compressType := msg.SysFlag & sysFlagCompressType
+		// 仅支持 zlib（早期版本不设置压缩类型，默认即为 zlib）
+		if compressType == 0 || compressType == compressTypeZlib {
+			if unzipped, err := zlibDecompress(body); err == nil {
+				msg.Body = unzipped
+			}
+		}
+	}
+
+	return msg, nil
+}
+
+// encodeMessage 按 CommitLog 格式编码单条消息（消息体不压缩）
+func encodeMessage(msg *MessageExt) ([]byte, error) {
+	bornIP, bornPort, err := parseHost(msg.BornHost)
+	if err != nil {
+		return nil, err
+	}
+	storeIP, storePort, err := parseHost(msg.StoreHost)
+	if err != nil {
+		return nil, err
+	}
+
+	sysFlag := msg.SysFlag &^ (sysFlagCompressed | sysFlagCompressType | sysFlagBornHostV6 | sysFlagStoreHostV6)
+	if len(bornIP) == net.IPv6len {
+		sysFlag |= sysFlagBornHostV6
+	}
+	if len(storeIP) == net.IPv6len {
+		sysFlag |= sysFlagStoreHostV6
+	}
+
+	props := messageProperties2String(msg.Properties)
+	if len(props) > math.MaxInt16 {
+		return nil, fmt.Errorf("%w: 消息属性过长", ErrInvalidMessageData)
+	}
+
+	magicCode := messageMagicCodeV1
+	if len(msg.Topic) > math.MaxUint8 {
+		magicCode = messageMagicCodeV2
+	}
+
+	buf := make([]byte, 4, 128+len(msg.Body)+len(msg.Topic)+len(props))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(magicCode))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(msg.BodyCRC))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(msg.QueueId))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(msg.Flag))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(msg.QueueOffset))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(msg.CommitLogOffset))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(sysFlag))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(msg.BornTimestamp))
+	buf = append(buf, bornIP...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(bornPort))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(msg.StoreTimestamp))
+	buf = append(buf, storeIP...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(storePort))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(msg.ReconsumeTimes))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(msg.PreparedTransactionOffset))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(msg.Body)))
+	buf = append(buf, msg.Body...)
+	if magicCode == messageMagicCodeV2 {
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(msg.Topic)))
+	} else {
+		buf = append(buf, byte(len(msg.Topic)))
+	}
+	buf = append(buf, msg.Topic...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(props)))
+	buf = append(buf, props...)
+
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(buf)))
+	return buf, nil
+}
+
+// parseHost 解析 IP:Port，为空时返回 0.0.0.0:0
+func parseHost(host string) (net.IP, int32, error) {
+	if host == "" {
+		return net.IPv4zero.To4(), 0, nil
+	}
+	h, p, err := net.SplitHostPort(host)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrInvalidMessageData, host)
+	}
+	ip := net.ParseIP(h)
+	if ip == nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrInvalidMessageData, host)
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	port, err := strconv.Atoi(p)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrInvalidMessageData, host)
+	}
+	return ip, int32(port), nil
+}
+
+// zlibDecompress 解压 zlib 压缩的消息体
+func zlibDecompress(data []byte) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// formatHost 格式化 IP:Port
+func formatHost(ip []byte, port int32) string {
+	if ip == nil {
+		return ""
+	}
+	return net.JoinHostPort(net.IP(ip).String(), strconv.Itoa(int(port)))
+}
+
+// =============================================================================
+// 消息属性
+// =============================================================================
+
+// string2MessageProperties 解析消息属性字符串
+func string2MessageProperties(s string) map[string]string {
+	props := make(map[string]string)
+	for _, item := range strings.Split(s, string(propertySeparator)) {
+		if item == "" {
+			continue
+		}
+		idx := strings.IndexByte(item, nameValueSeparator)
+		if idx < 0 {
+			continue
+		}
+		props[item[:idx]] = item[idx+1:]
+	}
+	return props
+}
+
+// messageProperties2String 将消息属性编码为字符串
+func messageProperties2String(props map[string]string) string {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte(nameValueSeparator)
+		sb.WriteString(props[k])
+		sb.WriteByte(propertySeparator)
+	}
+	return sb.String()
+}
+
+// =============================================================================
+// 消息 ID
+// =============================================================================
+
+// createOffsetMessageId 由存储地址和 CommitLog 偏移生成 OffsetMsgId
+func createOffsetMessageId(storeIP []byte, storePort int32, commitLogOffset int64) string {
+	buf := make([]byte, 0, len(storeIP)+12)
+	buf = append(buf, storeIP...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(storePort))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(commitLogOffset))
+	return strings.ToUpper(hex.EncodeToString(buf))
+}
+
+// MessageIdInfo OffsetMsgId 解析结果
+type MessageIdInfo struct {
+	StoreHost       string // 存储地址
+	CommitLogOffset int64  // CommitLog 偏移
+}
+
+// DecodeOffsetMessageId 解析 OffsetMsgId，得到存储地址和 CommitLog 偏移
+func DecodeOffsetMessageId(msgId string) (*MessageIdInfo, error) {
+	data, err := hex.DecodeString(msgId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidMessageData, msgId)
+	}
+
+	var ipLen int
+	switch len(data) {
+	case 16:
+		ipLen = 4
+	case 28:
+		ipLen = 16
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidMessageData, msgId)
+	}
+
+	port := int32(binary.BigEndian.Uint32(data[ipLen : ipLen+4]))
+	return &MessageIdInfo{
+		StoreHost:       formatHost(data[:ipLen], port),
+		CommitLogOffset: int64(binary.BigEndian.Uint64(data[ipLen+4:])),
+	}, nil
+}
+
+// uniqKeyNearlyTime 从客户端消息 ID（UNIQ_KEY）中推算消息的大致发送时间
+// UNIQ_KEY 格式: IP + PID(2) + ClassLoader(4) + 距当月起始的毫秒数(4) + 计数器(2)
+func uniqKeyNearlyTime(uniqKey string, now time.Time) (time.Time, error) {
+	data, err := hex.DecodeString(uniqKey)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidMessageData, uniqKey)
+	}
+
+	ipLen := 4
+	if len(data) == 28 {
+		ipLen = 16
+	}
+	if len(data) < ipLen+2+4+4 {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidMessageData, uniqKey)
+	}
+
+	spanMS := int64(binary.BigEndian.Uint32(data[ipLen+2+4 : ipLen+2+4+4]))
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	if monthStart.UnixMilli()+spanMS >= now.UnixMilli() {
+		monthStart = monthStart.AddDate(0, -1, 0)
+	}
+
+	return time.UnixMilli(monthStart.UnixMilli() + spanMS), nil
+}
@@ -0,0 +1,160 @@
+package admin
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// 消息编解码单元测试
+// =============================================================================
+
+// encodeTestMessage 按 CommitLog 格式编码消息（仅用于测试）
+func encodeTestMessage(msg *MessageExt, storeIP net.IP, storePort int32) []byte {
+	var props strings.Builder
+	for k, v := range msg.Properties {
+		props.WriteString(k)
+		props.WriteByte(nameValueSeparator)
+		props.WriteString(v)
+		props.WriteByte(propertySeparator)
+	}
+
+	magicCode := messageMagicCodeV1
+	buf := make([]byte, 4)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(magicCode))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(msg.BodyCRC))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(msg.QueueId))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(msg.Flag))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(msg.QueueOffset))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(msg.CommitLogOffset))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(msg.SysFlag))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(msg.BornTimestamp))
+	buf = append(buf, 127, 0, 0, 1)
+	buf = binary.BigEndian.AppendUint32(buf, 50000)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(msg.StoreTimestamp))
+	buf = append(buf, storeIP.To4()...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(storePort))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(msg.ReconsumeTimes))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(msg.PreparedTransactionOffset))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(msg.Body)))
+	buf = append(buf, msg.Body...)
+	buf = append(buf, byte(len(msg.Topic)))
+	buf = append(buf, msg.Topic...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(props.Len()))
+	buf = append(buf, props.String()...)
+
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(buf)))
+	return buf
+}
+
+// TestDecodeMessages 测试解码二进制消息
+func TestDecodeMessages(t *testing.T) {
+	storeIP := net.ParseIP("192.168.0.10")
+	first := &MessageExt{
+		Topic:           "TopicTest",
+		QueueId:         3,
+		QueueOffset:     100,
+		CommitLogOffset: 4096,
+		BornTimestamp:   1700000000000,
+		StoreTimestamp:  1700000000100,
+		ReconsumeTimes:  2,
+		Body:            []byte("hello"),
+		Properties: map[string]string{
+			PropertyUniqKey: "7F00000100002A9F0000000000000001",
+			PropertyTags:    "TagA",
+			PropertyKeys:    "k1 k2",
+		},
+	}
+	second := &MessageExt{
+		Topic:           "TopicTest",
+		QueueId:         1,
+		QueueOffset:     7,
+		CommitLogOffset: 8192,
+		Body:            []byte("world"),
+	}
+
+	body := append(encodeTestMessage(first, storeIP, 10911), encodeTestMessage(second, storeIP, 10911)...)
+	msgs, err := decodeMessages(body)
+	if err != nil {
+		t.Fatalf("解码消息失败: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("消息数量应为 2, got %d", len(msgs))
+	}
+
+	msg := msgs[0]
+	if msg.Topic != "TopicTest" || msg.QueueId != 3 || msg.QueueOffset != 100 {
+		t.Errorf("消息基础字段不匹配: %+v", msg)
+	}
+	if msg.ReconsumeTimes != 2 {
+		t.Errorf("ReconsumeTimes 应为 2, got %d", msg.ReconsumeTimes)
+	}
+	if string(msg.Body) != "hello" {
+		t.Errorf("Body 不匹配: %s", msg.Body)
+	}
+	if msg.MsgId != first.Properties[PropertyUniqKey] {
+		t.Errorf("MsgId 应取 UNIQ_KEY, got %s", msg.MsgId)
+	}
+	if msg.GetTags() != "TagA" || len(msg.GetKeys()) != 2 {
+		t.Errorf("Tags/Keys 解析错误: %s %v", msg.GetTags(), msg.GetKeys())
+	}
+	if msg.StoreHost != "192.168.0.10:10911" {
+		t.Errorf("StoreHost 不匹配: %s", msg.StoreHost)
+	}
+
+	// 没有 UNIQ_KEY 时 MsgId 等于 OffsetMsgId
+	if msgs[1].MsgId != msgs[1].OffsetMsgId {
+		t.Errorf("MsgId 应等于 OffsetMsgId: %s != %s", msgs[1].MsgId, msgs[1].OffsetMsgId)
+	}
+
+	info, err := DecodeOffsetMessageId(msg.OffsetMsgId)
+	if err != nil {
+		t.Fatalf("解析 OffsetMsgId 失败: %v", err)
+	}
+	if info.StoreHost != "192.168.0.10:10911" || info.CommitLogOffset != 4096 {
+		t.Errorf("OffsetMsgId 解析结果不匹配: %+v", info)
+	}
+}
+
+// TestDecodeMessages_Invalid 测试解码非法数据
+func TestDecodeMessages_Invalid(t *testing.T) {
+	if _, err := decodeMessages([]byte{0, 0, 0, 10, 1, 2}); err == nil {
+		t.Error("非法数据应返回错误")
+	}
+
+	msgs, err := decodeMessages([]byte(`[{"topic":"TopicTest","msgId":"abc"}]`))
+	if err != nil {
+		t.Fatalf("JSON 回退解析失败: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].MsgId != "abc" {
+		t.Errorf("JSON 回退解析结果不匹配: %+v", msgs)
+	}
+}
+
+// TestUniqKeyNearlyTime 测试从 UNIQ_KEY 推算发送时间
+func TestUniqKeyNearlyTime(t *testing.T) {
+	now := time.Date(2024, 5, 20, 12, 0, 0, 0, time.Local)
+	monthStart := time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)
+	sent := now.Add(-time.Hour)
+	spanMS := uint32(sent.UnixMilli() - monthStart.UnixMilli())
+
+	data := []byte{127, 0, 0, 1, 0x2A, 0x9F, 0, 0, 0, 1}
+	data = binary.BigEndian.AppendUint32(data, spanMS)
+	data = append(data, 0, 1)
+
+	got, err := uniqKeyNearlyTime(strings.ToUpper(hex.EncodeToString(data)), now)
+	if err != nil {
+		t.Fatalf("解析 UNIQ_KEY 失败: %v", err)
+	}
+	if !got.Equal(sent) {
+		t.Errorf("推算时间不匹配: got %v, want %v", got, sent)
+	}
+
+	if _, err := uniqKeyNearlyTime("not-hex", now); err == nil {
+		t.Error("非法 UNIQ_KEY 应返回错误")
+	}
+}
@@ -0,0 +1,431 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// 消息导出与导入
+// =============================================================================
+
+// MessageFileFormat 消息文件格式
+type MessageFileFormat string
+
+// 消息文件格式定义
+const (
+	// MessageFileFormatJSONLines 每行一条 JSON 消息，消息体为 Base64
+	MessageFileFormatJSONLines MessageFileFormat = "jsonl"
+	// MessageFileFormatBinary 连续存放的 CommitLog 格式消息，每条以 4 字节总长度开头
+	// 该格式不保留 BrokerName
+	MessageFileFormatBinary MessageFileFormat = "binary"
+)
+
+// 导出导入默认参数
+const (
+	exportPullBatchSize      = 32
+	importCheckpointInterval = 100
+)
+
+// MessageWriter 消息文件写入器
+type MessageWriter interface {
+	Write(msg *MessageExt) error
+}
+
+// MessageReader 消息文件读取器，读取完毕时返回 io.EOF
+type MessageReader interface {
+	Read() (*MessageExt, error)
+}
+
+// NewMessageWriter 创建指定格式的消息写入器，format 为空时使用 JSON Lines
+func NewMessageWriter(w io.Writer, format MessageFileFormat) (MessageWriter, error) {
+	switch format {
+	case "", MessageFileFormatJSONLines:
+		return &jsonMessageWriter{enc: json.NewEncoder(w)}, nil
+	case MessageFileFormatBinary:
+		return &binaryMessageWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("不支持的消息文件格式: %s", format)
+	}
+}
+
+// NewMessageReader 创建指定格式的消息读取器，format 为空时使用 JSON Lines
+func NewMessageReader(r io.Reader, format MessageFileFormat) (MessageReader, error) {
+	switch format {
+	case "", MessageFileFormatJSONLines:
+		return &jsonMessageReader{dec: json.NewDecoder(r)}, nil
+	case MessageFileFormatBinary:
+		return &binaryMessageReader{r: bufio.NewReader(r)}, nil
+	default:
+		return nil, fmt.Errorf("不支持的消息文件格式: %s", format)
+	}
+}
+
+type jsonMessageWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonMessageWriter) Write(msg *MessageExt) error {
+	return w.enc.Encode(msg)
+}
+
+type jsonMessageReader struct {
+	dec *json.Decoder
+}
+
+func (r *jsonMessageReader) Read() (*MessageExt, error) {
+	var msg MessageExt
+	if err := r.dec.Decode(&msg); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMessageData, err)
+	}
+	return &msg, nil
+}
+
+type binaryMessageWriter struct {
+	w io.Writer
+}
+
+func (w *binaryMessageWriter) Write(msg *MessageExt) error {
+	data, err := encodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(data)
+	return err
+}
+
+type binaryMessageReader struct {
+	r *bufio.Reader
+}
+
+func (r *binaryMessageReader) Read() (*MessageExt, error) {
+	header, err := r.r.Peek(4)
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, ErrInvalidMessageData
+	}
+
+	totalSize := int(int32(binary.BigEndian.Uint32(header)))
+	if totalSize <= 4 {
+		return nil, ErrInvalidMessageData
+	}
+	data := make([]byte, totalSize)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return nil, ErrInvalidMessageData
+	}
+	return decodeMessage(data)
+}
+
+// =============================================================================
+// 导出
+// =============================================================================
+
+// MessageExportOptions 消息导出选项
+type MessageExportOptions struct {
+	Format         MessageFileFormat // 文件格式，默认 JSON Lines
+	Queues         []MessageQueue    // 导出的队列，为空时导出全部队列（仅匹配 BrokerName 和 QueueId）
+	BeginTimestamp int64             // 存储时间下限（毫秒），0 表示从最小偏移开始
+	EndTimestamp   int64             // 存储时间上限（毫秒），0 表示导出到开始导出时的最大偏移
+	MaxMessages    int64             // 最多导出条数，<=0 表示不限
+}
+
+// MessageExportQueueResult 单个队列的导出结果
+type MessageExportQueueResult struct {
+	MessageQueue MessageQueue `json:"messageQueue"` // 队列
+	BeginOffset  int64        `json:"beginOffset"`  // 起始偏移
+	EndOffset    int64        `json:"endOffset"`    // 结束偏移（不含）
+	Count        int64        `json:"count"`        // 导出条数
+}
+
+// MessageExportResult 导出结果
+type MessageExportResult struct {
+	Topic  string                     `json:"topic"`  // Topic
+	Total  int64                      `json:"total"`  // 导出总条数
+	Queues []MessageExportQueueResult `json:"queues"` // 各队列导出结果
+}
+
+// ExportMessages 通过 PULL_MESSAGE 导出 Topic 消息到 w，不影响任何消费组的进度
+// 导出范围为开始导出时各队列的 [最小偏移, 最大偏移)，可按队列和存储时间进一步缩小
+func (c *Client) ExportMessages(ctx context.Context, topic string, w io.Writer, opts MessageExportOptions) (*MessageExportResult, error) {
+	writer, err := NewMessageWriter(w, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	routeData, err := c.ExamineTopicRouteInfo(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+	masters := masterAddrs(routeData)
+
+	statsTable, err := c.ExamineTopicStats(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MessageExportResult{Topic: topic}
+	for key, offset := range statsTable.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil || !containsQueue(opts.Queues, mq) {
+			continue
+		}
+		result.Queues = append(result.Queues, MessageExportQueueResult{
+			MessageQueue: mq,
+			BeginOffset:  offset.MinOffset,
+			EndOffset:    offset.MaxOffset,
+		})
+	}
+	sort.Slice(result.Queues, func(i, j int) bool { return queueLess(result.Queues[i].MessageQueue, result.Queues[j].MessageQueue) })
+
+	for i := range result.Queues {
+		queue := &result.Queues[i]
+		brokerAddr, ok := masters[queue.MessageQueue.BrokerName]
+		if !ok {
+			return result, fmt.Errorf("%w: %s", ErrBrokerNotFound, queue.MessageQueue.BrokerName)
+		}
+
+		if opts.BeginTimestamp > 0 {
+			offset, err := c.SearchOffset(ctx, brokerAddr, topic, queue.MessageQueue.QueueId, opts.BeginTimestamp)
+			if err != nil {
+				return result, fmt.Errorf("按时间搜索 %s 队列 %d 偏移失败: %w", queue.MessageQueue.BrokerName, queue.MessageQueue.QueueId, err)
+			}
+			queue.BeginOffset = max(queue.BeginOffset, min(offset, queue.EndOffset))
+		}
+
+		var limit int64 = -1
+		if opts.MaxMessages > 0 {
+			limit = opts.MaxMessages - result.Total
+		}
+		err := c.exportQueue(ctx, brokerAddr, queue, writer, opts, limit)
+		result.Total += queue.Count
+		if err != nil {
+			return result, err
+		}
+		if opts.MaxMessages > 0 && result.Total >= opts.MaxMessages {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// exportQueue 导出单个队列，limit < 0 表示不限条数
+func (c *Client) exportQueue(ctx context.Context, brokerAddr string, queue *MessageExportQueueResult, writer MessageWriter, opts MessageExportOptions, limit int64) error {
+	offset := queue.BeginOffset
+	for offset < queue.EndOffset {
+		pullResult, err := c.PullMessage(ctx, brokerAddr, queue.MessageQueue, "", offset, exportPullBatchSize)
+		if err != nil {
+			return fmt.Errorf("拉取 %s 队列 %d 消息失败: %w", queue.MessageQueue.BrokerName, queue.MessageQueue.QueueId, err)
+		}
+
+		for _, msg := range pullResult.Messages {
+			if msg.QueueOffset >= queue.EndOffset {
+				return nil
+			}
+			// 同一队列内存储时间基本递增，超过时间上限即可结束
+			if opts.EndTimestamp > 0 && msg.StoreTimestamp > opts.EndTimestamp {
+				return nil
+			}
+			if opts.BeginTimestamp > 0 && msg.StoreTimestamp < opts.BeginTimestamp {
+				continue
+			}
+			if err := writer.Write(msg); err != nil {
+				return fmt.Errorf("写入消息失败: %w", err)
+			}
+			queue.Count++
+			if limit >= 0 && queue.Count >= limit {
+				return nil
+			}
+		}
+
+		if pullResult.Status != PullStatusFound && pullResult.Status != PullStatusOffsetIllegal &&
+			pullResult.Status != PullStatusNoMatchedMsg {
+			return nil
+		}
+		if pullResult.NextBeginOffset <= offset {
+			return nil
+		}
+		offset = pullResult.NextBeginOffset
+	}
+	return nil
+}
+
+// containsQueue 判断队列是否在指定集合中，集合为空表示全部
+func containsQueue(queues []MessageQueue, mq MessageQueue) bool {
+	if len(queues) == 0 {
+		return true
+	}
+	for _, q := range queues {
+		if q.BrokerName == mq.BrokerName && q.QueueId == mq.QueueId {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// 导入
+// =============================================================================
+
+// MessageImportOptions 消息导入选项
+type MessageImportOptions struct {
+	Format         MessageFileFormat // 文件格式，默认 JSON Lines
+	TargetTopic    string            // 目标 Topic，为空时发送到消息原 Topic
+	RateLimit      int               // 每秒最多发送条数，<=0 表示不限速
+	ProducerGroup  string            // 生产者组，为空时使用默认组
+	CheckpointFile string            // 断点文件，为空时不记录断点；存在时从断点位置继续导入
+}
+
+// MessageImportResult 导入结果
+type MessageImportResult struct {
+	Skipped int64 `json:"skipped"` // 按断点跳过的条数
+	Sent    int64 `json:"sent"`    // 本次发送成功条数
+}
+
+// messageImportCheckpoint 导入断点
+type messageImportCheckpoint struct {
+	Position   int64 `json:"position"`   // 已成功导入的消息条数
+	UpdateTime int64 `json:"updateTime"` // 更新时间（毫秒）
+}
+
+// ImportMessages 将导出文件中的消息通过 SEND_MESSAGE_V2 回放到目标 Topic
+// 使用目标集群的 Client 调用即可导入到其他集群；保留消息的 Key、Tag、UNIQ_KEY 和用户属性
+// 发送失败时立即返回错误，断点停在最后一条成功消息之后；断点按批写入，中断后重新导入可能产生少量重复
+func (c *Client) ImportMessages(ctx context.Context, r io.Reader, opts MessageImportOptions) (*MessageImportResult, error) {
+	reader, err := NewMessageReader(r, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MessageImportResult{}
+	var position int64
+	if opts.CheckpointFile != "" {
+		checkpoint, err := loadImportCheckpoint(opts.CheckpointFile)
+		if err != nil {
+			return nil, err
+		}
+		position = checkpoint.Position
+	}
+
+	limiter := newRateLimiter(opts.RateLimit)
+	defer limiter.stop()
+
+	var index int64
+	saved := position
+	for {
+		msg, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, finishImport(opts, position, saved, fmt.Errorf("读取第 %d 条消息失败: %w", index+1, err))
+		}
+		index++
+		if index <= position {
+			result.Skipped++
+			continue
+		}
+
+		if err := limiter.wait(ctx); err != nil {
+			return result, finishImport(opts, position, saved, err)
+		}
+		if _, err := c.sendMessage(ctx, buildImportRequest(msg, opts)); err != nil {
+			return result, finishImport(opts, position, saved, fmt.Errorf("发送第 %d 条消息失败: %w", index, err))
+		}
+		result.Sent++
+		position = index
+
+		if opts.CheckpointFile != "" && position-saved >= importCheckpointInterval {
+			if err := saveImportCheckpoint(opts.CheckpointFile, position); err != nil {
+				return result, err
+			}
+			saved = position
+		}
+	}
+
+	return result, finishImport(opts, position, saved, nil)
+}
+
+// finishImport 保存最终断点，并返回原始错误
+func finishImport(opts MessageImportOptions, position, saved int64, cause error) error {
+	if opts.CheckpointFile == "" || position == saved {
+		return cause
+	}
+	if err := saveImportCheckpoint(opts.CheckpointFile, position); err != nil && cause == nil {
+		return err
+	}
+	return cause
+}
+
+// buildImportRequest 构造回放请求
+func buildImportRequest(msg *MessageExt, opts MessageImportOptions) *sendRequest {
+	props := make(map[string]string, len(msg.Properties))
+	for k, v := range msg.Properties {
+		props[k] = v
+	}
+	for _, k := range resendDroppedProperties {
+		delete(props, k)
+	}
+
+	topic := opts.TargetTopic
+	if topic == "" {
+		topic = msg.Topic
+	}
+
+	return &sendRequest{
+		topic:         topic,
+		body:          msg.Body,
+		properties:    props,
+		producerGroup: opts.ProducerGroup,
+	}
+}
+
+// loadImportCheckpoint 读取导入断点，文件不存在时返回空断点
+func loadImportCheckpoint(path string) (*messageImportCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &messageImportCheckpoint{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取导入断点失败: %w", err)
+	}
+
+	var checkpoint messageImportCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("解析导入断点失败: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+// saveImportCheckpoint 写入导入断点（先写临时文件再重命名，避免断点损坏）
+func saveImportCheckpoint(path string, position int64) error {
+	data, err := json.Marshal(messageImportCheckpoint{
+		Position:   position,
+		UpdateTime: time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("写入导入断点失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("写入导入断点失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package admin
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+// =============================================================================
+// 消息导出导入单元测试
+// =============================================================================
+
+// testExportMessages 构造测试消息
+func testExportMessages() []*MessageExt {
+	return []*MessageExt{
+		{
+			Topic:          "TopicTest",
+			QueueId:        1,
+			QueueOffset:    10,
+			Body:           []byte("hello"),
+			BornTimestamp:  1700000000000,
+			StoreTimestamp: 1700000000100,
+			BornHost:       "10.0.0.1:50000",
+			StoreHost:      "10.0.0.2:10911",
+			Properties: map[string]string{
+				PropertyTags:    "TagA",
+				PropertyKeys:    "k1 k2",
+				PropertyUniqKey: "7F00000100002A9F0000000000000001",
+				"custom":        "v",
+			},
+		},
+		{
+			Topic:          "TopicTest",
+			QueueId:        2,
+			QueueOffset:    11,
+			Body:           []byte("world"),
+			StoreTimestamp: 1700000000200,
+			BornHost:       "[::1]:50001",
+			StoreHost:      "10.0.0.2:10911",
+		},
+	}
+}
+
+// TestMessageFileRoundTrip 测试两种文件格式的写入与读取
+func TestMessageFileRoundTrip(t *testing.T) {
+	for _, format := range []MessageFileFormat{MessageFileFormatJSONLines, MessageFileFormatBinary} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := NewMessageWriter(&buf, format)
+			if err != nil {
+				t.Fatalf("创建写入器失败: %v", err)
+			}
+			msgs := testExportMessages()
+			for _, msg := range msgs {
+				if err := writer.Write(msg); err != nil {
+					t.Fatalf("写入消息失败: %v", err)
+				}
+			}
+
+			reader, err := NewMessageReader(&buf, format)
+			if err != nil {
+				t.Fatalf("创建读取器失败: %v", err)
+			}
+			for i, want := range msgs {
+				got, err := reader.Read()
+				if err != nil {
+					t.Fatalf("读取第 %d 条消息失败: %v", i+1, err)
+				}
+				if got.Topic != want.Topic || got.QueueId != want.QueueId || got.QueueOffset != want.QueueOffset ||
+					string(got.Body) != string(want.Body) || got.StoreTimestamp != want.StoreTimestamp ||
+					got.BornHost != want.BornHost || got.StoreHost != want.StoreHost {
+					t.Errorf("消息不匹配: got %+v, want %+v", got, want)
+				}
+				for k, v := range want.Properties {
+					if got.Properties[k] != v {
+						t.Errorf("属性 %s 不匹配: got %q, want %q", k, got.Properties[k], v)
+					}
+				}
+			}
+			if _, err := reader.Read(); !errors.Is(err, io.EOF) {
+				t.Errorf("读取完毕应返回 io.EOF, got %v", err)
+			}
+		})
+	}
+
+	if _, err := NewMessageWriter(io.Discard, "xml"); err == nil {
+		t.Error("不支持的格式应返回错误")
+	}
+}
+
+// TestBinaryMessageReader_Truncated 测试读取截断的二进制文件
+func TestBinaryMessageReader_Truncated(t *testing.T) {
+	data, err := encodeMessage(testExportMessages()[0])
+	if err != nil {
+		t.Fatalf("编码消息失败: %v", err)
+	}
+
+	reader, _ := NewMessageReader(bytes.NewReader(data[:len(data)-3]), MessageFileFormatBinary)
+	if _, err := reader.Read(); !errors.Is(err, ErrInvalidMessageData) {
+		t.Errorf("截断数据应返回 ErrInvalidMessageData, got %v", err)
+	}
+}
+
+// TestBuildImportRequest 测试构造回放请求
+func TestBuildImportRequest(t *testing.T) {
+	msg := testExportMessages()[0]
+	msg.Properties[PropertyDelayLevel] = "3"
+	msg.Properties[PropertyRealTopic] = "RealTopic"
+
+	req := buildImportRequest(msg, MessageImportOptions{})
+	if req.topic != "TopicTest" {
+		t.Errorf("默认应回放到原 Topic, got %s", req.topic)
+	}
+	if req.properties[PropertyUniqKey] != msg.Properties[PropertyUniqKey] || req.properties["custom"] != "v" {
+		t.Errorf("UNIQ_KEY 和用户属性应保留: %v", req.properties)
+	}
+	if _, ok := req.properties[PropertyDelayLevel]; ok {
+		t.Error("延时属性应被移除")
+	}
+
+	req = buildImportRequest(msg, MessageImportOptions{TargetTopic: "TargetTopic"})
+	if req.topic != "TargetTopic" {
+		t.Errorf("应回放到目标 Topic, got %s", req.topic)
+	}
+}
+
+// TestImportMessages_Checkpoint 测试按断点跳过已导入的消息
+func TestImportMessages_Checkpoint(t *testing.T) {
+	var buf bytes.Buffer
+	writer, _ := NewMessageWriter(&buf, MessageFileFormatJSONLines)
+	for _, msg := range testExportMessages() {
+		_ = writer.Write(msg)
+	}
+
+	checkpointFile := filepath.Join(t.TempDir(), "import.checkpoint")
+	if checkpoint, err := loadImportCheckpoint(checkpointFile); err != nil || checkpoint.Position != 0 {
+		t.Fatalf("断点文件不存在时应返回空断点: %+v %v", checkpoint, err)
+	}
+	if err := saveImportCheckpoint(checkpointFile, 2); err != nil {
+		t.Fatalf("写入断点失败: %v", err)
+	}
+
+	// 全部消息已导入，不会发起任何网络请求
+	client := &Client{}
+	result, err := client.ImportMessages(t.Context(), &buf, MessageImportOptions{CheckpointFile: checkpointFile})
+	if err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if result.Skipped != 2 || result.Sent != 0 {
+		t.Errorf("导入结果不匹配: %+v", result)
+	}
+}
+
+// =============================================================================
+// 消息导出导入集成测试
+// =============================================================================
+
+// TestIntegration_ExportAndImportMessages 测试导出消息并回放到另一个 Topic
+func TestIntegration_ExportAndImportMessages(t *testing.T) {
+	skipIfNoRocketMQ(t)
+	client := getTestClient(t)
+	defer client.Close()
+
+	source, cleanupSource := createTestTopic(t, client, "EXPORT_SRC")
+	defer cleanupSource()
+	target, cleanupTarget := createTestTopic(t, client, "EXPORT_DST")
+	defer cleanupTarget()
+
+	ctx, cancel := testContext()
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.SendMessage(ctx, source, []byte("export"), &SendMessageOptions{Tags: "TagA"}); err != nil {
+			t.Fatalf("发送消息失败: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	exportResult, err := client.ExportMessages(ctx, source, &buf, MessageExportOptions{Format: MessageFileFormatBinary})
+	if err != nil {
+		t.Fatalf("导出消息失败: %v", err)
+	}
+	if exportResult.Total != 3 {
+		t.Errorf("导出条数应为 3, got %d", exportResult.Total)
+	}
+
+	importResult, err := client.ImportMessages(ctx, &buf, MessageImportOptions{
+		Format:         MessageFileFormatBinary,
+		TargetTopic:    target,
+		RateLimit:      100,
+		CheckpointFile: filepath.Join(t.TempDir(), "import.checkpoint"),
+	})
+	if err != nil {
+		t.Fatalf("导入消息失败: %v", err)
+	}
+	if importResult.Sent != exportResult.Total {
+		t.Errorf("导入条数应为 %d, got %d", exportResult.Total, importResult.Sent)
+	}
+}
@@ -1,7 +1,17 @@
 package admin
 
 import (
+	"encoding/hex"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
 )
 
 // =============================================================================
@@ -335,3 +345,140 @@ func TestIntegration_ExportPopRecords(t *testing.T) {
 			record.Topic, record.ConsumerGroup, record.QueueId)
 	}
 }
+
+// TestIntegration_QueryMessageByUniqKey 测试按唯一键查询消息
+func TestIntegration_QueryMessageByUniqKey(t *testing.T) {
+	skipIfNoRocketMQ(t)
+	client := getTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := testContext()
+	defer cancel()
+
+	// 获取一个 Topic
+	topicList, err := client.FetchAllTopicList(ctx)
+	if err != nil {
+		t.Fatalf("获取 Topic 列表失败: %v", err)
+	}
+
+	var testTopic string
+	for _, topic := range topicList.TopicList {
+		if len(topic) >= 4 && topic[:4] == "RMQ_" {
+			continue
+		}
+		testTopic = topic
+		break
+	}
+
+	if testTopic == "" {
+		t.Skip("没有可用的测试 Topic")
+	}
+
+	// 使用不存在的消息 ID 查询，应返回 ErrMessageNotFound
+	_, err = client.QueryMessageByUniqKey(ctx, testTopic, "7F00000100002A9F0000000000000001", 0, 0)
+	if err == nil {
+		t.Log("意外查询到消息")
+		return
+	}
+
+	if !errors.Is(err, ErrMessageNotFound) {
+		t.Logf("查询失败: %v", err)
+		return
+	}
+
+	t.Logf("按唯一键查询返回预期错误: %v", err)
+}
+
+// =============================================================================
+// 消息查询单元测试
+// =============================================================================
+
+// TestQueryMessageByUniqKey 测试按唯一键查询：唯一键索引、跨 Broker 去重、按存储时间排序与回退到普通 Key 查询
+func TestQueryMessageByUniqKey(t *testing.T) {
+	srvA, srvB := remotingtest.NewServer(), remotingtest.NewServer()
+	t.Cleanup(srvA.Close)
+	t.Cleanup(srvB.Close)
+	srvA.Handle(remoting.GetRouteInfoByTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"queueDatas":[],"brokerDatas":[` +
+			`{"cluster":"DefaultCluster","brokerName":"broker-a","brokerAddrs":{0:"` + srvA.Addr + `"}},` +
+			`{"cluster":"DefaultCluster","brokerName":"broker-b","brokerAddrs":{0:"` + srvB.Addr + `"}}]}`))
+	})
+
+	// 时间跨度为 0 的 UNIQ_KEY，推算的发送时间为本月初
+	uniqKey := strings.ToUpper(hex.EncodeToString([]byte{127, 0, 0, 1, 0x2A, 0x9F, 0, 0, 0, 1, 0, 0, 0, 0, 0, 1}))
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	storeIP := net.ParseIP("192.168.0.10")
+	message := func(key string, commitLogOffset, storeTimestamp int64) []byte {
+		return encodeTestMessage(&MessageExt{
+			Topic:           "TopicA",
+			CommitLogOffset: commitLogOffset,
+			StoreTimestamp:  storeTimestamp,
+			Properties:      map[string]string{PropertyUniqKey: key},
+		}, storeIP, 10911)
+	}
+
+	var uniqIndexMissing, brokerDown atomic.Bool
+	handleQuery := func(srv *remotingtest.Server, body []byte) {
+		srv.Handle(remoting.QueryMessage, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+			if brokerDown.Load() {
+				return remotingtest.Error(remoting.SystemError, "store error")
+			}
+			if req.ExtFields[uniqueKeyQueryFlag] == "true" && uniqIndexMissing.Load() {
+				return remotingtest.Error(remoting.QueryNotFound, "can not find message")
+			}
+			return remotingtest.Success(body)
+		})
+	}
+	// broker-a 返回哈希冲突的其他消息，broker-b 返回与 broker-a 相同存储位置的消息
+	handleQuery(srvA, append(append(message(uniqKey, 200, 2000), message(uniqKey, 100, 1000)...), message("OTHER", 300, 500)...))
+	handleQuery(srvB, message(uniqKey, 100, 1000))
+	client := newFakeClient(t, srvA)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	msgs, err := client.QueryMessageByUniqKey(ctx, "TopicA", uniqKey, 0, 0)
+	if err != nil {
+		t.Fatalf("按唯一键查询失败: %v", err)
+	}
+	if len(msgs) != 2 || msgs[0].CommitLogOffset != 100 || msgs[1].CommitLogOffset != 200 {
+		t.Fatalf("结果应去重并按存储时间排序: %+v", msgs)
+	}
+	if msgs[0].BrokerName != "broker-a" {
+		t.Errorf("应补全 BrokerName: %s", msgs[0].BrokerName)
+	}
+	for _, srv := range []*remotingtest.Server{srvA, srvB} {
+		w := srv.Requests(remoting.QueryMessage)[0].ExtFields
+		if w[uniqueKeyQueryFlag] != "true" || w["key"] != uniqKey || w["maxNum"] != "32" {
+			t.Errorf("唯一键查询请求头错误: %v", w)
+		}
+		if begin, _ := strconv.ParseInt(w["beginTimestamp"], 10, 64); begin != monthStart.UnixMilli()-1000 {
+			t.Errorf("起始时间应为推算的发送时间前 1 秒, got %d", begin)
+		}
+	}
+
+	// 唯一键索引未命中时按时间范围的普通 Key 查询
+	uniqIndexMissing.Store(true)
+	msgs, err = client.QueryMessageByUniqKey(ctx, "TopicA", uniqKey, 10, 20)
+	if err != nil || len(msgs) != 2 {
+		t.Fatalf("回退查询失败: %v %+v", err, msgs)
+	}
+	requests := srvA.Requests(remoting.QueryMessage)
+	if w := requests[len(requests)-1].ExtFields; w[uniqueKeyQueryFlag] != "false" || w["beginTimestamp"] != "10" || w["endTimestamp"] != "20" {
+		t.Errorf("回退查询请求头错误: %v", w)
+	}
+	if _, err := client.QueryMessageByUniqKey(ctx, "TopicA", "NOT-A-UNIQ-KEY", 0, 0); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("未找到消息应返回 ErrMessageNotFound, got %v", err)
+	}
+
+	// 所有 Broker 都失败时返回错误而不是未找到
+	brokerDown.Store(true)
+	_, err = client.QueryMessageByUniqKey(ctx, "TopicA", uniqKey, 0, 0)
+	var brokerErrs BrokerErrors
+	if !errors.As(err, &brokerErrs) || len(brokerErrs) != 2 || errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("所有 Broker 失败应返回 BrokerErrors, got %v", err)
+	}
+	if !IsResponseCode(err, remoting.SystemError) {
+		t.Errorf("应保留 Broker 返回的响应码: %v", err)
+	}
+}
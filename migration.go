@@ -0,0 +1,411 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// =============================================================================
+// 跨集群迁移
+// =============================================================================
+
+// MigrationOptions 迁移选项
+type MigrationOptions struct {
+	// Topics 迁移的 Topic；同时限定迁移消费进度的 Topic，为空时迁移消费组的全部 Topic
+	Topics []string
+
+	// Groups 迁移的订阅组及其消费进度
+	Groups []string
+
+	// TargetCluster 目标集群，为空时在目标的全部 Master 上创建
+	TargetCluster string
+
+	// OverwriteConfig 目标已存在不同的 Topic/订阅组配置时使用源配置覆盖
+	OverwriteConfig bool
+
+	// SkipOffsets 只迁移配置，不迁移消费进度
+	SkipOffsets bool
+
+	// Force 允许目标消费组有在线客户端，并允许回退目标已提交的 Offset
+	Force bool
+
+	// DryRun 只生成迁移报告，不修改目标集群
+	DryRun bool
+}
+
+// MappingAccuracy 消费进度映射方式
+type MappingAccuracy string
+
+const (
+	MappingExact    MappingAccuracy = "exact"     // 同名队列，按首条未消费消息的存储时间映射
+	MappingTopic    MappingAccuracy = "topic"     // 源集群无同名队列，按 Topic 最早的未消费时间映射
+	MappingCaughtUp MappingAccuracy = "caught-up" // 源队列已消费完，映射到目标最大 Offset
+	MappingNone     MappingAccuracy = "none"      // 源消费组没有可用的消费进度
+)
+
+// OffsetMapping 单个目标队列的消费进度映射
+type OffsetMapping struct {
+	Group           string          `json:"group"`
+	Target          MessageQueue    `json:"target"`           // 目标队列
+	Source          *MessageQueue   `json:"source,omitempty"` // 对应的源队列，按 Topic 映射时为空
+	SourceOffset    int64           `json:"sourceOffset"`     // 源消费 Offset
+	SourceStoreTime int64           `json:"sourceStoreTime"`  // 映射使用的存储时间（毫秒），已消费完时为 0
+	TargetOffset    int64           `json:"targetOffset"`     // 映射后的目标 Offset
+	PreviousOffset  int64           `json:"previousOffset"`   // 目标原 Offset，-1 表示未提交
+	Accuracy        MappingAccuracy `json:"accuracy"`         // 映射方式
+	DriftMs         int64           `json:"driftMs"`          // 目标 Offset 处消息存储时间与映射时间之差（毫秒）
+	Status          RestoreStatus   `json:"status"`           // 执行状态
+	Reason          string          `json:"reason,omitempty"` // 跳过原因或错误信息
+}
+
+// MigrationReport 迁移报告
+type MigrationReport struct {
+	DryRun  bool            `json:"dryRun"`
+	Configs []RestoreItem   `json:"configs"` // Topic 和订阅组配置
+	Offsets []OffsetMapping `json:"offsets"` // 消费进度
+}
+
+// Failed 返回失败的配置和队列数量
+func (r *MigrationReport) Failed() int {
+	n := 0
+	for _, item := range r.Configs {
+		if item.Status == RestoreFailed {
+			n++
+		}
+	}
+	for _, m := range r.Offsets {
+		if m.Status == RestoreFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// MigrateCluster 将 Topic、订阅组和消费进度从 source 集群迁移到 target 集群
+//
+// 配置在目标缺失时创建，已存在且相同时不做修改。消费进度按时间映射：取源队列
+// 首条未消费消息的存储时间，在目标同名队列上 SearchOffset；目标队列在源集群
+// 不存在时使用该 Topic 最早的未消费时间。目标已提交的 Offset 大于映射结果时
+// 默认不回退，因此重复执行是安全的。
+//
+// 刚创建的 Topic 需要等 Broker 注册路由后才能迁移消费进度，此时对应队列
+// 记录为失败，稍后重新执行即可。
+func MigrateCluster(ctx context.Context, source, target *Client, opts MigrationOptions) (*MigrationReport, error) {
+	report := &MigrationReport{DryRun: opts.DryRun}
+
+	sourceMasters, err := source.brokerMasterAddrs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取源集群信息失败: %w", err)
+	}
+
+	if err := migrateConfigs(ctx, source, target, sourceMasters, &opts, report); err != nil {
+		return report, err
+	}
+
+	if !opts.SkipOffsets {
+		for _, group := range opts.Groups {
+			if err := migrateGroupOffsets(ctx, source, target, sourceMasters, group, &opts, report); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	if n := report.Failed(); n > 0 {
+		return report, fmt.Errorf("迁移完成，%d 项失败", n)
+	}
+	return report, nil
+}
+
+// migrateConfigs 在目标集群创建缺失的 Topic 和订阅组
+func migrateConfigs(ctx context.Context, source, target *Client, sourceMasters map[string]string,
+	opts *MigrationOptions, report *MigrationReport) error {
+
+	if len(opts.Topics) == 0 && len(opts.Groups) == 0 {
+		return nil
+	}
+
+	clusterInfo, err := target.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("获取目标集群信息失败: %w", err)
+	}
+	targetBrokers, err := planBrokers(clusterInfo, opts.TargetCluster)
+	if err != nil {
+		return err
+	}
+
+	// 按 Broker 名排序读取，源配置取第一个包含该资源的 Master
+	var sourceTopics, targetTopics []map[string]*TopicConfig
+	var sourceGroups, targetGroups []map[string]*SubscriptionGroupConfig
+	for _, name := range sortedKeys(sourceMasters) {
+		if len(opts.Topics) > 0 {
+			topics, err := source.GetAllTopicConfig(ctx, sourceMasters[name])
+			if err != nil {
+				return fmt.Errorf("获取源 %s 的 Topic 配置失败: %w", name, err)
+			}
+			sourceTopics = append(sourceTopics, topics)
+		}
+		if len(opts.Groups) > 0 {
+			groups, err := source.GetAllSubscriptionGroup(ctx, sourceMasters[name])
+			if err != nil {
+				return fmt.Errorf("获取源 %s 的订阅组失败: %w", name, err)
+			}
+			sourceGroups = append(sourceGroups, groups)
+		}
+	}
+	targetNames := sortedKeys(targetBrokers)
+	for _, name := range targetNames {
+		if len(opts.Topics) > 0 {
+			topics, err := target.GetAllTopicConfig(ctx, targetBrokers[name])
+			if err != nil {
+				return fmt.Errorf("获取目标 %s 的 Topic 配置失败: %w", name, err)
+			}
+			targetTopics = append(targetTopics, topics)
+		}
+		if len(opts.Groups) > 0 {
+			groups, err := target.GetAllSubscriptionGroup(ctx, targetBrokers[name])
+			if err != nil {
+				return fmt.Errorf("获取目标 %s 的订阅组失败: %w", name, err)
+			}
+			targetGroups = append(targetGroups, groups)
+		}
+	}
+
+	// record 按目标现状确定状态并执行
+	record := func(kind ResourceKind, broker, name string, exists, differ bool, apply func() error) {
+		item := RestoreItem{Kind: kind, Broker: broker, Name: name}
+		switch {
+		case !exists:
+			item.Status = RestoreCreated
+		case !differ:
+			item.Status = RestoreUnchanged
+		case opts.OverwriteConfig:
+			item.Status = RestoreUpdated
+		default:
+			item.Status, item.Reason = RestoreSkipped, "目标已存在不同配置"
+		}
+		if (item.Status == RestoreCreated || item.Status == RestoreUpdated) && !opts.DryRun {
+			if err := apply(); err != nil {
+				item.Status, item.Reason = RestoreFailed, err.Error()
+			}
+		}
+		report.Configs = append(report.Configs, item)
+	}
+
+	for _, topic := range opts.Topics {
+		config := firstConfig(sourceTopics, topic)
+		if config == nil {
+			return fmt.Errorf("源集群中不存在 Topic %s: %w", topic, ErrTopicNotFound)
+		}
+		desired := *config
+		desired.TopicName = topic
+		for i, name := range targetNames {
+			addr := targetBrokers[name]
+			current, exists := targetTopics[i][topic]
+			record(ResourceTopic, name, topic, exists, exists && len(topicChanges(&desired, current)) > 0, func() error {
+				return target.CreateTopic(ctx, addr, desired)
+			})
+		}
+	}
+
+	for _, group := range opts.Groups {
+		config := firstConfig(sourceGroups, group)
+		if config == nil {
+			return fmt.Errorf("源集群中不存在订阅组 %s: %w", group, ErrConsumerGroupNotFound)
+		}
+		desired := *config
+		desired.GroupName = group
+		for i, name := range targetNames {
+			addr := targetBrokers[name]
+			current, exists := targetGroups[i][group]
+			record(ResourceGroup, name, group, exists, exists && len(groupChanges(&desired, current)) > 0, func() error {
+				return target.CreateSubscriptionGroup(ctx, addr, desired)
+			})
+		}
+	}
+	return nil
+}
+
+// firstConfig 返回第一个包含 name 的配置
+func firstConfig[T any](tables []map[string]*T, name string) *T {
+	for _, table := range tables {
+		if config, ok := table[name]; ok && config != nil {
+			return config
+		}
+	}
+	return nil
+}
+
+// sourceQueue 源队列的消费进度
+type sourceQueue struct {
+	offset    int64 // 消费 Offset
+	storeTime int64 // 首条未消费消息的存储时间，0 表示未知
+	caughtUp  bool  // 已消费完
+}
+
+// migrateGroupOffsets 按时间映射迁移单个消费组的消费进度
+func migrateGroupOffsets(ctx context.Context, source, target *Client, sourceMasters map[string]string,
+	group string, opts *MigrationOptions, report *MigrationReport) error {
+
+	// 目标客户端在线时会用自己的进度覆盖写入的 Offset
+	conn, err := target.ExamineConsumerConnectionInfo(ctx, group)
+	if err == nil && len(conn.ConnectionSet) > 0 && !opts.Force {
+		return fmt.Errorf("目标消费组 %s 有 %d 个在线客户端，请先停止消费或使用 Force", group, len(conn.ConnectionSet))
+	}
+	if err != nil && !errors.Is(err, ErrConsumerGroupNotFound) {
+		return fmt.Errorf("查询目标消费组 %s 失败: %w", group, err)
+	}
+
+	srcStats, err := source.ExamineConsumeStats(ctx, group)
+	if err != nil {
+		return fmt.Errorf("获取源消费组 %s 消费统计失败: %w", group, err)
+	}
+	tgtStats, err := target.ExamineConsumeStats(ctx, group)
+	if err != nil {
+		return fmt.Errorf("获取目标消费组 %s 消费统计失败: %w", group, err)
+	}
+	previous := make(map[MessageQueue]int64)
+	for key, offset := range tgtStats.OffsetTable {
+		if mq, err := ParseMessageQueueKey(key); err == nil {
+			previous[mq] = offset.ConsumerOffset
+		}
+	}
+
+	wanted := make(map[string]bool, len(opts.Topics))
+	for _, topic := range opts.Topics {
+		wanted[topic] = true
+	}
+
+	// 按 Topic 汇总源队列进度，重试和系统 Topic 的消息不会迁移，跳过
+	topics := make(map[string]map[MessageQueue]*sourceQueue)
+	for key, offset := range srcStats.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil || IsSystemTopic(mq.Topic) || (len(wanted) > 0 && !wanted[mq.Topic]) {
+			continue
+		}
+		q := &sourceQueue{offset: offset.ConsumerOffset, caughtUp: offset.ConsumerOffset >= offset.BrokerOffset}
+		if !q.caughtUp {
+			q.storeTime, err = source.firstUnconsumedStoreTime(ctx, sourceMasters[mq.BrokerName], mq, offset.ConsumerOffset)
+			if err != nil {
+				// 拉取失败时以最后消费消息的存储时间近似
+				q.storeTime = offset.LastTimestamp
+			}
+		}
+		if topics[mq.Topic] == nil {
+			topics[mq.Topic] = make(map[MessageQueue]*sourceQueue)
+		}
+		topics[mq.Topic][mq] = q
+	}
+
+	for _, topic := range sortedKeys(topics) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		report.Offsets = append(report.Offsets, target.mapTopicOffsets(ctx, group, topic, topics[topic], previous, opts)...)
+	}
+	return nil
+}
+
+// mapTopicOffsets 将 Topic 的源进度映射到目标各队列并写入
+func (c *Client) mapTopicOffsets(ctx context.Context, group, topic string, queues map[MessageQueue]*sourceQueue,
+	previous map[MessageQueue]int64, opts *MigrationOptions) []OffsetMapping {
+
+	// Topic 级别的回退时间取最早的未消费时间，宁可重复消费也不丢消息
+	var topicTime int64
+	allCaughtUp := true
+	for _, q := range queues {
+		if !q.caughtUp {
+			allCaughtUp = false
+			if q.storeTime > 0 && (topicTime == 0 || q.storeTime < topicTime) {
+				topicTime = q.storeTime
+			}
+		}
+	}
+
+	fail := func(reason string) []OffsetMapping {
+		return []OffsetMapping{{Group: group, Target: MessageQueue{Topic: topic}, PreviousOffset: -1,
+			Accuracy: MappingNone, Status: RestoreFailed, Reason: reason}}
+	}
+	route, err := c.ExamineTopicRouteInfo(ctx, topic)
+	if err != nil {
+		return fail(fmt.Sprintf("获取目标路由失败: %v", err))
+	}
+	stats, err := c.ExamineTopicStats(ctx, topic)
+	if err != nil {
+		return fail(fmt.Sprintf("获取目标 Topic 统计失败: %v", err))
+	}
+	masters := masterAddrs(route)
+
+	targetQueues := make([]MessageQueue, 0, len(stats.OffsetTable))
+	offsets := make(map[MessageQueue]*TopicOffset, len(stats.OffsetTable))
+	for key, offset := range stats.OffsetTable {
+		if mq, err := ParseMessageQueueKey(key); err == nil {
+			targetQueues = append(targetQueues, mq)
+			offsets[mq] = offset
+		}
+	}
+	sort.Slice(targetQueues, func(i, j int) bool { return queueLess(targetQueues[i], targetQueues[j]) })
+
+	var result []OffsetMapping
+	for _, mq := range targetQueues {
+		bounds := offsets[mq]
+		m := OffsetMapping{Group: group, Target: mq, PreviousOffset: -1}
+		if prev, ok := previous[mq]; ok {
+			m.PreviousOffset = prev
+		}
+
+		src, exists := queues[mq]
+		if exists {
+			srcQueue := mq
+			m.Source = &srcQueue
+			m.SourceOffset = src.offset
+		}
+		switch {
+		case exists && src.caughtUp, !exists && allCaughtUp && len(queues) > 0:
+			m.Accuracy, m.TargetOffset = MappingCaughtUp, bounds.MaxOffset
+		case exists && src.storeTime > 0:
+			m.Accuracy, m.SourceStoreTime = MappingExact, src.storeTime
+		case topicTime > 0:
+			m.Accuracy, m.SourceStoreTime = MappingTopic, topicTime
+		default:
+			m.Accuracy, m.Status, m.Reason = MappingNone, RestoreSkipped, "源消费组在该 Topic 上没有可用的消费进度"
+			result = append(result, m)
+			continue
+		}
+
+		addr := masters[mq.BrokerName]
+		if m.SourceStoreTime > 0 {
+			offset, err := c.SearchOffset(ctx, addr, topic, mq.QueueId, m.SourceStoreTime)
+			if err != nil {
+				m.Status, m.Reason = RestoreFailed, fmt.Sprintf("按时间查询目标 Offset 失败: %v", err)
+				result = append(result, m)
+				continue
+			}
+			m.TargetOffset = min(max(offset, bounds.MinOffset), bounds.MaxOffset)
+			if m.TargetOffset < bounds.MaxOffset {
+				if storeTime, err := c.firstUnconsumedStoreTime(ctx, addr, mq, m.TargetOffset); err == nil {
+					m.DriftMs = storeTime - m.SourceStoreTime
+				}
+			}
+		}
+
+		switch {
+		case m.PreviousOffset == m.TargetOffset:
+			m.Status = RestoreUnchanged
+		case m.PreviousOffset > m.TargetOffset && !opts.Force:
+			m.Status, m.Reason = RestoreSkipped, "目标已提交的 Offset 大于映射结果"
+		case m.PreviousOffset < 0:
+			m.Status = RestoreCreated
+		default:
+			m.Status = RestoreUpdated
+		}
+		if (m.Status == RestoreCreated || m.Status == RestoreUpdated) && !opts.DryRun {
+			if err := c.UpdateConsumeOffset(ctx, addr, group, topic, mq.QueueId, m.TargetOffset); err != nil {
+				m.Status, m.Reason = RestoreFailed, err.Error()
+			}
+		}
+		result = append(result, m)
+	}
+	return result
+}
@@ -0,0 +1,285 @@
+package admin
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// 跨集群迁移单元测试（本地模拟源集群和目标集群）
+// =============================================================================
+
+// handleStoreTimePull 注册拉取消息处理，offset 处消息的存储时间为 base + (offset-shift)*100
+func handleStoreTimePull(t *testing.T, srv *remotingtest.Server, base, shift int64) {
+	srv.Handle(remoting.PullMessage, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		queueId, _ := strconv.Atoi(req.ExtFields["queueId"])
+		offset, _ := strconv.ParseInt(req.ExtFields["queueOffset"], 10, 64)
+		body, err := encodeMessage(&MessageExt{
+			Topic:          req.ExtFields["topic"],
+			QueueId:        queueId,
+			QueueOffset:    offset,
+			Body:           []byte("m"),
+			StoreTimestamp: base + (offset-shift)*100,
+			BornHost:       "10.0.0.1:50000",
+			StoreHost:      "10.0.0.2:10911",
+		})
+		if err != nil {
+			t.Errorf("编码消息失败: %v", err)
+		}
+		return remotingtest.Success(body)
+	})
+}
+
+// consumeStatsBody 构造 RocketMQ 格式的消费统计，offsets 为 queueId -> {brokerOffset, consumerOffset}
+func consumeStatsBody(topic string, offsets map[int][2]int64) []byte {
+	queueIds := make([]int, 0, len(offsets))
+	for queueId := range offsets {
+		queueIds = append(queueIds, queueId)
+	}
+	sort.Ints(queueIds)
+
+	body := `{"consumeTps":0,"offsetTable":{`
+	for i, queueId := range queueIds {
+		if i > 0 {
+			body += ","
+		}
+		body += fmt.Sprintf(`{"brokerName":"broker-a","queueId":%d,"topic":"%s"}:{"brokerOffset":%d,"consumerOffset":%d,"lastTimestamp":1200}`,
+			queueId, topic, offsets[queueId][0], offsets[queueId][1])
+	}
+	return []byte(body + "}}")
+}
+
+// newMigrationSource 启动源集群：TopicA 两个队列，GroupA 在队列 0 有堆积、队列 1 已消费完
+func newMigrationSource(t *testing.T) *remotingtest.Server {
+	t.Helper()
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+	handleClusterInfo(srv, "broker-a")
+
+	srv.Handle(remoting.GetAllTopicConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"topicConfigTable": map[string]any{
+			"TopicA": map[string]any{"topicName": "TopicA", "readQueueNums": 2, "writeQueueNums": 2, "perm": 6},
+		}})
+	})
+	srv.Handle(remoting.GetAllSubscriptionGroup, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"subscriptionGroupTable": map[string]any{
+			"GroupA": map[string]any{"groupName": "GroupA", "consumeEnable": true, "retryQueueNums": 1, "retryMaxTimes": 16},
+		}})
+	})
+	srv.Handle(remoting.GetConsumeStats, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success(consumeStatsBody("TopicA", map[int][2]int64{0: {10, 4}, 1: {5, 5}}))
+	})
+	// 源集群 offset 处消息的存储时间为 1000 + offset*100
+	handleStoreTimePull(t, srv, 1000, 0)
+	return srv
+}
+
+// migrationTarget 目标集群状态
+type migrationTarget struct {
+	*remotingtest.Server
+	mu      sync.Mutex
+	topics  map[string]map[string]any
+	groups  map[string]map[string]any
+	offsets map[int][2]int64
+}
+
+// newMigrationTarget 启动空的目标集群：TopicA 路由有三个队列，同一时间点的 Offset 比源集群大 2
+func newMigrationTarget(t *testing.T) *migrationTarget {
+	t.Helper()
+	srv := &migrationTarget{
+		Server:  remotingtest.NewServer(),
+		topics:  make(map[string]map[string]any),
+		groups:  make(map[string]map[string]any),
+		offsets: make(map[int][2]int64),
+	}
+	t.Cleanup(srv.Close)
+	handleClusterInfo(srv.Server, "broker-a")
+	maxOffsets := map[int]int64{0: 20, 1: 8, 2: 6}
+
+	srv.Handle(remoting.GetAllTopicConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		return remotingtest.JSON(map[string]any{"topicConfigTable": srv.topics})
+	})
+	srv.Handle(remoting.UpdateAndCreateTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		srv.topics[req.ExtFields["topic"]] = map[string]any{"topicName": req.ExtFields["topic"],
+			"readQueueNums": atoi(req.ExtFields["readQueueNums"]), "writeQueueNums": atoi(req.ExtFields["writeQueueNums"]),
+			"perm": atoi(req.ExtFields["perm"]), "order": req.ExtFields["order"] == "true"}
+		return remotingtest.Success(nil)
+	})
+	srv.Handle(remoting.GetAllSubscriptionGroup, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		return remotingtest.JSON(map[string]any{"subscriptionGroupTable": srv.groups})
+	})
+	srv.Handle(remoting.UpdateAndCreateSubscriptionGroup, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		srv.groups[req.ExtFields["groupName"]] = map[string]any{"groupName": req.ExtFields["groupName"],
+			"consumeEnable": req.ExtFields["consumeEnable"] == "true", "retryQueueNums": atoi(req.ExtFields["retryQueueNums"]),
+			"retryMaxTimes": atoi(req.ExtFields["retryMaxTimes"])}
+		return remotingtest.Success(nil)
+	})
+	srv.Handle(remoting.GetConsumerConnectionList, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Error(remoting.ConsumerNotOnline, "the consumer group not online")
+	})
+	srv.Handle(remoting.GetConsumeStats, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		return remotingtest.Success(consumeStatsBody("TopicA", srv.offsets))
+	})
+	srv.Handle(remoting.UpdateConsumeOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		queueId := atoi(req.ExtFields["queueId"])
+		offset, _ := strconv.ParseInt(req.ExtFields["commitOffset"], 10, 64)
+		srv.offsets[queueId] = [2]int64{maxOffsets[queueId], offset}
+		return remotingtest.Success(nil)
+	})
+	srv.Handle(remoting.GetRouteInfoByTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"queueDatas":[{"brokerName":"broker-a","readQueueNums":3,"writeQueueNums":3,"perm":6}],` +
+			`"brokerDatas":[{"cluster":"DefaultCluster","brokerName":"broker-a","brokerAddrs":{0:"` + srv.Addr + `"}}]}`))
+	})
+	srv.Handle(remoting.GetTopicStatsInfo, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		body := `{"offsetTable":{`
+		for queueId := 0; queueId < 3; queueId++ {
+			if queueId > 0 {
+				body += ","
+			}
+			body += fmt.Sprintf(`{"brokerName":"broker-a","queueId":%d,"topic":"TopicA"}:{"minOffset":0,"maxOffset":%d,"lastUpdateTimestamp":0}`,
+				queueId, maxOffsets[queueId])
+		}
+		return remotingtest.Success([]byte(body + "}}"))
+	})
+	srv.Handle(remoting.SearchOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		timestamp, _ := strconv.ParseInt(req.ExtFields["timestamp"], 10, 64)
+		resp := remotingtest.Success(nil)
+		resp.ExtFields = map[string]string{"offset": strconv.FormatInt((timestamp-1000)/100+2, 10)}
+		return resp
+	})
+	// 目标集群消息比源集群晚 30ms 存储
+	handleStoreTimePull(t, srv.Server, 1030, 2)
+	return srv
+}
+
+// TestMigrateCluster 测试迁移配置和按时间映射消费进度
+func TestMigrateCluster(t *testing.T) {
+	source := newFakeClient(t, newMigrationSource(t))
+	targetSrv := newMigrationTarget(t)
+	target := newFakeClient(t, targetSrv.Server)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	opts := MigrationOptions{Topics: []string{"TopicA"}, Groups: []string{"GroupA"}, DryRun: true}
+
+	// 预演：目标为空，配置在预演中不会创建，路由仍可查询
+	report, err := MigrateCluster(ctx, source, target, opts)
+	if err != nil {
+		t.Fatalf("预演迁移失败: %v", err)
+	}
+	if len(report.Configs) != 2 || report.Configs[0].Status != RestoreCreated || report.Configs[1].Status != RestoreCreated {
+		t.Fatalf("预演应创建 Topic 和订阅组: %+v", report.Configs)
+	}
+	if n := len(targetSrv.Requests(remoting.UpdateConsumeOffset)) + len(targetSrv.Requests(remoting.UpdateAndCreateTopic)); n != 0 {
+		t.Fatalf("预演不应修改目标集群, got %d 次写入", n)
+	}
+
+	want := []struct {
+		queueId  int
+		accuracy MappingAccuracy
+		offset   int64
+		drift    int64
+	}{
+		{0, MappingExact, 6, 30},   // 源 offset 4 存储于 1400，目标对应 offset 6
+		{1, MappingCaughtUp, 8, 0}, // 源已消费完，目标取最大 Offset
+		{2, MappingTopic, 6, 0},    // 源无同名队列，按 Topic 最早未消费时间映射后截断到最大 Offset
+	}
+	if len(report.Offsets) != len(want) {
+		t.Fatalf("应映射 %d 个队列, got %+v", len(want), report.Offsets)
+	}
+	for i, w := range want {
+		m := report.Offsets[i]
+		if m.Target.QueueId != w.queueId || m.Accuracy != w.accuracy || m.TargetOffset != w.offset || m.DriftMs != w.drift {
+			t.Errorf("队列 %d 映射错误: %+v", w.queueId, m)
+		}
+		if m.Status != RestoreCreated || m.PreviousOffset != -1 {
+			t.Errorf("队列 %d 应为新提交: %+v", w.queueId, m)
+		}
+	}
+	if report.Offsets[2].Source != nil || report.Offsets[0].Source == nil || report.Offsets[0].SourceStoreTime != 1400 {
+		t.Errorf("源队列信息错误: %+v %+v", report.Offsets[0], report.Offsets[2])
+	}
+
+	// 执行迁移
+	opts.DryRun = false
+	if _, err := MigrateCluster(ctx, source, target, opts); err != nil {
+		t.Fatalf("迁移失败: %v", err)
+	}
+	targetSrv.mu.Lock()
+	if targetSrv.offsets[0][1] != 6 || targetSrv.offsets[1][1] != 8 || targetSrv.offsets[2][1] != 6 {
+		t.Errorf("目标消费进度错误: %v", targetSrv.offsets)
+	}
+	if targetSrv.topics["TopicA"] == nil || targetSrv.groups["GroupA"] == nil {
+		t.Errorf("目标应创建 Topic 和订阅组")
+	}
+	targetSrv.mu.Unlock()
+
+	// 重复执行不做任何修改
+	writes := len(targetSrv.Requests(remoting.UpdateConsumeOffset))
+	report, err = MigrateCluster(ctx, source, target, opts)
+	if err != nil {
+		t.Fatalf("重复迁移失败: %v", err)
+	}
+	for _, item := range report.Configs {
+		if item.Status != RestoreUnchanged {
+			t.Errorf("重复迁移配置应不变: %+v", item)
+		}
+	}
+	for _, m := range report.Offsets {
+		if m.Status != RestoreUnchanged {
+			t.Errorf("重复迁移消费进度应不变: %+v", m)
+		}
+	}
+	if n := len(targetSrv.Requests(remoting.UpdateConsumeOffset)); n != writes {
+		t.Errorf("重复迁移不应提交 Offset, got %d 次", n-writes)
+	}
+
+	// 目标进度领先时默认不回退
+	targetSrv.mu.Lock()
+	targetSrv.offsets[0] = [2]int64{20, 15}
+	targetSrv.mu.Unlock()
+	report, err = MigrateCluster(ctx, source, target, MigrationOptions{Groups: []string{"GroupA"}})
+	if err != nil {
+		t.Fatalf("迁移失败: %v", err)
+	}
+	if m := report.Offsets[0]; m.Status != RestoreSkipped || m.PreviousOffset != 15 {
+		t.Errorf("目标进度领先时应跳过: %+v", m)
+	}
+}
+
+// TestMigrateCluster_ConsumerOnline 测试目标消费组在线时拒绝迁移消费进度
+func TestMigrateCluster_ConsumerOnline(t *testing.T) {
+	source := newFakeClient(t, newMigrationSource(t))
+	targetSrv := newMigrationTarget(t)
+	targetSrv.Handle(remoting.GetConsumerConnectionList, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"connectionSet": []map[string]any{{"clientId": "10.0.0.3@1"}}})
+	})
+	target := newFakeClient(t, targetSrv.Server)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	if _, err := MigrateCluster(ctx, source, target, MigrationOptions{Groups: []string{"GroupA"}}); err == nil {
+		t.Fatal("目标消费组在线时应拒绝迁移")
+	}
+	if n := len(targetSrv.Requests(remoting.UpdateConsumeOffset)); n != 0 {
+		t.Errorf("拒绝迁移时不应提交 Offset, got %d 次", n)
+	}
+}
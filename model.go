@@ -1,5 +1,12 @@
 package admin
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // =============================================================================
 // 集群相关模型
 // =============================================================================
@@ -30,6 +37,18 @@ type KVTable struct {
 	Table map[string]string `json:"table"`
 }
 
+// DataVersion Broker 配置表的数据版本，配置每次变更时 Counter 递增
+type DataVersion struct {
+	// Timestamp 最后变更时间
+	Timestamp int64 `json:"timestamp"`
+
+	// Counter 变更计数
+	Counter int64 `json:"counter"`
+
+	// StateVersion 状态机版本 (5.x)
+	StateVersion int64 `json:"stateVersion"`
+}
+
 // =============================================================================
 // Topic 相关模型
 // =============================================================================
@@ -46,7 +65,7 @@ type TopicConfig struct {
 	WriteQueueNums int `json:"writeQueueNums"`
 
 	// Perm 权限
-	Perm int `json:"perm"`
+	Perm Perm `json:"perm"`
 
 	// TopicFilterType Topic 过滤类型
 	TopicFilterType string `json:"topicFilterType"`
@@ -56,6 +75,12 @@ type TopicConfig struct {
 
 	// Order 是否顺序消息
 	Order bool `json:"order"`
+
+	// Attributes Topic 属性（5.x），如 message.type、queue.type，见 WithMessageType
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// Extra Broker 返回而本库未定义的字段，读取时保存，不随 UPDATE_AND_CREATE_TOPIC 请求头发送
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // TopicList Topic 列表
@@ -91,7 +116,7 @@ type QueueData struct {
 	WriteQueueNums int `json:"writeQueueNums"`
 
 	// Perm 权限
-	Perm int `json:"perm"`
+	Perm Perm `json:"perm"`
 
 	// TopicSysFlag Topic 系统标志
 	TopicSysFlag int `json:"topicSysFlag"`
@@ -147,6 +172,24 @@ type SubscriptionGroupConfig struct {
 
 	// NotifyConsumerIdsChangedEnable 是否通知消费者 ID 变更
 	NotifyConsumerIdsChangedEnable bool `json:"notifyConsumerIdsChangedEnable"`
+
+	// ConsumeMessageOrderly 是否顺序消费（5.x Proxy 使用）
+	ConsumeMessageOrderly bool `json:"consumeMessageOrderly,omitempty"`
+
+	// GroupRetryPolicy 重试策略（5.x），见 NewExponentialRetryPolicy、NewCustomizedRetryPolicy
+	GroupRetryPolicy *GroupRetryPolicy `json:"groupRetryPolicy,omitempty"`
+
+	// GroupSysFlag 订阅组系统标志（5.x）
+	GroupSysFlag int `json:"groupSysFlag,omitempty"`
+
+	// ConsumeTimeoutMinute 消费超时时间（分钟，5.x），为 0 时使用 Broker 默认值
+	ConsumeTimeoutMinute int `json:"consumeTimeoutMinute,omitempty"`
+
+	// Attributes 订阅组属性（5.x）
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// Extra Broker 返回而本库未定义的字段，如 subscriptionDataSet，更新时原样保留
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // ConsumeStats 消费统计
@@ -173,6 +216,29 @@ type OffsetWrapper struct {
 	PullOffset int64 `json:"pullOffset"`
 }
 
+// QueueOffsetResult 单个队列的 Offset 修改结果
+type QueueOffsetResult struct {
+	// MessageQueue 消息队列
+	MessageQueue MessageQueue `json:"messageQueue"`
+
+	// OldOffset 修改前的消费 Offset，-1 表示未提交
+	OldOffset int64 `json:"oldOffset"`
+
+	// NewOffset 修改后的消费 Offset
+	NewOffset int64 `json:"newOffset"`
+
+	// Error 修改失败时的错误信息
+	Error string `json:"error,omitempty"`
+}
+
+// Skipped 返回按 Offset 差值计算的跳过消息数，回退或原 Offset 未提交时为 0
+func (r *QueueOffsetResult) Skipped() int64 {
+	if r.OldOffset < 0 || r.NewOffset <= r.OldOffset {
+		return 0
+	}
+	return r.NewOffset - r.OldOffset
+}
+
 // ConsumerConnection 消费者连接
 type ConsumerConnection struct {
 	// ConnectionSet 连接集合
@@ -250,3 +316,81 @@ type MessageQueue struct {
 func (mq *MessageQueue) String() string {
 	return mq.Topic + "-" + mq.BrokerName + "-" + string(rune(mq.QueueId))
 }
+
+// queueLess 队列按 Topic、Broker 名、队列 ID 排序
+func queueLess(a, b MessageQueue) bool {
+	if a.Topic != b.Topic {
+		return a.Topic < b.Topic
+	}
+	if a.BrokerName != b.BrokerName {
+		return a.BrokerName < b.BrokerName
+	}
+	return a.QueueId < b.QueueId
+}
+
+// ParseMessageQueueKey 解析统计表中的队列 key
+// 支持以下格式:
+//   - JSON 对象: {"brokerName":"broker-a","queueId":0,"topic":"TopicTest"}
+//   - 非标准对象: {topic:TopicTest,brokerName:broker-a,queueId:0}
+//   - 分隔格式: TopicTest@broker-a@0
+//   - Java toString: MessageQueue [topic=TopicTest, brokerName=broker-a, queueId=0]
+func ParseMessageQueueKey(key string) (MessageQueue, error) {
+	var mq MessageQueue
+	key = strings.TrimSpace(key)
+
+	switch {
+	case strings.HasPrefix(key, "{"):
+		if err := json.Unmarshal([]byte(key), &mq); err == nil {
+			return mq, nil
+		}
+		return parseMessageQueueFields(key, strings.TrimSuffix(strings.TrimPrefix(key, "{"), "}"), ":")
+
+	case strings.HasPrefix(key, "MessageQueue ["):
+		return parseMessageQueueFields(key, strings.TrimSuffix(strings.TrimPrefix(key, "MessageQueue ["), "]"), "=")
+
+	default:
+		// Topic 名称中不含 '@'，brokerName 取首尾分隔符之间的部分
+		last := strings.LastIndex(key, "@")
+		first := strings.Index(key, "@")
+		if first < 0 || first == last {
+			return mq, fmt.Errorf("解析队列 key 失败: %s", key)
+		}
+		id, err := strconv.Atoi(key[last+1:])
+		if err != nil {
+			return mq, fmt.Errorf("解析队列 key 失败: %s", key)
+		}
+		mq.Topic = key[:first]
+		mq.BrokerName = key[first+1 : last]
+		mq.QueueId = id
+		return mq, nil
+	}
+}
+
+// parseMessageQueueFields 解析 "topic<sep>x, brokerName<sep>y, queueId<sep>0" 形式的字段列表
+func parseMessageQueueFields(key, body, sep string) (MessageQueue, error) {
+	var mq MessageQueue
+	for _, part := range strings.Split(body, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), sep, 2)
+		if len(kv) != 2 {
+			continue
+		}
+		name := strings.Trim(kv[0], `" `)
+		value := strings.Trim(kv[1], `" `)
+		switch name {
+		case "topic":
+			mq.Topic = value
+		case "brokerName":
+			mq.BrokerName = value
+		case "queueId":
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				return mq, fmt.Errorf("解析队列 key 失败: %s", key)
+			}
+			mq.QueueId = id
+		}
+	}
+	if mq.Topic == "" || mq.BrokerName == "" {
+		return mq, fmt.Errorf("解析队列 key 失败: %s", key)
+	}
+	return mq, nil
+}
@@ -1,5 +1,7 @@
 package admin
 
+import "strings"
+
 // =============================================================================
 // ACL 用户管理
 // =============================================================================
@@ -177,4 +179,19 @@ type MessageExt struct {
 	SysFlag        int               `json:"sysFlag"`        // 系统标志
 	BrokerName     string            `json:"brokerName"`     // Broker 名称
 	Properties     map[string]string `json:"properties"`     // 属性
+
+	BodyCRC                   int32 `json:"bodyCRC"`                   // 消息体 CRC
+	CommitLogOffset           int64 `json:"commitLogOffset"`           // CommitLog 物理偏移
+	ReconsumeTimes            int   `json:"reconsumeTimes"`            // 重新消费次数
+	PreparedTransactionOffset int64 `json:"preparedTransactionOffset"` // 事务预提交偏移
+}
+
+// GetTags 返回消息 Tag
+func (m *MessageExt) GetTags() string {
+	return m.Properties[PropertyTags]
+}
+
+// GetKeys 返回消息 Key 列表
+func (m *MessageExt) GetKeys() []string {
+	return strings.Fields(m.Properties[PropertyKeys])
 }
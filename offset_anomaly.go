@@ -0,0 +1,231 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// =============================================================================
+// 消费进度异常检查
+// =============================================================================
+
+// OffsetAnomalyKind 消费进度异常类型
+type OffsetAnomalyKind string
+
+const (
+	OffsetAboveMax    OffsetAnomalyKind = "above-max"    // 已提交 Offset 大于队列最大 Offset，通常由 Topic 重建导致，新消息会被跳过
+	OffsetBelowMin    OffsetAnomalyKind = "below-min"    // 已提交 Offset 小于队列最小 Offset，消息已过期删除
+	OffsetUncommitted OffsetAnomalyKind = "uncommitted"  // 消费组在 Topic 上有进度，但该队列没有已提交的 Offset
+	OffsetStaleBroker OffsetAnomalyKind = "stale-broker" // 进度所在的 Broker 或队列已不在 Topic 路由中
+)
+
+// OffsetAnomaly 单个队列的消费进度异常
+type OffsetAnomaly struct {
+	Group           string            `json:"group"`
+	MessageQueue    MessageQueue      `json:"messageQueue"`
+	Kind            OffsetAnomalyKind `json:"kind"`
+	CommittedOffset int64             `json:"committedOffset"` // 已提交的 Offset，-1 表示未提交
+	MinOffset       int64             `json:"minOffset"`
+	MaxOffset       int64             `json:"maxOffset"`
+	SuggestedOffset int64             `json:"suggestedOffset"` // 建议写入的 Offset，-1 表示需要人工处理
+	Suggestion      string            `json:"suggestion"`      // 修复建议
+	Fixed           bool              `json:"fixed"`           // 是否已按建议修复
+	Error           string            `json:"error,omitempty"` // 修复失败的原因
+}
+
+// OffsetAnomalyOptions 消费进度异常检查选项
+type OffsetAnomalyOptions struct {
+	// Groups 只检查指定消费组，为空时检查全部非系统消费组
+	Groups []string
+
+	// Fix 按建议写入 Offset 修复异常，消费组在线时同时通知客户端
+	Fix bool
+}
+
+// OffsetAnomalyReport 消费进度异常检查报告
+type OffsetAnomalyReport struct {
+	Groups    int             `json:"groups"` // 检查的消费组数量
+	Anomalies []OffsetAnomaly `json:"anomalies"`
+}
+
+// Count 返回指定类型的异常数量
+func (r *OffsetAnomalyReport) Count(kind OffsetAnomalyKind) int {
+	n := 0
+	for _, a := range r.Anomalies {
+		if a.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// topicOffsetState Topic 的路由与队列范围，检查多个消费组时复用
+type topicOffsetState struct {
+	masters map[string]string // 路由中的 brokerName -> Master 地址
+	ranges  map[MessageQueue]*TopicOffset
+	err     error
+}
+
+// ScanOffsetAnomalies 对比消费组的已提交 Offset 与队列的 [min, max] 范围，检查异常的消费进度
+//
+// 进度数据来自 ExamineConsumeStats，队列范围来自 ExamineTopicStats，只检查消费组已有进度的
+// Topic（含重试 Topic）。越界的 Offset 建议修正到最近的边界，未提交的队列建议从最小
+// Offset 开始以免丢消息；不在路由中的 Broker 无法自动修复，需要人工确认后清理。
+// 单个消费组检查失败不影响其他消费组，错误汇总后返回。
+func (c *Client) ScanOffsetAnomalies(ctx context.Context, opts OffsetAnomalyOptions) (*OffsetAnomalyReport, error) {
+	groups := opts.Groups
+	if len(groups) == 0 {
+		var err error
+		if groups, err = c.ListConsumerGroups(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &OffsetAnomalyReport{}
+	topics := make(map[string]*topicOffsetState)
+	var errs []error
+	for _, group := range groups {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		anomalies, err := c.scanGroupOffsets(ctx, group, topics)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", group, err))
+			continue
+		}
+		report.Groups++
+
+		if opts.Fix && len(anomalies) > 0 {
+			c.fixOffsetAnomalies(ctx, group, anomalies, topics)
+		}
+		report.Anomalies = append(report.Anomalies, anomalies...)
+	}
+
+	if len(errs) > 0 {
+		return report, fmt.Errorf("检查消费进度失败: %w", errors.Join(errs...))
+	}
+	return report, nil
+}
+
+// scanGroupOffsets 检查单个消费组的消费进度
+func (c *Client) scanGroupOffsets(ctx context.Context, group string, topics map[string]*topicOffsetState) ([]OffsetAnomaly, error) {
+	stats, err := c.ExamineConsumeStats(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	committed := make(map[string]map[MessageQueue]int64)
+	for key, wrapper := range stats.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if committed[mq.Topic] == nil {
+			committed[mq.Topic] = make(map[MessageQueue]int64)
+		}
+		committed[mq.Topic][mq] = wrapper.ConsumerOffset
+	}
+
+	var anomalies []OffsetAnomaly
+	for _, topic := range sortedKeys(committed) {
+		state := c.topicOffsetState(ctx, topic, topics)
+		if state.err != nil && !errors.Is(state.err, ErrTopicNotFound) {
+			return nil, fmt.Errorf("读取 Topic %s 队列信息失败: %w", topic, state.err)
+		}
+
+		offsets := committed[topic]
+		for mq, offset := range offsets {
+			a := OffsetAnomaly{Group: group, MessageQueue: mq, CommittedOffset: max(offset, -1), SuggestedOffset: -1}
+			r, ok := state.ranges[mq]
+			if _, routed := state.masters[mq.BrokerName]; !routed || !ok {
+				a.Kind = OffsetStaleBroker
+				a.Suggestion = "Broker 或队列已不在 Topic 路由中，确认 Topic 迁移完成后清理该消费进度"
+				anomalies = append(anomalies, a)
+				continue
+			}
+			a.MinOffset, a.MaxOffset = r.MinOffset, r.MaxOffset
+			switch {
+			case offset < 0:
+				a.Kind, a.SuggestedOffset = OffsetUncommitted, r.MinOffset
+				a.Suggestion = "提交最小 Offset，避免客户端按 consumeFromWhere 从最新位置开始而丢失消息"
+			case offset > r.MaxOffset:
+				a.Kind, a.SuggestedOffset = OffsetAboveMax, r.MaxOffset
+				a.Suggestion = "修正为最大 Offset，从当前最新消息继续消费"
+			case offset < r.MinOffset:
+				a.Kind, a.SuggestedOffset = OffsetBelowMin, r.MinOffset
+				a.Suggestion = "修正为最小 Offset，已过期的消息无法再消费"
+			default:
+				continue
+			}
+			anomalies = append(anomalies, a)
+		}
+
+		// 消费统计中缺失的路由队列视为未提交
+		for mq, r := range state.ranges {
+			if _, ok := offsets[mq]; ok {
+				continue
+			}
+			anomalies = append(anomalies, OffsetAnomaly{
+				Group: group, MessageQueue: mq, Kind: OffsetUncommitted, CommittedOffset: -1,
+				MinOffset: r.MinOffset, MaxOffset: r.MaxOffset, SuggestedOffset: r.MinOffset,
+				Suggestion: "提交最小 Offset，避免客户端按 consumeFromWhere 从最新位置开始而丢失消息",
+			})
+		}
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool { return queueLess(anomalies[i].MessageQueue, anomalies[j].MessageQueue) })
+	return anomalies, nil
+}
+
+// topicOffsetState 读取并缓存 Topic 的路由与队列范围，Topic 不存在时 ranges 为空
+func (c *Client) topicOffsetState(ctx context.Context, topic string, cache map[string]*topicOffsetState) *topicOffsetState {
+	if state, ok := cache[topic]; ok {
+		return state
+	}
+	state := &topicOffsetState{}
+	cache[topic] = state
+
+	routeData, err := c.ExamineTopicRouteInfo(ctx, topic)
+	if err != nil {
+		state.err = err
+		return state
+	}
+	state.masters = masterAddrs(routeData)
+	stats, err := c.ExamineTopicStats(ctx, topic)
+	if err != nil {
+		state.err = err
+		return state
+	}
+	state.ranges = make(map[MessageQueue]*TopicOffset, len(stats.OffsetTable))
+	for key, offset := range stats.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil {
+			state.err = err
+			return state
+		}
+		state.ranges[mq] = offset
+	}
+	return state
+}
+
+// fixOffsetAnomalies 按建议写入 Offset，结果记录在各异常项中
+func (c *Client) fixOffsetAnomalies(ctx context.Context, group string, anomalies []OffsetAnomaly, topics map[string]*topicOffsetState) {
+	online, err := c.groupOnline(ctx, group)
+	for i := range anomalies {
+		a := &anomalies[i]
+		if a.SuggestedOffset < 0 {
+			continue
+		}
+		if err != nil {
+			a.Error = err.Error()
+			continue
+		}
+		addr := topics[a.MessageQueue.Topic].masters[a.MessageQueue.BrokerName]
+		if err := c.commitQueueOffset(ctx, addr, group, a.MessageQueue, a.SuggestedOffset, online); err != nil {
+			a.Error = err.Error()
+			continue
+		}
+		a.Fixed = true
+	}
+}
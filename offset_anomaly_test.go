@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// 消费进度异常检查单元测试
+// =============================================================================
+
+// TestScanOffsetAnomalies 测试检查并修复越界、未提交与不在路由中的消费进度
+func TestScanOffsetAnomalies(t *testing.T) {
+	srv := newResetCluster(t)
+	client := newFakeClient(t, srv.Server)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	srv.mu.Lock()
+	srv.offsets[0], srv.offsets[1] = 1, 50
+	srv.mu.Unlock()
+	// broker-b 已从 TopicA 的路由中移除，但仍保留消费进度
+	srv.Handle(remoting.GetConsumeStats, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		entries := []string{`{"brokerName":"broker-b","queueId":0,"topic":"TopicA"}:{"brokerOffset":8,"consumerOffset":8}`}
+		for queueId, offset := range srv.offsets {
+			entries = append(entries, fmt.Sprintf(`{"brokerName":"broker-a","queueId":%d,"topic":"TopicA"}:{"brokerOffset":20,"consumerOffset":%d}`, queueId, offset))
+		}
+		return remotingtest.Success([]byte(`{"offsetTable":{` + strings.Join(entries, ",") + `}}`))
+	})
+
+	want := []struct {
+		kind      OffsetAnomalyKind
+		suggested int64
+	}{
+		{OffsetBelowMin, 2}, {OffsetAboveMax, 10}, {OffsetUncommitted, 0}, {OffsetStaleBroker, -1},
+	}
+	report, err := client.ScanOffsetAnomalies(ctx, OffsetAnomalyOptions{Groups: []string{"GroupA"}})
+	if err != nil {
+		t.Fatalf("检查消费进度失败: %v", err)
+	}
+	if report.Groups != 1 || len(report.Anomalies) != len(want) {
+		t.Fatalf("应发现 %d 个异常, got %+v", len(want), report.Anomalies)
+	}
+	for i, w := range want {
+		if a := report.Anomalies[i]; a.Kind != w.kind || a.SuggestedOffset != w.suggested || a.Fixed {
+			t.Errorf("第 %d 个异常应为 %s/%d, got %+v", i, w.kind, w.suggested, a)
+		}
+	}
+	if n := len(srv.Requests(remoting.UpdateConsumeOffset)); n != 0 {
+		t.Errorf("未指定 Fix 时不应修改 Offset, got %d 次", n)
+	}
+
+	report, err = client.ScanOffsetAnomalies(ctx, OffsetAnomalyOptions{Groups: []string{"GroupA"}, Fix: true})
+	if err != nil {
+		t.Fatalf("修复消费进度失败: %v", err)
+	}
+	if !report.Anomalies[0].Fixed || report.Anomalies[3].Fixed {
+		t.Errorf("只应修复有建议 Offset 的异常: %+v", report.Anomalies)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.offsets[0] != 2 || srv.offsets[1] != 10 || srv.offsets[2] != 0 {
+		t.Errorf("修复后的 Offset 错误: %v", srv.offsets)
+	}
+}
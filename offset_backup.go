@@ -0,0 +1,231 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// 消费进度备份与恢复
+// =============================================================================
+
+// OffsetBackupVersion 当前消费进度备份格式版本
+const OffsetBackupVersion = 1
+
+// QueueOffsetBackup 单个队列的消费进度
+type QueueOffsetBackup struct {
+	Topic      string `json:"topic"`
+	BrokerName string `json:"brokerName"`
+	QueueId    int    `json:"queueId"`
+	Offset     int64  `json:"offset"`     // 已提交的 Offset
+	CapturedAt int64  `json:"capturedAt"` // 读取时间（毫秒）
+}
+
+// MessageQueue 返回备份对应的消息队列
+func (q *QueueOffsetBackup) MessageQueue() MessageQueue {
+	return MessageQueue{Topic: q.Topic, BrokerName: q.BrokerName, QueueId: q.QueueId}
+}
+
+// OffsetBackup 消费组的消费进度备份
+type OffsetBackup struct {
+	Version    int                 `json:"version"`
+	Group      string              `json:"group"`
+	CapturedAt time.Time           `json:"capturedAt"`
+	Queues     []QueueOffsetBackup `json:"queues"`
+}
+
+// BackupConsumerOffsets 备份消费组在全部 Topic（含重试 Topic）上已提交的 Offset
+func (c *Client) BackupConsumerOffsets(ctx context.Context, group string) (*OffsetBackup, error) {
+	stats, err := c.ExamineConsumeStats(ctx, group)
+	if err != nil {
+		return nil, fmt.Errorf("获取消费组 %s 消费统计失败: %w", group, err)
+	}
+
+	now := time.Now()
+	backup := &OffsetBackup{Version: OffsetBackupVersion, Group: group, CapturedAt: now}
+	for key, wrapper := range stats.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if wrapper.ConsumerOffset < 0 {
+			continue
+		}
+		backup.Queues = append(backup.Queues, QueueOffsetBackup{
+			Topic:      mq.Topic,
+			BrokerName: mq.BrokerName,
+			QueueId:    mq.QueueId,
+			Offset:     wrapper.ConsumerOffset,
+			CapturedAt: now.UnixMilli(),
+		})
+	}
+	if len(backup.Queues) == 0 {
+		return nil, fmt.Errorf("%w: %s 没有已提交的消费进度", ErrConsumerGroupNotFound, group)
+	}
+	sort.Slice(backup.Queues, func(i, j int) bool {
+		return queueLess(backup.Queues[i].MessageQueue(), backup.Queues[j].MessageQueue())
+	})
+	return backup, nil
+}
+
+// WriteOffsetBackup 以 JSON 格式写入消费进度备份
+func WriteOffsetBackup(w io.Writer, backup *OffsetBackup) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(backup); err != nil {
+		return fmt.Errorf("写入消费进度备份失败: %w", err)
+	}
+	return nil
+}
+
+// ReadOffsetBackup 读取消费进度备份
+func ReadOffsetBackup(r io.Reader) (*OffsetBackup, error) {
+	var backup OffsetBackup
+	if err := json.NewDecoder(r).Decode(&backup); err != nil {
+		return nil, fmt.Errorf("解析消费进度备份失败: %w", err)
+	}
+	if backup.Version <= 0 || backup.Version > OffsetBackupVersion {
+		return nil, fmt.Errorf("不支持的备份版本 %d，当前支持 %d", backup.Version, OffsetBackupVersion)
+	}
+	return &backup, nil
+}
+
+// OffsetRestoreStatus 单个队列的恢复状态
+type OffsetRestoreStatus string
+
+const (
+	OffsetRestored  OffsetRestoreStatus = "restored"  // 已恢复为备份值
+	OffsetUnchanged OffsetRestoreStatus = "unchanged" // 当前值与备份相同
+	OffsetClamped   OffsetRestoreStatus = "clamped"   // 备份值超过最大 Offset，已恢复为最大 Offset
+	OffsetExpired   OffsetRestoreStatus = "expired"   // 备份位置的消息已过期删除，已恢复为最小 Offset
+	OffsetFailed    OffsetRestoreStatus = "failed"    // 队列不存在或写入失败
+)
+
+// RestoreOffsetOptions 消费进度恢复选项
+type RestoreOffsetOptions struct {
+	// Group 恢复到的消费组，为空时使用备份中的消费组
+	Group string
+
+	// Topics 只恢复指定 Topic，为空时恢复全部
+	Topics []string
+
+	// Force 消费组在线时仍然恢复，并通知客户端
+	Force bool
+
+	// DryRun 只校验并生成报告，不修改集群
+	DryRun bool
+}
+
+// OffsetRestoreItem 单个队列的恢复结果
+type OffsetRestoreItem struct {
+	MessageQueue   MessageQueue        `json:"messageQueue"`
+	BackupOffset   int64               `json:"backupOffset"`     // 备份的 Offset
+	CurrentOffset  int64               `json:"currentOffset"`    // 恢复前的 Offset，-1 表示未提交
+	RestoredOffset int64               `json:"restoredOffset"`   // 写入的 Offset
+	MinOffset      int64               `json:"minOffset"`        // 队列当前最小 Offset
+	MaxOffset      int64               `json:"maxOffset"`        // 队列当前最大 Offset
+	Status         OffsetRestoreStatus `json:"status"`           // 恢复状态
+	Reason         string              `json:"reason,omitempty"` // 失败原因
+}
+
+// OffsetRestoreReport 消费进度恢复报告
+type OffsetRestoreReport struct {
+	Group  string              `json:"group"`
+	DryRun bool                `json:"dryRun"`
+	Items  []OffsetRestoreItem `json:"items"`
+}
+
+// Count 返回指定状态的队列数量
+func (r *OffsetRestoreReport) Count(status OffsetRestoreStatus) int {
+	n := 0
+	for _, item := range r.Items {
+		if item.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// RestoreConsumerOffsets 将备份的消费进度写回集群
+//
+// 每个队列的备份值先校验是否仍在当前 [min, max] 范围内：小于最小 Offset 时消息已
+// 过期，恢复为最小 Offset 并标记为 expired；大于最大 Offset 时恢复为最大 Offset
+// 并标记为 clamped。集群中已不存在的队列标记为 failed。消费组在线时客户端会覆盖
+// 写入的 Offset，除非 Force 否则返回 ErrConsumerOnline。
+func (c *Client) RestoreConsumerOffsets(ctx context.Context, backup *OffsetBackup, opts RestoreOffsetOptions) (*OffsetRestoreReport, error) {
+	group := opts.Group
+	if group == "" {
+		group = backup.Group
+	}
+	online, err := c.groupOnline(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	if online && !opts.Force {
+		return nil, fmt.Errorf("%w: %s，请先停止消费或使用 Force", ErrConsumerOnline, group)
+	}
+
+	wanted := make(map[string]bool, len(opts.Topics))
+	for _, topic := range opts.Topics {
+		wanted[topic] = true
+	}
+	byTopic := make(map[string][]QueueOffsetBackup)
+	for _, q := range backup.Queues {
+		if len(wanted) == 0 || wanted[q.Topic] {
+			byTopic[q.Topic] = append(byTopic[q.Topic], q)
+		}
+	}
+
+	report := &OffsetRestoreReport{Group: group, DryRun: opts.DryRun}
+	for _, topic := range sortedKeys(byTopic) {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		live := make(map[MessageQueue]QueueResetPlan)
+		queues, loadErr := c.loadQueueOffsets(ctx, group, topic)
+		for _, q := range queues {
+			live[q.MessageQueue] = q
+		}
+
+		for _, b := range byTopic[topic] {
+			mq := b.MessageQueue()
+			item := OffsetRestoreItem{MessageQueue: mq, BackupOffset: b.Offset, CurrentOffset: -1}
+			q, ok := live[mq]
+			switch {
+			case loadErr != nil:
+				item.Status, item.Reason = OffsetFailed, fmt.Sprintf("读取队列信息失败: %v", loadErr)
+			case !ok:
+				item.Status, item.Reason = OffsetFailed, "队列在集群中不存在"
+			default:
+				item.CurrentOffset, item.MinOffset, item.MaxOffset = q.CurrentOffset, q.MinOffset, q.MaxOffset
+				item.RestoredOffset = min(max(b.Offset, q.MinOffset), q.MaxOffset)
+				switch {
+				case b.Offset < q.MinOffset:
+					item.Status = OffsetExpired
+				case b.Offset > q.MaxOffset:
+					item.Status = OffsetClamped
+				case q.CurrentOffset == b.Offset:
+					item.Status = OffsetUnchanged
+				default:
+					item.Status = OffsetRestored
+				}
+				if item.RestoredOffset != q.CurrentOffset && !opts.DryRun {
+					if err := c.commitQueueOffset(ctx, q.BrokerAddr, group, mq, item.RestoredOffset, online); err != nil {
+						item.Status, item.Reason = OffsetFailed, err.Error()
+					}
+				}
+			}
+			report.Items = append(report.Items, item)
+		}
+	}
+
+	if n := report.Count(OffsetFailed); n > 0 {
+		return report, fmt.Errorf("恢复消费进度失败: %d/%d 个队列", n, len(report.Items))
+	}
+	return report, nil
+}
@@ -0,0 +1,111 @@
+package admin
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+)
+
+// =============================================================================
+// 消费进度备份与恢复单元测试
+// =============================================================================
+
+// TestBackupConsumerOffsets 测试备份消费进度并读写文件
+func TestBackupConsumerOffsets(t *testing.T) {
+	srv := newResetCluster(t)
+	client := newFakeClient(t, srv.Server)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	backup, err := client.BackupConsumerOffsets(ctx, "GroupA")
+	if err != nil {
+		t.Fatalf("备份消费进度失败: %v", err)
+	}
+	if backup.Group != "GroupA" || len(backup.Queues) != 2 {
+		t.Fatalf("应备份 2 个队列, got %+v", backup)
+	}
+	if q := backup.Queues[0]; q.BrokerName != "broker-a" || q.QueueId != 0 || q.Offset != 10 || q.CapturedAt == 0 {
+		t.Errorf("队列备份错误: %+v", q)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteOffsetBackup(&buf, backup); err != nil {
+		t.Fatalf("写入备份失败: %v", err)
+	}
+	read, err := ReadOffsetBackup(&buf)
+	if err != nil {
+		t.Fatalf("读取备份失败: %v", err)
+	}
+	if len(read.Queues) != 2 || read.Queues[1] != backup.Queues[1] {
+		t.Errorf("读写后备份不一致: %+v", read)
+	}
+	if _, err := ReadOffsetBackup(bytes.NewReader([]byte(`{"version":99}`))); err == nil {
+		t.Error("不支持的版本应返回错误")
+	}
+
+	// 原样恢复不做任何修改
+	report, err := client.RestoreConsumerOffsets(ctx, read, RestoreOffsetOptions{})
+	if err != nil {
+		t.Fatalf("恢复消费进度失败: %v", err)
+	}
+	if report.Count(OffsetUnchanged) != 2 || len(srv.Requests(remoting.UpdateConsumeOffset)) != 0 {
+		t.Errorf("原样恢复应不变: %+v", report.Items)
+	}
+}
+
+// TestRestoreConsumerOffsets 测试恢复时校验队列范围
+func TestRestoreConsumerOffsets(t *testing.T) {
+	srv := newResetCluster(t)
+	client := newFakeClient(t, srv.Server)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	backup := &OffsetBackup{Version: OffsetBackupVersion, Group: "GroupA", Queues: []QueueOffsetBackup{
+		{Topic: "TopicA", BrokerName: "broker-a", QueueId: 0, Offset: 1},  // 小于最小 Offset 2
+		{Topic: "TopicA", BrokerName: "broker-a", QueueId: 1, Offset: 50}, // 大于最大 Offset 10
+		{Topic: "TopicA", BrokerName: "broker-a", QueueId: 2, Offset: 3},
+		{Topic: "TopicA", BrokerName: "broker-b", QueueId: 0, Offset: 3}, // Broker 已不存在
+		{Topic: "TopicB", BrokerName: "broker-a", QueueId: 0, Offset: 3}, // 被 Topic 过滤
+	}}
+	want := []struct {
+		status   OffsetRestoreStatus
+		restored int64
+	}{
+		{OffsetExpired, 2}, {OffsetClamped, 10}, {OffsetRestored, 3}, {OffsetFailed, 0},
+	}
+
+	opts := RestoreOffsetOptions{Topics: []string{"TopicA"}, DryRun: true}
+	report, err := client.RestoreConsumerOffsets(ctx, backup, opts)
+	if err == nil {
+		t.Error("存在失败队列时应返回错误")
+	}
+	if len(report.Items) != len(want) {
+		t.Fatalf("应恢复 %d 个队列, got %+v", len(want), report.Items)
+	}
+	for i, w := range want {
+		if item := report.Items[i]; item.Status != w.status || item.RestoredOffset != w.restored {
+			t.Errorf("第 %d 个队列应为 %s/%d, got %+v", i, w.status, w.restored, item)
+		}
+	}
+	if n := len(srv.Requests(remoting.UpdateConsumeOffset)); n != 0 {
+		t.Errorf("预演不应提交 Offset, got %d 次", n)
+	}
+
+	opts.DryRun = false
+	if _, err := client.RestoreConsumerOffsets(ctx, backup, opts); err == nil {
+		t.Error("存在失败队列时应返回错误")
+	}
+	srv.mu.Lock()
+	if srv.offsets[0] != 2 || srv.offsets[1] != 10 || srv.offsets[2] != 3 {
+		t.Errorf("恢复后的 Offset 错误: %v", srv.offsets)
+	}
+	srv.online = true
+	srv.mu.Unlock()
+
+	// 消费组在线时需要强制执行
+	if _, err := client.RestoreConsumerOffsets(ctx, backup, opts); !errors.Is(err, ErrConsumerOnline) {
+		t.Errorf("消费组在线应返回 ErrConsumerOnline, got %v", err)
+	}
+}
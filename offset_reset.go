@@ -0,0 +1,381 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Offset 重置计划
+// =============================================================================
+
+// ResetTargetKind 重置目标类型
+type ResetTargetKind string
+
+const (
+	ResetToTimestamp ResetTargetKind = "timestamp" // 指定时间后的第一条消息
+	ResetToOffset    ResetTargetKind = "offset"    // 指定 Offset
+	ResetToMin       ResetTargetKind = "min"       // 最小 Offset，重新消费全部消息
+	ResetToMax       ResetTargetKind = "max"       // 最大 Offset，跳过全部堆积
+	ResetSkip        ResetTargetKind = "skip"      // 在当前 Offset 基础上跳过 N 条，负数表示回退
+)
+
+// ResetTarget 重置目标
+type ResetTarget struct {
+	Kind  ResetTargetKind `json:"kind"`
+	Value int64           `json:"value,omitempty"` // timestamp 为毫秒时间戳，offset 为目标 Offset，skip 为跳过的消息数
+}
+
+// String 返回目标的可读形式，如 "timestamp=1700000000000"、"max"
+func (t ResetTarget) String() string {
+	switch t.Kind {
+	case ResetToMin, ResetToMax:
+		return string(t.Kind)
+	default:
+		return fmt.Sprintf("%s=%d", t.Kind, t.Value)
+	}
+}
+
+// ParseResetTarget 解析 "timestamp=ms"、"offset=n"、"min"、"max"、"skip=n" 形式的重置目标
+func ParseResetTarget(s string) (ResetTarget, error) {
+	kind, value, hasValue := strings.Cut(strings.TrimSpace(s), "=")
+	target := ResetTarget{Kind: ResetTargetKind(strings.ToLower(kind))}
+	switch target.Kind {
+	case ResetToMin, ResetToMax:
+		if hasValue {
+			return target, fmt.Errorf("重置目标 %s 不需要参数", kind)
+		}
+		return target, nil
+	case ResetToTimestamp, ResetToOffset, ResetSkip:
+		if !hasValue {
+			return target, fmt.Errorf("重置目标 %s 需要参数，如 %s=100", kind, kind)
+		}
+		var err error
+		if target.Value, err = strconv.ParseInt(strings.TrimSpace(value), 10, 64); err != nil {
+			return target, fmt.Errorf("解析重置目标 %s 失败: %w", s, err)
+		}
+		return target, nil
+	default:
+		return target, fmt.Errorf("未知的重置目标: %s（支持 timestamp、offset、min、max、skip）", s)
+	}
+}
+
+// QueueResetPlan 单个队列的重置计划
+type QueueResetPlan struct {
+	MessageQueue  MessageQueue `json:"messageQueue"`
+	BrokerAddr    string       `json:"brokerAddr"`    // Master 地址
+	MinOffset     int64        `json:"minOffset"`     // 队列最小 Offset
+	MaxOffset     int64        `json:"maxOffset"`     // 队列最大 Offset
+	CurrentOffset int64        `json:"currentOffset"` // 当前已提交的 Offset，-1 表示未提交
+	TargetOffset  int64        `json:"targetOffset"`  // 目标 Offset，已截断到 [MinOffset, MaxOffset]
+	Skipped       int64        `json:"skipped"`       // 将跳过的未消费消息数
+	Reconsumed    int64        `json:"reconsumed"`    // 将重复消费的消息数
+}
+
+// OffsetResetPlan 消费组的 Offset 重置计划
+type OffsetResetPlan struct {
+	Group     string           `json:"group"`
+	Target    ResetTarget      `json:"target"`
+	CreatedAt time.Time        `json:"createdAt"`
+	Queues    []QueueResetPlan `json:"queues"`
+}
+
+// Skipped 返回将跳过的未消费消息总数
+func (p *OffsetResetPlan) Skipped() int64 {
+	var n int64
+	for _, q := range p.Queues {
+		n += q.Skipped
+	}
+	return n
+}
+
+// Reconsumed 返回将重复消费的消息总数
+func (p *OffsetResetPlan) Reconsumed() int64 {
+	var n int64
+	for _, q := range p.Queues {
+		n += q.Reconsumed
+	}
+	return n
+}
+
+// PlanOffsetReset 计算消费组在各队列上的重置计划，不修改集群
+//
+// topics 为空时使用消费组已提交 Offset 的全部 Topic（不含重试 Topic）。目标 Offset
+// 截断到队列的 [min, max]；未提交 Offset 的队列按 skip 重置时以最小 Offset 为起点。
+// 计划可以序列化保存，确认后通过 ExecuteOffsetReset 执行。
+func (c *Client) PlanOffsetReset(ctx context.Context, group string, topics []string, target ResetTarget) (*OffsetResetPlan, error) {
+	switch target.Kind {
+	case ResetToTimestamp, ResetToOffset, ResetToMin, ResetToMax, ResetSkip:
+	default:
+		return nil, fmt.Errorf("未知的重置目标: %s", target.Kind)
+	}
+
+	if len(topics) == 0 {
+		stats, err := c.ExamineConsumeStats(ctx, group)
+		if err != nil {
+			return nil, fmt.Errorf("获取消费组 %s 消费统计失败: %w", group, err)
+		}
+		seen := make(map[string]bool)
+		for key := range stats.OffsetTable {
+			mq, err := ParseMessageQueueKey(key)
+			if err != nil || seen[mq.Topic] || strings.HasPrefix(mq.Topic, RetryGroupTopicPrefix) {
+				continue
+			}
+			seen[mq.Topic] = true
+			topics = append(topics, mq.Topic)
+		}
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("%w: %s 没有已提交的消费进度", ErrConsumerGroupNotFound, group)
+		}
+	}
+
+	plan := &OffsetResetPlan{Group: group, Target: target, CreatedAt: time.Now()}
+	for _, topic := range topics {
+		queues, err := c.planTopicReset(ctx, group, topic, target)
+		if err != nil {
+			return nil, fmt.Errorf("计算 Topic %s 的重置计划失败: %w", topic, err)
+		}
+		plan.Queues = append(plan.Queues, queues...)
+	}
+	sort.Slice(plan.Queues, func(i, j int) bool { return queueLess(plan.Queues[i].MessageQueue, plan.Queues[j].MessageQueue) })
+	return plan, nil
+}
+
+// planTopicReset 计算单个 Topic 各队列的重置计划
+func (c *Client) planTopicReset(ctx context.Context, group, topic string, target ResetTarget) ([]QueueResetPlan, error) {
+	queues, err := c.loadQueueOffsets(ctx, group, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range queues {
+		q := &queues[i]
+		switch target.Kind {
+		case ResetToTimestamp:
+			mq := q.MessageQueue
+			if q.TargetOffset, err = c.SearchOffset(ctx, q.BrokerAddr, topic, mq.QueueId, target.Value); err != nil {
+				return nil, fmt.Errorf("按时间查询 %s@%d 的 Offset 失败: %w", mq.BrokerName, mq.QueueId, err)
+			}
+		case ResetToOffset:
+			q.TargetOffset = target.Value
+		case ResetToMin:
+			q.TargetOffset = q.MinOffset
+		case ResetToMax:
+			q.TargetOffset = q.MaxOffset
+		case ResetSkip:
+			q.TargetOffset = max(q.CurrentOffset, q.MinOffset) + target.Value
+		}
+		q.TargetOffset = min(max(q.TargetOffset, q.MinOffset), q.MaxOffset)
+
+		// 已过期的消息不计入，以当前 Offset 和最小 Offset 中较大者为起点
+		if q.CurrentOffset >= 0 {
+			base := max(q.CurrentOffset, q.MinOffset)
+			if q.TargetOffset > base {
+				q.Skipped = q.TargetOffset - base
+			} else {
+				q.Reconsumed = base - q.TargetOffset
+			}
+		}
+	}
+	return queues, nil
+}
+
+// loadQueueOffsets 读取 Topic 在各 Master 上的队列范围和消费组已提交的 Offset，目标 Offset 未填写
+func (c *Client) loadQueueOffsets(ctx context.Context, group, topic string) ([]QueueResetPlan, error) {
+	routeData, err := c.ExamineTopicRouteInfo(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+	masters := masterAddrs(routeData)
+	stats, err := c.ExamineTopicStats(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[MessageQueue]int64)
+	for brokerName, addr := range masters {
+		offsets, err := c.brokerConsumeStats(ctx, addr, group, topic)
+		if err != nil {
+			// 消费组在该 Broker 上没有订阅关系时视为未提交
+			continue
+		}
+		for mq, wrapper := range offsets {
+			if mq.BrokerName == brokerName {
+				current[mq] = wrapper.ConsumerOffset
+			}
+		}
+	}
+
+	var queues []QueueResetPlan
+	for key, offset := range stats.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil {
+			return nil, err
+		}
+		addr, ok := masters[mq.BrokerName]
+		if !ok {
+			continue
+		}
+		q := QueueResetPlan{MessageQueue: mq, BrokerAddr: addr, MinOffset: offset.MinOffset, MaxOffset: offset.MaxOffset, CurrentOffset: -1}
+		if committed, ok := current[mq]; ok && committed >= 0 {
+			q.CurrentOffset = committed
+		}
+		queues = append(queues, q)
+	}
+	return queues, nil
+}
+
+// ExecuteOffsetReset 按计划重置消费组 Offset 并校验结果
+//
+// 严格使用计划中的目标 Offset，不重新计算。消费组在线时通过 ResetOffsetByQueueId
+// 通知客户端，否则直接 UpdateConsumeOffset。执行后重新读取各队列的已提交 Offset，
+// 与目标不一致的队列记录为失败。
+func (c *Client) ExecuteOffsetReset(ctx context.Context, plan *OffsetResetPlan) ([]QueueOffsetResult, error) {
+	online, err := c.groupOnline(ctx, plan.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]QueueOffsetResult, len(plan.Queues))
+	for i, q := range plan.Queues {
+		results[i] = QueueOffsetResult{MessageQueue: q.MessageQueue, OldOffset: q.CurrentOffset, NewOffset: q.TargetOffset}
+		if q.CurrentOffset == q.TargetOffset {
+			continue
+		}
+		if err := c.commitQueueOffset(ctx, q.BrokerAddr, plan.Group, q.MessageQueue, q.TargetOffset, online); err != nil {
+			results[i].Error = err.Error()
+		}
+	}
+
+	// 按 Broker 和 Topic 重新读取已提交的 Offset 进行校验
+	type brokerTopic struct{ addr, topic string }
+	verified := make(map[brokerTopic]map[MessageQueue]*OffsetWrapper)
+	failed := 0
+	for i, q := range plan.Queues {
+		r := &results[i]
+		if r.Error == "" {
+			key := brokerTopic{q.BrokerAddr, q.MessageQueue.Topic}
+			offsets, ok := verified[key]
+			if !ok {
+				if offsets, err = c.brokerConsumeStats(ctx, q.BrokerAddr, plan.Group, q.MessageQueue.Topic); err != nil {
+					offsets = nil
+				}
+				verified[key] = offsets
+			}
+			switch wrapper, ok := offsets[q.MessageQueue]; {
+			case offsets == nil:
+				r.Error = "校验失败: 无法读取已提交的 Offset"
+			case !ok:
+				r.Error = "校验失败: 队列没有已提交的 Offset"
+			case wrapper.ConsumerOffset != q.TargetOffset:
+				r.Error = fmt.Sprintf("校验失败: 已提交的 Offset 为 %d", wrapper.ConsumerOffset)
+			}
+		}
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("重置消费位点失败: %d/%d 个队列", failed, len(results))
+	}
+	return results, nil
+}
+
+// groupOnline 返回消费组是否有在线客户端
+func (c *Client) groupOnline(ctx context.Context, group string) (bool, error) {
+	conn, err := c.ExamineConsumerConnectionInfo(ctx, group)
+	switch {
+	case err == nil:
+		return len(conn.ConnectionSet) > 0, nil
+	case errors.Is(err, ErrConsumerGroupNotFound):
+		return false, nil
+	default:
+		return false, fmt.Errorf("查询消费组 %s 连接失败: %w", group, err)
+	}
+}
+
+// commitQueueOffset 写入单个队列的 Offset，消费组在线时同时通知客户端
+func (c *Client) commitQueueOffset(ctx context.Context, addr, group string, mq MessageQueue, offset int64, online bool) error {
+	if online {
+		return c.ResetOffsetByQueueId(ctx, addr, group, mq.Topic, mq.QueueId, offset)
+	}
+	return c.UpdateConsumeOffset(ctx, addr, group, mq.Topic, mq.QueueId, offset)
+}
+
+// =============================================================================
+// 跳过堆积
+// =============================================================================
+
+// SkipAccumulatedMessages 将消费组的已提交 Offset 移动到各队列的最大 Offset，丢弃全部堆积
+//
+// topic 为空时处理消费组已提交 Offset 的全部 Topic；includeRetry 为 true 时同时跳过
+// 重试 Topic 的堆积。消费组在线时由 Broker 重置并通知客户端（与 Java
+// skipAccumulatedMessage 相同），不在线时直接改写已提交的 Offset。
+func (c *Client) SkipAccumulatedMessages(ctx context.Context, group, topic string, includeRetry bool) ([]QueueOffsetResult, error) {
+	var topics []string
+	if topic != "" {
+		topics = append(topics, topic)
+	} else {
+		stats, err := c.ExamineConsumeStats(ctx, group)
+		if err != nil {
+			return nil, fmt.Errorf("获取消费组 %s 消费统计失败: %w", group, err)
+		}
+		seen := make(map[string]bool)
+		for key := range stats.OffsetTable {
+			mq, err := ParseMessageQueueKey(key)
+			if err != nil || seen[mq.Topic] || strings.HasPrefix(mq.Topic, RetryGroupTopicPrefix) {
+				continue
+			}
+			seen[mq.Topic] = true
+			topics = append(topics, mq.Topic)
+		}
+		sort.Strings(topics)
+	}
+	if includeRetry {
+		// 没有消费失败过的消费组不存在重试 Topic
+		retryTopic := GetRetryTopic(group)
+		if _, err := c.ExamineTopicRouteInfo(ctx, retryTopic); err == nil {
+			topics = append(topics, retryTopic)
+		} else if !errors.Is(err, ErrTopicNotFound) {
+			return nil, fmt.Errorf("查询重试 Topic 路由失败: %w", err)
+		}
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("%w: %s 没有已提交的消费进度", ErrConsumerGroupNotFound, group)
+	}
+
+	online, err := c.groupOnline(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	if !online {
+		plan, err := c.PlanOffsetReset(ctx, group, topics, ResetTarget{Kind: ResetToMax})
+		if err != nil {
+			return nil, err
+		}
+		return c.ExecuteOffsetReset(ctx, plan)
+	}
+
+	// Broker 将时间戳 -1 解释为最大 Offset
+	var (
+		results []QueueOffsetResult
+		errs    []error
+	)
+	for _, t := range topics {
+		queues, err := c.ResetOffsetByTimestamp(ctx, t, group, -1, true)
+		results = append(results, queues...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", t, err))
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return queueLess(results[i].MessageQueue, results[j].MessageQueue) })
+	if len(errs) > 0 {
+		return results, fmt.Errorf("跳过堆积失败: %w", errors.Join(errs...))
+	}
+	return results, nil
+}
@@ -0,0 +1,238 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// Offset 重置计划单元测试
+// =============================================================================
+
+// TestParseResetTarget 测试解析重置目标
+func TestParseResetTarget(t *testing.T) {
+	cases := []struct {
+		in   string
+		want ResetTarget
+	}{
+		{"timestamp=1700000000000", ResetTarget{Kind: ResetToTimestamp, Value: 1700000000000}},
+		{"offset=42", ResetTarget{Kind: ResetToOffset, Value: 42}},
+		{"MAX", ResetTarget{Kind: ResetToMax}},
+		{"min", ResetTarget{Kind: ResetToMin}},
+		{"skip=-10", ResetTarget{Kind: ResetSkip, Value: -10}},
+	}
+	for _, c := range cases {
+		got, err := ParseResetTarget(c.in)
+		if err != nil || got != c.want {
+			t.Errorf("ParseResetTarget(%q) = %+v, %v, want %+v", c.in, got, err, c.want)
+		}
+	}
+	for _, in := range []string{"skip", "max=1", "offset=abc", "latest"} {
+		if _, err := ParseResetTarget(in); err == nil {
+			t.Errorf("ParseResetTarget(%q) 应返回错误", in)
+		}
+	}
+	if s := (ResetTarget{Kind: ResetSkip, Value: 5}).String(); s != "skip=5" {
+		t.Errorf("String() = %s", s)
+	}
+}
+
+// resetCluster 模拟单 Broker 集群中 GroupA 在 TopicA 上的消费进度
+type resetCluster struct {
+	*remotingtest.Server
+	mu           sync.Mutex
+	offsets      map[int]int64 // queueId -> 已提交 Offset
+	online       bool
+	ignoreWrites bool
+}
+
+// newResetCluster 启动模拟集群：TopicA 三个队列，范围分别为 [2,20]、[0,10]、[0,5]，
+// 已提交 q0=10、q1=1，q2 未提交；按时间查询的结果均为 4
+func newResetCluster(t *testing.T) *resetCluster {
+	t.Helper()
+	srv := &resetCluster{Server: remotingtest.NewServer(), offsets: map[int]int64{0: 10, 1: 1}}
+	t.Cleanup(srv.Close)
+	handleClusterInfo(srv.Server, "broker-a")
+
+	srv.Handle(remoting.GetRouteInfoByTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"queueDatas":[{"brokerName":"broker-a","readQueueNums":3,"writeQueueNums":3,"perm":6}],` +
+			`"brokerDatas":[{"cluster":"DefaultCluster","brokerName":"broker-a","brokerAddrs":{0:"` + srv.Addr + `"}}]}`))
+	})
+	srv.Handle(remoting.GetTopicStatsInfo, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"offsetTable":{` +
+			`{"brokerName":"broker-a","queueId":0,"topic":"TopicA"}:{"minOffset":2,"maxOffset":20},` +
+			`{"brokerName":"broker-a","queueId":1,"topic":"TopicA"}:{"minOffset":0,"maxOffset":10},` +
+			`{"brokerName":"broker-a","queueId":2,"topic":"TopicA"}:{"minOffset":0,"maxOffset":5}}}`))
+	})
+	srv.Handle(remoting.GetConsumeStats, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		var entries []string
+		for queueId, offset := range srv.offsets {
+			entries = append(entries, fmt.Sprintf(`{"brokerName":"broker-a","queueId":%d,"topic":"TopicA"}:{"brokerOffset":20,"consumerOffset":%d}`, queueId, offset))
+		}
+		return remotingtest.Success([]byte(`{"offsetTable":{` + strings.Join(entries, ",") + `}}`))
+	})
+	srv.Handle(remoting.SearchOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		resp := remotingtest.Success(nil)
+		resp.ExtFields = map[string]string{"offset": "4"}
+		return resp
+	})
+	srv.Handle(remoting.UpdateConsumeOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		if !srv.ignoreWrites {
+			offset, _ := strconv.ParseInt(req.ExtFields["commitOffset"], 10, 64)
+			srv.offsets[atoi(req.ExtFields["queueId"])] = offset
+		}
+		return remotingtest.Success(nil)
+	})
+	srv.Handle(remoting.ResetOffsetByQueueId, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success(nil)
+	})
+	srv.Handle(remoting.GetConsumerConnectionList, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		if !srv.online {
+			return remotingtest.Error(remoting.ConsumerNotOnline, "the consumer group not online")
+		}
+		return remotingtest.JSON(map[string]any{"connectionSet": []map[string]any{{"clientId": "10.0.0.3@1"}}})
+	})
+	return srv
+}
+
+// TestPlanOffsetReset 测试计算各类目标的重置计划
+func TestPlanOffsetReset(t *testing.T) {
+	client := newFakeClient(t, newResetCluster(t).Server)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	cases := []struct {
+		target     string
+		offsets    [3]int64
+		skipped    int64
+		reconsumed int64
+	}{
+		{"timestamp=1700000000000", [3]int64{4, 4, 4}, 3, 6},
+		{"offset=100", [3]int64{20, 10, 5}, 19, 0},
+		{"min", [3]int64{2, 0, 0}, 0, 9},
+		{"max", [3]int64{20, 10, 5}, 19, 0},
+		{"skip=5", [3]int64{15, 6, 5}, 10, 0},
+		{"skip=-100", [3]int64{2, 0, 0}, 0, 9},
+	}
+	for _, c := range cases {
+		target, _ := ParseResetTarget(c.target)
+		plan, err := client.PlanOffsetReset(ctx, "GroupA", nil, target)
+		if err != nil {
+			t.Fatalf("%s: 计算重置计划失败: %v", c.target, err)
+		}
+		if len(plan.Queues) != 3 {
+			t.Fatalf("%s: 应包含 3 个队列, got %+v", c.target, plan.Queues)
+		}
+		for i, q := range plan.Queues {
+			if q.MessageQueue.QueueId != i || q.TargetOffset != c.offsets[i] {
+				t.Errorf("%s: 队列 %d 目标应为 %d, got %+v", c.target, i, c.offsets[i], q)
+			}
+		}
+		if plan.Skipped() != c.skipped || plan.Reconsumed() != c.reconsumed {
+			t.Errorf("%s: 跳过/重复消费应为 %d/%d, got %d/%d", c.target, c.skipped, c.reconsumed, plan.Skipped(), plan.Reconsumed())
+		}
+		if q := plan.Queues[2]; q.CurrentOffset != -1 || q.Skipped != 0 || q.Reconsumed != 0 {
+			t.Errorf("%s: 未提交的队列不计算跳过数: %+v", c.target, q)
+		}
+	}
+}
+
+// TestExecuteOffsetReset 测试按计划执行重置并校验
+func TestExecuteOffsetReset(t *testing.T) {
+	srv := newResetCluster(t)
+	client := newFakeClient(t, srv.Server)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	plan, err := client.PlanOffsetReset(ctx, "GroupA", []string{"TopicA"}, ResetTarget{Kind: ResetToMax})
+	if err != nil {
+		t.Fatalf("计算重置计划失败: %v", err)
+	}
+	results, err := client.ExecuteOffsetReset(ctx, plan)
+	if err != nil {
+		t.Fatalf("执行重置失败: %v %+v", err, results)
+	}
+	if len(results) != 3 || results[0].OldOffset != 10 || results[0].NewOffset != 20 || results[2].OldOffset != -1 {
+		t.Errorf("重置结果错误: %+v", results)
+	}
+	if n := len(srv.Requests(remoting.ResetOffsetByQueueId)); n != 0 {
+		t.Errorf("消费组不在线时不应通知客户端, got %d 次", n)
+	}
+
+	// 消费组在线时通过 ResetOffsetByQueueId 通知客户端
+	srv.mu.Lock()
+	srv.online = true
+	srv.mu.Unlock()
+	plan, _ = client.PlanOffsetReset(ctx, "GroupA", []string{"TopicA"}, ResetTarget{Kind: ResetToOffset, Value: 3})
+	if _, err := client.ExecuteOffsetReset(ctx, plan); err != nil {
+		t.Fatalf("执行重置失败: %v", err)
+	}
+	reqs := srv.Requests(remoting.ResetOffsetByQueueId)
+	if len(reqs) != 3 || reqs[0].ExtFields["offset"] != "3" || reqs[0].ExtFields["group"] != "GroupA" {
+		t.Errorf("应按队列通知客户端: %+v", reqs)
+	}
+
+	// Broker 未生效时校验失败
+	srv.mu.Lock()
+	srv.ignoreWrites = true
+	srv.mu.Unlock()
+	plan, _ = client.PlanOffsetReset(ctx, "GroupA", []string{"TopicA"}, ResetTarget{Kind: ResetToMin})
+	results, err = client.ExecuteOffsetReset(ctx, plan)
+	if err == nil || !strings.Contains(results[0].Error, "校验失败") {
+		t.Errorf("未生效的重置应校验失败: %v %+v", err, results)
+	}
+}
+
+// TestSkipAccumulatedMessages 测试跳过堆积：不在线时改写 Offset，在线时由 Broker 重置
+func TestSkipAccumulatedMessages(t *testing.T) {
+	srv := newResetCluster(t)
+	client := newFakeClient(t, srv.Server)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	results, err := client.SkipAccumulatedMessages(ctx, "GroupA", "", false)
+	if err != nil {
+		t.Fatalf("跳过堆积失败: %v %+v", err, results)
+	}
+	if len(results) != 3 || results[0].NewOffset != 20 || results[1].NewOffset != 10 || results[2].NewOffset != 5 {
+		t.Fatalf("应移动到最大 Offset: %+v", results)
+	}
+	if results[0].Skipped() != 10 || results[1].Skipped() != 9 || results[2].Skipped() != 0 {
+		t.Errorf("跳过数错误: %+v", results)
+	}
+
+	// 消费组在线时通过 Broker 按时间戳 -1 重置
+	srv.mu.Lock()
+	srv.online = true
+	srv.mu.Unlock()
+	srv.Handle(remoting.InvokeBrokerToResetOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		if req.ExtFields["queueId"] != "" {
+			return remotingtest.Success(nil)
+		}
+		return remotingtest.Success([]byte(`{"offsetTable":{` +
+			`{"brokerName":"broker-a","queueId":0,"topic":"` + req.ExtFields["topic"] + `"}:20}}`))
+	})
+	results, err = client.SkipAccumulatedMessages(ctx, "GroupA", "TopicA", true)
+	if err != nil {
+		t.Fatalf("跳过堆积失败: %v", err)
+	}
+	reqs := srv.Requests(remoting.InvokeBrokerToResetOffset)
+	if len(reqs) != 2 || reqs[0].ExtFields["timestamp"] != "-1" || reqs[1].ExtFields["topic"] != "%RETRY%GroupA" {
+		t.Errorf("应由 Broker 重置业务 Topic 与重试 Topic: %+v", reqs)
+	}
+	if len(results) != 2 || results[1].MessageQueue.Topic != "TopicA" {
+		t.Errorf("重置结果错误: %+v", results)
+	}
+}
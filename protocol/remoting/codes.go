@@ -81,9 +81,12 @@ const (
 	// GetMinOffset 获取最小 Offset
 	GetMinOffset = 31
 
-	// ResetConsumerOffset 重置消费者 Offset
+	// ResetConsumerOffset Broker 通知客户端重置 Offset（RESET_CONSUMER_CLIENT_OFFSET）
 	ResetConsumerOffset = 220
 
+	// InvokeBrokerToResetOffset 由 Broker 按时间戳重置消费组 Offset 并通知在线客户端
+	InvokeBrokerToResetOffset = 222
+
 	// ========== ACL 相关 ==========
 
 	// UpdateAclConfig 更新 ACL 配置
@@ -176,13 +179,22 @@ const (
 	// ViewMessageById 按 ID 查看消息
 	ViewMessageById = 33
 
+	// PullMessage 拉取消息
+	PullMessage = 11
+
+	// SendMessageV2 发送消息（精简请求头）
+	SendMessageV2 = 310
+
 	// ========== Offset 扩展 ==========
 
-	// UpdateConsumeOffset 更新消费 Offset
-	UpdateConsumeOffset = 221
+	// UpdateConsumeOffset 更新消费 Offset（UPDATE_CONSUMER_OFFSET）
+	UpdateConsumeOffset = 15
+
+	// ResetOffsetByQueueId 按队列 ID 重置 Offset，与 InvokeBrokerToResetOffset 共用请求码，请求头携带 queueId 和 offset
+	ResetOffsetByQueueId = InvokeBrokerToResetOffset
 
-	// ResetOffsetByQueueId 按队列 ID 重置 Offset
-	ResetOffsetByQueueId = 222
+	// GetAllConsumerOffset 获取 Broker 上全部消费 Offset
+	GetAllConsumerOffset = 43
 
 	// ========== Broker 扩展 ==========
 
@@ -265,6 +277,9 @@ const (
 	// SetMessageRequestMode 设置消息请求模式
 	SetMessageRequestMode = 342
 
+	// UpdateAndGetGroupForbidden 更新并查询订阅组对 Topic 的读取禁止状态
+	UpdateAndGetGroupForbidden = 353
+
 	// ========== 同步状态 ==========
 
 	// GetInSyncStateData 获取同步状态数据
@@ -319,12 +334,36 @@ const (
 	// RequestCodeNotSupported 请求码不支持
 	RequestCodeNotSupported = 3
 
+	// FlushDiskTimeout 刷盘超时
+	FlushDiskTimeout = 10
+
+	// SlaveNotAvailable Slave 不可用
+	SlaveNotAvailable = 11
+
+	// FlushSlaveTimeout 同步 Slave 超时
+	FlushSlaveTimeout = 12
+
+	// NoPermission 没有权限
+	NoPermission = 16
+
 	// TopicNotExist Topic 不存在
 	TopicNotExist = 17
 
+	// PullNotFound 没有新消息
+	PullNotFound = 19
+
+	// PullRetryImmediately 立即重试
+	PullRetryImmediately = 20
+
+	// PullOffsetMoved 拉取偏移非法
+	PullOffsetMoved = 21
+
 	// SubscriptionNotExist 订阅不存在
 	SubscriptionNotExist = 21
 
+	// QueryNotFound 查询结果不存在（如 KV 命名空间不存在）
+	QueryNotFound = 22
+
 	// ConsumerNotOnline 消费者不在线
 	ConsumerNotOnline = 206
 )
@@ -0,0 +1,33 @@
+package remoting
+
+import (
+	"testing"
+)
+
+// TestRequestCodes 校验请求码与 Java RequestCode 的取值一致，写错会被 Broker 当作其他请求处理
+func TestRequestCodes(t *testing.T) {
+	cases := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"UpdateAndCreateTopic", UpdateAndCreateTopic, 17},
+		{"GetBrokerConfig", GetBrokerConfig, 26},
+		{"SearchOffsetByTimestamp", SearchOffsetByTimestamp, 29},
+		{"GetMaxOffset", GetMaxOffset, 30},
+		{"GetMinOffset", GetMinOffset, 31},
+		{"GetRouteInfoByTopic", GetRouteInfoByTopic, 105},
+		{"GetBrokerClusterInfo", GetBrokerClusterInfo, 106},
+		{"DeleteSubscriptionGroup", DeleteSubscriptionGroup, 207},
+		{"DeleteTopicInBroker", DeleteTopicInBroker, 215},
+		{"UpdateConsumeOffset", UpdateConsumeOffset, 15},
+		{"ResetConsumerOffset", ResetConsumerOffset, 220},
+		{"InvokeBrokerToResetOffset", InvokeBrokerToResetOffset, 222},
+		{"ResetOffsetByQueueId", ResetOffsetByQueueId, 222},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("%s 应为 %d, got %d", c.name, c.want, c.got)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Package remotingtest 提供用于测试的本地 Remoting 服务端
+//
+// Server 监听本地随机端口，按请求码分发到注册的处理函数，可同时充当
+// NameServer 和 Broker，使上层运维接口无需真实集群即可端到端测试。
+package remotingtest
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+)
+
+// HandlerFunc 请求处理函数，返回的响应会自动设置 Opaque 和响应标志
+type HandlerFunc func(req *remoting.RemotingCommand) *remoting.RemotingCommand
+
+// Server 本地 Remoting 服务端
+type Server struct {
+	// Addr 监听地址（host:port）
+	Addr string
+
+	listener net.Listener
+	mu       sync.RWMutex
+	handlers map[int]HandlerFunc
+	requests []*remoting.RemotingCommand
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+	closed   bool
+}
+
+// NewServer 在 127.0.0.1 的随机端口上启动服务端
+func NewServer() *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("remotingtest: 监听失败: %v", err))
+	}
+
+	s := &Server{
+		Addr:     ln.Addr().String(),
+		listener: ln,
+		handlers: make(map[int]HandlerFunc),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Handle 注册请求码的处理函数，重复注册会覆盖
+func (s *Server) Handle(code int, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[code] = handler
+}
+
+// Requests 返回已收到的指定请求码的请求，code 小于 0 时返回全部请求
+func (s *Server) Requests(code int) []*remoting.RemotingCommand {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*remoting.RemotingCommand
+	for _, req := range s.requests {
+		if code < 0 || req.Code == code {
+			result = append(result, req)
+		}
+	}
+	return result
+}
+
+// Close 关闭服务端及全部连接
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.listener.Close()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// serve 接受连接
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn 逐个读取请求并写回响应
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	var writeMu sync.Mutex
+	for {
+		lengthBuf := make([]byte, 4)
+		if _, err := io.ReadFull(conn, lengthBuf); err != nil {
+			return
+		}
+		data := make([]byte, binary.BigEndian.Uint32(lengthBuf))
+		if _, err := io.ReadFull(conn, data); err != nil {
+			return
+		}
+
+		req, err := remoting.Decode(data)
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		handler := s.handlers[req.Code]
+		s.mu.Unlock()
+
+		oneway := req.Flag&0x02 != 0
+		go func() {
+			var resp *remoting.RemotingCommand
+			if handler != nil {
+				resp = handler(req)
+			}
+			if resp == nil {
+				resp = Error(remoting.RequestCodeNotSupported, fmt.Sprintf("request code %d not supported", req.Code))
+			}
+			if oneway {
+				return
+			}
+
+			resp.Opaque = req.Opaque
+			resp.MarkResponseType()
+			encoded, err := resp.Encode()
+			if err != nil {
+				return
+			}
+			writeMu.Lock()
+			conn.Write(encoded)
+			writeMu.Unlock()
+		}()
+	}
+}
+
+// =============================================================================
+// 响应构造
+// =============================================================================
+
+// Success 返回成功响应，body 可为空
+func Success(body []byte) *remoting.RemotingCommand {
+	return &remoting.RemotingCommand{
+		Code:     remoting.Success,
+		Language: "JAVA",
+		Version:  remoting.CurrentVersion,
+		Body:     body,
+	}
+}
+
+// JSON 返回以 v 的 JSON 编码为 body 的成功响应
+func JSON(v any) *remoting.RemotingCommand {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return Error(remoting.SystemError, err.Error())
+	}
+	return Success(body)
+}
+
+// Error 返回错误响应
+func Error(code int, remark string) *remoting.RemotingCommand {
+	return &remoting.RemotingCommand{
+		Code:     code,
+		Language: "JAVA",
+		Version:  remoting.CurrentVersion,
+		Remark:   remark,
+	}
+}
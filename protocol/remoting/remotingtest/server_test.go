@@ -0,0 +1,51 @@
+package remotingtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+)
+
+// TestServer 测试请求分发、请求记录与未注册请求码
+func TestServer(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.Handle(remoting.GetBrokerClusterInfo, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return JSON(map[string]string{"echo": req.ExtFields["key"]})
+	})
+
+	client := remoting.NewClient(s.Addr, time.Second)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	resp, err := client.InvokeSync(ctx, remoting.NewRequest(remoting.GetBrokerClusterInfo, map[string]string{"key": "v"}))
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	if resp.Code != remoting.Success || string(resp.Body) != `{"echo":"v"}` || !resp.IsResponseType() {
+		t.Errorf("响应不匹配: code=%d body=%s", resp.Code, resp.Body)
+	}
+
+	resp, err = client.InvokeSync(ctx, remoting.NewRequest(remoting.GetBrokerConfig, nil))
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	if resp.Code != remoting.RequestCodeNotSupported {
+		t.Errorf("未注册请求码应返回 %d, got %d", remoting.RequestCodeNotSupported, resp.Code)
+	}
+
+	if n := len(s.Requests(remoting.GetBrokerClusterInfo)); n != 1 {
+		t.Errorf("应记录 1 个集群信息请求, got %d", n)
+	}
+	if n := len(s.Requests(-1)); n != 2 {
+		t.Errorf("应记录 2 个请求, got %d", n)
+	}
+}
@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+)
+
+// =============================================================================
+// 消息拉取
+// =============================================================================
+
+// ToolsConsumerGroup 运维工具使用的消费组（对应 Java MixAll.TOOLS_CONSUMER_GROUP）
+const ToolsConsumerGroup = "TOOLS_CONSUMER"
+
+// pullSysFlagSubscription 拉取请求携带订阅表达式（对应 Java PullSysFlag.FLAG_SUBSCRIPTION）
+const pullSysFlagSubscription = 0x1 << 2
+
+// PullStatus 拉取结果状态
+type PullStatus string
+
+// 拉取结果状态定义
+const (
+	PullStatusFound         PullStatus = "FOUND"          // 拉取到消息
+	PullStatusNoNewMsg      PullStatus = "NO_NEW_MSG"     // 没有新消息
+	PullStatusNoMatchedMsg  PullStatus = "NO_MATCHED_MSG" // 没有匹配的消息
+	PullStatusOffsetIllegal PullStatus = "OFFSET_ILLEGAL" // 偏移非法
+)
+
+// PullResult 拉取结果
+type PullResult struct {
+	Status          PullStatus    `json:"status"`          // 状态
+	NextBeginOffset int64         `json:"nextBeginOffset"` // 下次拉取偏移
+	MinOffset       int64         `json:"minOffset"`       // 队列最小偏移
+	MaxOffset       int64         `json:"maxOffset"`       // 队列最大偏移
+	Messages        []*MessageExt `json:"messages"`        // 消息列表
+}
+
+// PullMessage 从指定队列拉取消息，不提交消费进度
+// consumerGroup 为空时使用 TOOLS_CONSUMER，订阅表达式固定为 "*"
+func (c *Client) PullMessage(ctx context.Context, brokerAddr string, mq MessageQueue, consumerGroup string, offset int64, maxNums int) (*PullResult, error) {
+	if consumerGroup == "" {
+		consumerGroup = ToolsConsumerGroup
+	}
+
+	extFields := map[string]string{
+		"consumerGroup":        consumerGroup,
+		"topic":                mq.Topic,
+		"queueId":              fmt.Sprintf("%d", mq.QueueId),
+		"queueOffset":          fmt.Sprintf("%d", offset),
+		"maxMsgNums":           fmt.Sprintf("%d", maxNums),
+		"sysFlag":              fmt.Sprintf("%d", pullSysFlagSubscription),
+		"commitOffset":         "0",
+		"suspendTimeoutMillis": "0",
+		"subscription":         "*",
+		"subVersion":           "0",
+		"expressionType":       "TAG",
+	}
+	cmd := remoting.NewRequest(remoting.PullMessage, extFields)
+
+	resp, err := c.invokeBroker(ctx, brokerAddr, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PullResult{
+		NextBeginOffset: parseExtInt64(resp.ExtFields, "nextBeginOffset"),
+		MinOffset:       parseExtInt64(resp.ExtFields, "minOffset"),
+		MaxOffset:       parseExtInt64(resp.ExtFields, "maxOffset"),
+	}
+
+	switch resp.Code {
+	case remoting.Success:
+		result.Status = PullStatusFound
+		msgs, err := decodeMessages(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("解析拉取消息失败: %w", err)
+		}
+		for _, msg := range msgs {
+			msg.BrokerName = mq.BrokerName
+		}
+		result.Messages = msgs
+	case remoting.PullNotFound:
+		result.Status = PullStatusNoNewMsg
+	case remoting.PullRetryImmediately:
+		result.Status = PullStatusNoMatchedMsg
+	case remoting.PullOffsetMoved:
+		result.Status = PullStatusOffsetIllegal
+	default:
+		return nil, NewAdminError(resp.Code, resp.Remark)
+	}
+
+	return result, nil
+}
+
+// parseExtInt64 解析响应头中的整数字段，缺失或非法时返回 0
+func parseExtInt64(extFields map[string]string, key string) int64 {
+	v, err := strconv.ParseInt(extFields[key], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// masterAddrs 返回路由中各 Broker 的 Master 地址，key 为 BrokerName
+func masterAddrs(routeData *TopicRouteData) map[string]string {
+	masters := make(map[string]string, len(routeData.BrokerDatas))
+	for _, brokerData := range routeData.BrokerDatas {
+		if addr := brokerData.BrokerAddrs["0"]; addr != "" {
+			masters[brokerData.BrokerName] = addr
+		}
+	}
+	return masters
+}
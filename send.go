@@ -0,0 +1,339 @@
+package admin
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+)
+
+// =============================================================================
+// 消息发送（SEND_MESSAGE_V2）
+// =============================================================================
+
+// defaultProducerGroup 运维工具发送消息使用的生产者组（对应 Java MixAll.CLIENT_INNER_PRODUCER_GROUP）
+const defaultProducerGroup = "CLIENT_INNER_PRODUCER"
+
+// SendStatus 发送状态
+type SendStatus string
+
+// 发送状态定义
+const (
+	SendStatusOK                SendStatus = "SEND_OK"             // 发送成功
+	SendStatusFlushDiskTimeout  SendStatus = "FLUSH_DISK_TIMEOUT"  // 刷盘超时
+	SendStatusFlushSlaveTimeout SendStatus = "FLUSH_SLAVE_TIMEOUT" // 同步 Slave 超时
+	SendStatusSlaveNotAvailable SendStatus = "SLAVE_NOT_AVAILABLE" // Slave 不可用
+)
+
+// SendResult 发送结果
+type SendResult struct {
+	Status        SendStatus   `json:"status"`        // 发送状态
+	MsgId         string       `json:"msgId"`         // 客户端消息 ID（UNIQ_KEY）
+	OffsetMsgId   string       `json:"offsetMsgId"`   // 偏移消息 ID
+	MessageQueue  MessageQueue `json:"messageQueue"`  // 目标队列
+	QueueOffset   int64        `json:"queueOffset"`   // 队列偏移
+	TransactionId string       `json:"transactionId"` // 事务 ID
+}
+
+// SendMessageOptions 发送消息选项（对应 Java mqadmin sendMessage 命令参数）
+type SendMessageOptions struct {
+	Tags          string            // 消息 Tag
+	Keys          []string          // 消息 Key
+	DelayLevel    int               // 延时等级，0 表示不延时
+	Properties    map[string]string // 用户自定义属性
+	BrokerName    string            // 指定 Broker，为空时随机选择可写队列
+	QueueId       int               // 指定队列 ID，仅在指定 BrokerName 时生效
+	ProducerGroup string            // 生产者组，为空时使用默认组
+}
+
+// SendMessage 直接通过 SEND_MESSAGE_V2 发送一条消息，无需启动完整的 Producer
+// 适用于发送探测消息、回放消息等运维场景，opts 可为 nil
+func (c *Client) SendMessage(ctx context.Context, topic string, body []byte, opts *SendMessageOptions) (*SendResult, error) {
+	if opts == nil {
+		opts = &SendMessageOptions{}
+	}
+	if topic == "" {
+		return nil, errors.New("Topic 不能为空")
+	}
+	if len(body) == 0 {
+		return nil, errors.New("消息体不能为空")
+	}
+	if opts.DelayLevel < 0 {
+		return nil, fmt.Errorf("延时等级非法: %d", opts.DelayLevel)
+	}
+
+	props := make(map[string]string, len(opts.Properties)+4)
+	for k, v := range opts.Properties {
+		props[k] = v
+	}
+	if opts.Tags != "" {
+		props[PropertyTags] = opts.Tags
+	}
+	if len(opts.Keys) > 0 {
+		props[PropertyKeys] = strings.Join(opts.Keys, " ")
+	}
+	if opts.DelayLevel > 0 {
+		props[PropertyDelayLevel] = strconv.Itoa(opts.DelayLevel)
+	}
+	props[PropertyWaitStoreMsgOK] = "true"
+	if props[PropertyUniqKey] == "" {
+		props[PropertyUniqKey] = createUniqId()
+	}
+
+	req := &sendRequest{
+		topic:         topic,
+		body:          body,
+		properties:    props,
+		producerGroup: opts.ProducerGroup,
+	}
+
+	if opts.BrokerName == "" {
+		return c.sendMessage(ctx, req)
+	}
+
+	queues, err := c.writableQueues(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+	for _, queue := range queues {
+		if queue.mq.BrokerName == opts.BrokerName && queue.mq.QueueId == opts.QueueId {
+			return c.sendMessageToQueue(ctx, queue, req)
+		}
+	}
+	return nil, fmt.Errorf("Topic %s 在 %s 上没有可写队列 %d", topic, opts.BrokerName, opts.QueueId)
+}
+
+// rateLimiter 按固定间隔放行请求的简单限流器
+type rateLimiter struct {
+	ticker *time.Ticker
+	first  bool
+}
+
+// newRateLimiter 创建每秒最多放行 ratePerSecond 次的限流器，<=0 表示不限速
+func newRateLimiter(ratePerSecond int) *rateLimiter {
+	if ratePerSecond <= 0 {
+		return &rateLimiter{}
+	}
+	return &rateLimiter{
+		ticker: time.NewTicker(time.Second / time.Duration(ratePerSecond)),
+		first:  true,
+	}
+}
+
+// wait 等待下一次放行，首次调用立即返回
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.ticker == nil || l.first {
+		l.first = false
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.ticker.C:
+		return nil
+	}
+}
+
+// stop 释放限流器资源
+func (l *rateLimiter) stop() {
+	if l.ticker != nil {
+		l.ticker.Stop()
+	}
+}
+
+// publishQueue 可写队列及其 Master 地址
+type publishQueue struct {
+	mq         MessageQueue
+	brokerAddr string
+}
+
+// sendRequest 发送请求参数
+type sendRequest struct {
+	topic          string            // 目标 Topic
+	body           []byte            // 消息体
+	properties     map[string]string // 消息属性
+	producerGroup  string            // 生产者组
+	reconsumeTimes int               // 重新消费次数
+}
+
+// writableQueues 获取 Topic 在各 Master 上的可写队列
+func (c *Client) writableQueues(ctx context.Context, topic string) ([]*publishQueue, error) {
+	routeData, err := c.ExamineTopicRouteInfo(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	masters := masterAddrs(routeData)
+
+	var queues []*publishQueue
+	for _, queueData := range routeData.QueueDatas {
+		if !queueData.Perm.Writable() {
+			continue
+		}
+		brokerAddr, ok := masters[queueData.BrokerName]
+		if !ok {
+			continue
+		}
+		for i := 0; i < queueData.WriteQueueNums; i++ {
+			queues = append(queues, &publishQueue{
+				mq:         MessageQueue{Topic: topic, BrokerName: queueData.BrokerName, QueueId: i},
+				brokerAddr: brokerAddr,
+			})
+		}
+	}
+
+	if len(queues) == 0 {
+		return nil, fmt.Errorf("Topic %s 没有可写队列", topic)
+	}
+
+	return queues, nil
+}
+
+// sendMessage 随机选择一个可写队列发送消息
+func (c *Client) sendMessage(ctx context.Context, req *sendRequest) (*SendResult, error) {
+	queues, err := c.writableQueues(ctx, req.topic)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.sendMessageToQueue(ctx, queues[rand.IntN(len(queues))], req)
+}
+
+// sendMessageToQueue 向指定队列发送消息
+func (c *Client) sendMessageToQueue(ctx context.Context, queue *publishQueue, req *sendRequest) (*SendResult, error) {
+	producerGroup := req.producerGroup
+	if producerGroup == "" {
+		producerGroup = defaultProducerGroup
+	}
+
+	// 请求头字段名与 Java SendMessageRequestHeaderV2 保持一致
+	extFields := map[string]string{
+		"a": producerGroup,
+		"b": req.topic,
+		"c": "TBW102",
+		"d": "4",
+		"e": fmt.Sprintf("%d", queue.mq.QueueId),
+		"f": "0",
+		"g": fmt.Sprintf("%d", time.Now().UnixMilli()),
+		"h": "0",
+		"i": messageProperties2String(req.properties),
+		"j": fmt.Sprintf("%d", req.reconsumeTimes),
+		"k": "false",
+		"m": "false",
+		"n": queue.mq.BrokerName,
+	}
+	cmd := remoting.NewRequest(remoting.SendMessageV2, extFields)
+	cmd.Body = req.body
+
+	resp, err := c.invokeBroker(ctx, queue.brokerAddr, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SendResult{
+		MsgId:         req.properties[PropertyUniqKey],
+		OffsetMsgId:   resp.ExtFields["msgId"],
+		MessageQueue:  queue.mq,
+		TransactionId: resp.ExtFields["transactionId"],
+	}
+
+	switch resp.Code {
+	case remoting.Success:
+		result.Status = SendStatusOK
+	case remoting.FlushDiskTimeout:
+		result.Status = SendStatusFlushDiskTimeout
+	case remoting.FlushSlaveTimeout:
+		result.Status = SendStatusFlushSlaveTimeout
+	case remoting.SlaveNotAvailable:
+		result.Status = SendStatusSlaveNotAvailable
+	default:
+		return nil, NewAdminError(resp.Code, resp.Remark)
+	}
+
+	if queueId, err := strconv.Atoi(resp.ExtFields["queueId"]); err == nil {
+		result.MessageQueue.QueueId = queueId
+	}
+	result.QueueOffset = parseExtInt64(resp.ExtFields, "queueOffset")
+	if result.MsgId == "" {
+		result.MsgId = result.OffsetMsgId
+	}
+
+	return result, nil
+}
+
+// =============================================================================
+// 消息唯一 ID（对应 Java MessageClientIDSetter）
+// =============================================================================
+
+// uniqIdGenerator 唯一 ID 生成器
+// 格式：IP(4) + PID(2) + 随机标识(4) + 距本月开始的毫秒数(4) + 计数器(2)
+type uniqIdGenerator struct {
+	mu         sync.Mutex
+	prefix     []byte
+	counter    uint16
+	startTime  int64
+	nextSwitch int64
+}
+
+var defaultUniqIdGenerator = newUniqIdGenerator()
+
+// newUniqIdGenerator 创建唯一 ID 生成器
+func newUniqIdGenerator() *uniqIdGenerator {
+	prefix := make([]byte, 0, 10)
+	prefix = append(prefix, localIPv4()...)
+	prefix = binary.BigEndian.AppendUint16(prefix, uint16(os.Getpid()))
+	prefix = binary.BigEndian.AppendUint32(prefix, rand.Uint32())
+	return &uniqIdGenerator{prefix: prefix}
+}
+
+// next 生成下一个唯一 ID
+func (g *uniqIdGenerator) next() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	if now.UnixMilli() >= g.nextSwitch {
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+		g.startTime = monthStart.UnixMilli()
+		g.nextSwitch = monthStart.AddDate(0, 1, 0).UnixMilli()
+	}
+	g.counter++
+
+	buf := make([]byte, 0, len(g.prefix)+6)
+	buf = append(buf, g.prefix...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(now.UnixMilli()-g.startTime))
+	buf = binary.BigEndian.AppendUint16(buf, g.counter)
+	return strings.ToUpper(hex.EncodeToString(buf))
+}
+
+// createUniqId 生成消息唯一 ID（UNIQ_KEY）
+func createUniqId() string {
+	return defaultUniqIdGenerator.next()
+}
+
+// localIPv4 返回本机第一个非回环 IPv4 地址，找不到时返回 127.0.0.1
+func localIPv4() net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err == nil {
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLoopback() {
+				continue
+			}
+			if ip := ipNet.IP.To4(); ip != nil {
+				return ip
+			}
+		}
+	}
+	return net.IPv4(127, 0, 0, 1).To4()
+}
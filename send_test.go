@@ -0,0 +1,185 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// 消息发送单元测试
+// =============================================================================
+
+// TestCreateUniqId 测试生成的唯一 ID 可以还原发送时间
+func TestCreateUniqId(t *testing.T) {
+	first := createUniqId()
+	second := createUniqId()
+	if len(first) != 32 || first == second {
+		t.Fatalf("唯一 ID 格式错误: %s %s", first, second)
+	}
+
+	sent, err := uniqKeyNearlyTime(first, time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("解析唯一 ID 失败: %v", err)
+	}
+	if d := time.Since(sent); d < 0 || d > time.Minute {
+		t.Errorf("还原的发送时间偏差过大: %v", sent)
+	}
+}
+
+// TestSendMessage_InvalidArgs 测试发送参数校验
+func TestSendMessage_InvalidArgs(t *testing.T) {
+	client := &Client{}
+	ctx := t.Context()
+
+	if _, err := client.SendMessage(ctx, "", []byte("x"), nil); err == nil {
+		t.Error("Topic 为空时应返回错误")
+	}
+	if _, err := client.SendMessage(ctx, "TopicTest", nil, nil); err == nil {
+		t.Error("消息体为空时应返回错误")
+	}
+	if _, err := client.SendMessage(ctx, "TopicTest", []byte("x"), &SendMessageOptions{DelayLevel: -1}); err == nil {
+		t.Error("延时等级为负时应返回错误")
+	}
+}
+
+// TestSendMessage 测试 SEND_MESSAGE_V2 请求头、属性编码与响应码到发送状态的映射
+func TestSendMessage(t *testing.T) {
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Handle(remoting.GetRouteInfoByTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"queueDatas":[{"brokerName":"broker-a","readQueueNums":2,"writeQueueNums":2,"perm":6}],` +
+			`"brokerDatas":[{"cluster":"DefaultCluster","brokerName":"broker-a","brokerAddrs":{0:"` + srv.Addr + `"}}]}`))
+	})
+	var respCode atomic.Int32
+	srv.Handle(remoting.SendMessageV2, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		code := int(respCode.Load())
+		if code != remoting.Success && code != remoting.FlushDiskTimeout && code != remoting.FlushSlaveTimeout && code != remoting.SlaveNotAvailable {
+			return remotingtest.Error(code, "message illegal")
+		}
+		resp := remotingtest.Error(code, "")
+		resp.ExtFields = map[string]string{"msgId": "0A00000100002A9F0000000000000064", "queueId": req.ExtFields["e"], "queueOffset": "42"}
+		return resp
+	})
+	client := newFakeClient(t, srv)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	opts := &SendMessageOptions{
+		Tags:       "TagA",
+		Keys:       []string{"k1", "k2"},
+		DelayLevel: 3,
+		Properties: map[string]string{PropertyUniqKey: "7F00000100002A9F0000000000000001", "custom": "v"},
+		BrokerName: "broker-a",
+		QueueId:    1,
+	}
+	before := time.Now().UnixMilli()
+	result, err := client.SendMessage(ctx, "TopicA", []byte("hello"), opts)
+	if err != nil {
+		t.Fatalf("发送消息失败: %v", err)
+	}
+	want := SendResult{
+		Status:       SendStatusOK,
+		MsgId:        "7F00000100002A9F0000000000000001",
+		OffsetMsgId:  "0A00000100002A9F0000000000000064",
+		MessageQueue: MessageQueue{Topic: "TopicA", BrokerName: "broker-a", QueueId: 1},
+		QueueOffset:  42,
+	}
+	if *result != want {
+		t.Errorf("发送结果错误: %+v", result)
+	}
+
+	req := srv.Requests(remoting.SendMessageV2)[0]
+	if string(req.Body) != "hello" {
+		t.Errorf("消息体错误: %s", req.Body)
+	}
+	headers := map[string]string{
+		"a": defaultProducerGroup, "b": "TopicA", "c": "TBW102", "d": "4", "e": "1", "f": "0",
+		"h": "0", "j": "0", "k": "false", "m": "false", "n": "broker-a",
+		"i": "DELAY\x013\x02KEYS\x01k1 k2\x02TAGS\x01TagA\x02UNIQ_KEY\x017F00000100002A9F0000000000000001\x02WAIT\x01true\x02custom\x01v\x02",
+	}
+	for key, value := range headers {
+		if got := req.ExtFields[key]; got != value {
+			t.Errorf("请求头 %s 应为 %q, got %q", key, value, got)
+		}
+	}
+	if born, _ := strconv.ParseInt(req.ExtFields["g"], 10, 64); born < before || born > time.Now().UnixMilli() {
+		t.Errorf("请求头 g 应为发送时间, got %s", req.ExtFields["g"])
+	}
+
+	// 未指定 UNIQ_KEY 时自动生成
+	result, err = client.SendMessage(ctx, "TopicA", []byte("hello"), &SendMessageOptions{ProducerGroup: "PG"})
+	if err != nil || len(result.MsgId) != 32 || result.MsgId == want.MsgId {
+		t.Errorf("应生成 UNIQ_KEY: %v %+v", err, result)
+	}
+	if w := srv.Requests(remoting.SendMessageV2)[1].ExtFields; w["a"] != "PG" || string2MessageProperties(w["i"])[PropertyUniqKey] != result.MsgId {
+		t.Errorf("请求头错误: %v", w)
+	}
+
+	for code, status := range map[int]SendStatus{
+		remoting.FlushDiskTimeout:  SendStatusFlushDiskTimeout,
+		remoting.FlushSlaveTimeout: SendStatusFlushSlaveTimeout,
+		remoting.SlaveNotAvailable: SendStatusSlaveNotAvailable,
+	} {
+		respCode.Store(int32(code))
+		result, err := client.SendMessage(ctx, "TopicA", []byte("hello"), opts)
+		if err != nil || result.Status != status || result.QueueOffset != 42 {
+			t.Errorf("响应码 %d 应映射为 %s: %v %+v", code, status, err, result)
+		}
+	}
+
+	respCode.Store(remoting.SystemError)
+	_, err = client.SendMessage(ctx, "TopicA", []byte("hello"), opts)
+	var adminErr *AdminError
+	if !errors.As(err, &adminErr) || adminErr.Code != remoting.SystemError {
+		t.Errorf("其他响应码应返回 AdminError, got %v", err)
+	}
+	if _, err := client.SendMessage(ctx, "TopicA", []byte("hello"), &SendMessageOptions{BrokerName: "broker-a", QueueId: 2}); err == nil {
+		t.Error("不存在的队列应返回错误")
+	}
+}
+
+// =============================================================================
+// 消息发送集成测试
+// =============================================================================
+
+// TestIntegration_SendMessage 测试发送消息并按唯一键查询
+func TestIntegration_SendMessage(t *testing.T) {
+	skipIfNoRocketMQ(t)
+	client := getTestClient(t)
+	defer client.Close()
+
+	topic, cleanup := createTestTopic(t, client, "SEND")
+	defer cleanup()
+
+	ctx, cancel := testContext()
+	defer cancel()
+
+	result, err := client.SendMessage(ctx, topic, []byte("hello"), &SendMessageOptions{
+		Tags:       "TagA",
+		Keys:       []string{"key1"},
+		Properties: map[string]string{"probe": "true"},
+	})
+	if err != nil {
+		t.Fatalf("发送消息失败: %v", err)
+	}
+	t.Logf("发送结果: %s msgId=%s queue=%s@%d offset=%d",
+		result.Status, result.MsgId, result.MessageQueue.BrokerName, result.MessageQueue.QueueId, result.QueueOffset)
+
+	// 指定队列发送
+	result, err = client.SendMessage(ctx, topic, []byte("hello"), &SendMessageOptions{
+		BrokerName: result.MessageQueue.BrokerName,
+		QueueId:    1,
+	})
+	if err != nil {
+		t.Fatalf("指定队列发送消息失败: %v", err)
+	}
+	if result.MessageQueue.QueueId != 1 {
+		t.Errorf("队列 ID 应为 1, got %d", result.MessageQueue.QueueId)
+	}
+}
@@ -0,0 +1,774 @@
+package admin
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+)
+
+// =============================================================================
+// 集群元数据快照
+// =============================================================================
+
+// SnapshotVersion 当前快照格式版本，读取更高版本的快照会返回错误
+const SnapshotVersion = 1
+
+// 快照额外包含的资源类型，其余类型见 ResourceKind
+const (
+	ResourceOffset       ResourceKind = "offset"
+	ResourceBrokerConfig ResourceKind = "brokerConfig"
+)
+
+// defaultKVNamespaces 未指定时导出的 KV 命名空间（NameServer 不支持列出命名空间）
+var defaultKVNamespaces = []string{"ORDER_TOPIC_CONFIG"}
+
+// brokerIdentityKeys 标识 Broker 身份和部署位置的配置，恢复时不会覆盖
+var brokerIdentityKeys = map[string]bool{
+	"brokerName":             true,
+	"brokerClusterName":      true,
+	"brokerId":               true,
+	"brokerIP1":              true,
+	"brokerIP2":              true,
+	"listenPort":             true,
+	"haListenPort":           true,
+	"namesrvAddr":            true,
+	"rocketmqHome":           true,
+	"storePathRootDir":       true,
+	"storePathCommitLog":     true,
+	"storePathConsumerQueue": true,
+	"storePathIndex":         true,
+	"storeCheckpoint":        true,
+	"abortFile":              true,
+}
+
+// MetadataSnapshot 集群元数据快照（对应 Java exportMetadata/exportConfigs 的导出内容）
+type MetadataSnapshot struct {
+	// Version 快照格式版本
+	Version int `json:"version"`
+
+	// CreatedAt 导出时间
+	CreatedAt time.Time `json:"createdAt"`
+
+	// NameServers 导出时使用的 NameServer 地址
+	NameServers []string `json:"nameServers"`
+
+	// Cluster 导出的集群，为空表示全部集群
+	Cluster string `json:"cluster,omitempty"`
+
+	// Brokers 各 Master Broker 的元数据
+	Brokers []*BrokerSnapshot `json:"brokers"`
+
+	// KV NameServer KV 配置 key: 命名空间
+	KV map[string]map[string]string `json:"kv,omitempty"`
+
+	// Warnings 导出时跳过的内容，如 4.x Broker 不支持 ACL 2.0 接口
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// BrokerSnapshot 单个 Master Broker 的元数据
+type BrokerSnapshot struct {
+	BrokerName string                              `json:"brokerName"`
+	Cluster    string                              `json:"cluster"`
+	Addr       string                              `json:"addr"`
+	Config     map[string]string                   `json:"config,omitempty"`
+	Topics     map[string]*TopicConfig             `json:"topics,omitempty"`
+	Groups     map[string]*SubscriptionGroupConfig `json:"groups,omitempty"`
+	Offsets    map[string]map[int]int64            `json:"offsets,omitempty"` // key: topic@group
+	Users      []UserInfo                          `json:"users,omitempty"`
+	Acls       []AclInfo                           `json:"acls,omitempty"`
+}
+
+// SnapshotFilter 快照导出/恢复的过滤条件
+//
+// Include/Exclude 为 path.Match 通配符，匹配资源名称：Topic、订阅组、用户名、
+// ACL 主体、Broker 配置项和 KV（namespace/key 或 namespace）；消费 Offset
+// 同时匹配 Topic、订阅组和 topic@group。
+type SnapshotFilter struct {
+	// Kinds 包含的资源类型，为空时包含全部类型
+	Kinds []ResourceKind
+
+	// Include 包含的名称，为空时包含全部
+	Include []string
+
+	// Exclude 排除的名称，优先于 Include
+	Exclude []string
+
+	// IncludeSystem 包含系统 Topic 和系统订阅组
+	IncludeSystem bool
+}
+
+// kindEnabled 判断资源类型是否包含在内
+func (f *SnapshotFilter) kindEnabled(kind ResourceKind) bool {
+	if len(f.Kinds) == 0 {
+		return true
+	}
+	for _, k := range f.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// match 判断名称是否通过过滤，任一名称命中 Include 即包含，任一名称命中 Exclude 即排除
+func (f *SnapshotFilter) match(names ...string) bool {
+	matchAny := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			for _, name := range names {
+				if ok, _ := path.Match(pattern, name); ok {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if matchAny(f.Exclude) {
+		return false
+	}
+	return len(f.Include) == 0 || matchAny(f.Include)
+}
+
+// matchTopic 判断 Topic 是否包含在内
+func (f *SnapshotFilter) matchTopic(topic string, builtin map[string]bool) bool {
+	if !f.IncludeSystem && (IsSystemTopic(topic) || builtin[topic]) {
+		return false
+	}
+	return f.match(topic)
+}
+
+// matchGroup 判断订阅组是否包含在内
+func (f *SnapshotFilter) matchGroup(group string) bool {
+	if !f.IncludeSystem && isBuiltinGroup(group) {
+		return false
+	}
+	return f.match(group)
+}
+
+// matchOffset 判断 topic@group 的消费 Offset 是否包含在内
+func (f *SnapshotFilter) matchOffset(key string) bool {
+	topic, group, ok := strings.Cut(key, "@")
+	if !ok {
+		return false
+	}
+	if !f.IncludeSystem && isBuiltinGroup(group) {
+		return false
+	}
+	return f.match(key, topic, group)
+}
+
+// ExportOptions 导出选项
+type ExportOptions struct {
+	SnapshotFilter
+
+	// Cluster 导出的集群，为空时导出全部集群
+	Cluster string
+
+	// KVNamespaces 导出的 KV 命名空间，为空时导出 ORDER_TOPIC_CONFIG
+	KVNamespaces []string
+}
+
+// ExportMetadata 导出集群元数据快照
+//
+// 依次读取每个 Master 的 Broker 配置、Topic、订阅组、消费 Offset、ACL 用户和
+// 策略，以及 NameServer 的 KV 配置。Broker 拒绝 ACL 请求（如 4.x 版本）时
+// 记录到 Warnings 并继续导出。
+func (c *Client) ExportMetadata(ctx context.Context, opts ExportOptions) (*MetadataSnapshot, error) {
+	clusterInfo, err := c.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取集群信息失败: %w", err)
+	}
+	brokers, err := planBrokers(clusterInfo, opts.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	builtin := builtinTopics(clusterInfo)
+
+	snap := &MetadataSnapshot{
+		Version:     SnapshotVersion,
+		CreatedAt:   time.Now(),
+		NameServers: c.GetNameServerAddressList(),
+		Cluster:     opts.Cluster,
+	}
+	f := &opts.SnapshotFilter
+
+	for _, name := range sortedKeys(brokers) {
+		addr := brokers[name]
+		b := &BrokerSnapshot{BrokerName: name, Cluster: clusterInfo.BrokerAddrTable[name].Cluster, Addr: addr}
+
+		if f.kindEnabled(ResourceBrokerConfig) {
+			config, err := c.GetBrokerConfig(ctx, addr)
+			if err != nil {
+				return nil, fmt.Errorf("获取 %s 的 Broker 配置失败: %w", name, err)
+			}
+			b.Config = make(map[string]string)
+			for k, v := range config {
+				if f.match(k) {
+					b.Config[k] = v
+				}
+			}
+		}
+
+		if f.kindEnabled(ResourceTopic) {
+			topics, err := c.GetAllTopicConfig(ctx, addr)
+			if err != nil {
+				return nil, fmt.Errorf("获取 %s 的 Topic 配置失败: %w", name, err)
+			}
+			b.Topics = make(map[string]*TopicConfig)
+			for topic, config := range topics {
+				if f.matchTopic(topic, builtin) {
+					b.Topics[topic] = config
+				}
+			}
+		}
+
+		if f.kindEnabled(ResourceGroup) {
+			groups, err := c.GetAllSubscriptionGroup(ctx, addr)
+			if err != nil {
+				return nil, fmt.Errorf("获取 %s 的订阅组失败: %w", name, err)
+			}
+			b.Groups = make(map[string]*SubscriptionGroupConfig)
+			for group, config := range groups {
+				if f.matchGroup(group) {
+					b.Groups[group] = config
+				}
+			}
+		}
+
+		if f.kindEnabled(ResourceOffset) {
+			offsets, err := c.GetAllConsumerOffset(ctx, addr)
+			if err != nil {
+				return nil, fmt.Errorf("获取 %s 的消费 Offset 失败: %w", name, err)
+			}
+			b.Offsets = make(map[string]map[int]int64)
+			for key, queues := range offsets {
+				if f.matchOffset(key) {
+					b.Offsets[key] = queues
+				}
+			}
+		}
+
+		if f.kindEnabled(ResourceUser) {
+			list, err := c.ListUser(ctx, addr)
+			if err := snapshotWarning(snap, name, "用户", err); err != nil {
+				return nil, err
+			}
+			if list != nil {
+				for _, user := range list.Users {
+					if f.match(user.Username) {
+						b.Users = append(b.Users, user)
+					}
+				}
+			}
+		}
+
+		if f.kindEnabled(ResourceAcl) {
+			list, err := c.ListAcl(ctx, addr)
+			if err := snapshotWarning(snap, name, "ACL", err); err != nil {
+				return nil, err
+			}
+			if list != nil {
+				for _, acl := range list.Acls {
+					if f.match(acl.Subject) {
+						b.Acls = append(b.Acls, acl)
+					}
+				}
+			}
+		}
+
+		snap.Brokers = append(snap.Brokers, b)
+	}
+
+	if f.kindEnabled(ResourceKV) {
+		namespaces := opts.KVNamespaces
+		if len(namespaces) == 0 {
+			namespaces = defaultKVNamespaces
+		}
+		snap.KV = make(map[string]map[string]string)
+		for _, namespace := range namespaces {
+			table, err := c.getKVNamespace(ctx, namespace)
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range table {
+				if f.match(namespace+"/"+key, namespace) {
+					if snap.KV[namespace] == nil {
+						snap.KV[namespace] = make(map[string]string)
+					}
+					snap.KV[namespace][key] = value
+				}
+			}
+		}
+	}
+
+	return snap, nil
+}
+
+// snapshotWarning Broker 拒绝请求时记录警告，其他错误原样返回
+func snapshotWarning(snap *MetadataSnapshot, brokerName, what string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var adminErr *AdminError
+	if errors.As(err, &adminErr) {
+		snap.Warnings = append(snap.Warnings, fmt.Sprintf("%s: 跳过%s: %v", brokerName, what, err))
+		return nil
+	}
+	return fmt.Errorf("获取 %s 的%s失败: %w", brokerName, what, err)
+}
+
+// builtinTopics 返回 Broker 自动创建的集群名和 Broker 名同名 Topic
+func builtinTopics(clusterInfo *ClusterInfo) map[string]bool {
+	builtin := make(map[string]bool)
+	for clusterName, brokerNames := range clusterInfo.ClusterAddrTable {
+		builtin[clusterName] = true
+		for _, name := range brokerNames {
+			builtin[name] = true
+		}
+	}
+	return builtin
+}
+
+// getKVNamespace 获取命名空间下的 KV，命名空间不存在时返回空
+func (c *Client) getKVNamespace(ctx context.Context, namespace string) (map[string]string, error) {
+	table, err := c.GetKVListByNamespace(ctx, namespace)
+	if err != nil {
+		// 命名空间不存在时 NameServer 返回 QUERY_NOT_FOUND
+		var adminErr *AdminError
+		if errors.As(err, &adminErr) && adminErr.Code == remoting.QueryNotFound {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("获取命名空间 %s 的 KV 失败: %w", namespace, err)
+	}
+	return table, nil
+}
+
+// WriteSnapshot 将快照以 gzip 压缩的 JSON 写入 w
+func WriteSnapshot(w io.Writer, snap *MetadataSnapshot) error {
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(snap); err != nil {
+		return fmt.Errorf("写入快照失败: %w", err)
+	}
+	return zw.Close()
+}
+
+// ReadSnapshot 读取快照，支持 gzip 压缩和未压缩的 JSON
+func ReadSnapshot(r io.Reader) (*MetadataSnapshot, error) {
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("读取快照失败: %w", err)
+		}
+		defer zr.Close()
+		src = zr
+	}
+
+	var snap MetadataSnapshot
+	if err := json.NewDecoder(src).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("解析快照失败: %w", err)
+	}
+	if err := snap.checkVersion(); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// checkVersion 检查快照版本
+func (s *MetadataSnapshot) checkVersion() error {
+	if s.Version <= 0 || s.Version > SnapshotVersion {
+		return fmt.Errorf("不支持的快照版本 %d，当前支持 %d", s.Version, SnapshotVersion)
+	}
+	return nil
+}
+
+// =============================================================================
+// 快照恢复
+// =============================================================================
+
+// ConflictPolicy 目标已存在不同配置时的处理方式
+type ConflictPolicy string
+
+const (
+	ConflictSkip      ConflictPolicy = "skip"      // 保留目标现有配置（默认）
+	ConflictOverwrite ConflictPolicy = "overwrite" // 使用快照覆盖
+	ConflictFail      ConflictPolicy = "fail"      // 存在任何冲突时不做修改并返回错误
+)
+
+// RestoreOptions 恢复选项
+type RestoreOptions struct {
+	SnapshotFilter
+
+	// Cluster 目标集群，为空时使用全部集群的 Master
+	Cluster string
+
+	// BrokerMapping 快照 Broker 名到目标 Broker 名的映射，未配置时按同名匹配
+	BrokerMapping map[string]string
+
+	// Conflict 冲突处理方式，默认 ConflictSkip
+	Conflict ConflictPolicy
+
+	// DryRun 只生成恢复报告，不修改集群
+	DryRun bool
+}
+
+// RestoreStatus 恢复状态
+type RestoreStatus string
+
+const (
+	RestoreCreated   RestoreStatus = "created"   // 目标不存在，已创建
+	RestoreUpdated   RestoreStatus = "updated"   // 目标存在不同配置，已覆盖
+	RestoreUnchanged RestoreStatus = "unchanged" // 目标配置相同
+	RestoreSkipped   RestoreStatus = "skipped"   // 存在冲突，未修改
+	RestoreFailed    RestoreStatus = "failed"    // 执行失败
+)
+
+// RestoreItem 单个资源的恢复结果
+type RestoreItem struct {
+	Kind   ResourceKind  `json:"kind"`
+	Broker string        `json:"broker,omitempty"` // 目标 Broker 名，KV 为空
+	Name   string        `json:"name"`
+	Status RestoreStatus `json:"status"`
+	Reason string        `json:"reason,omitempty"`
+}
+
+// RestoreReport 恢复报告
+type RestoreReport struct {
+	DryRun bool          `json:"dryRun"`
+	Items  []RestoreItem `json:"items"`
+}
+
+// Count 返回指定状态的资源数量
+func (r *RestoreReport) Count(status RestoreStatus) int {
+	n := 0
+	for _, item := range r.Items {
+		if item.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// restoreOp 恢复操作
+type restoreOp struct {
+	item  RestoreItem
+	apply func(ctx context.Context) error
+}
+
+// restoreTarget 目标 Broker 现状
+type restoreTarget struct {
+	addr    string
+	config  map[string]string
+	topics  map[string]*TopicConfig
+	groups  map[string]*SubscriptionGroupConfig
+	offsets map[string]map[int]int64
+	users   map[string]*UserInfo
+	acls    map[string]*AclInfo
+}
+
+// RestoreMetadata 将快照恢复到当前客户端连接的集群（可以与导出集群不同）
+//
+// 恢复顺序为 KV、Broker 配置、Topic、订阅组、消费 Offset、用户、ACL。
+// Broker 身份和存储路径相关的配置项不会恢复；快照中的用户密码通常已加密
+// 或为空，这类用户可能创建失败，需要单独设置密码。消费 Offset 只在目标
+// 与导出集群使用同一份存储数据时有意义，跨集群迁移请排除 ResourceOffset。
+func (c *Client) RestoreMetadata(ctx context.Context, snap *MetadataSnapshot, opts RestoreOptions) (*RestoreReport, error) {
+	if err := snap.checkVersion(); err != nil {
+		return nil, err
+	}
+	if opts.Conflict == "" {
+		opts.Conflict = ConflictSkip
+	}
+
+	clusterInfo, err := c.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取集群信息失败: %w", err)
+	}
+	brokers, err := planBrokers(clusterInfo, opts.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先确认快照中的 Broker 都能找到目标，避免恢复到一半才失败
+	targetNames := make(map[string]string, len(snap.Brokers))
+	for _, b := range snap.Brokers {
+		name := b.BrokerName
+		if mapped, ok := opts.BrokerMapping[name]; ok {
+			name = mapped
+		}
+		if _, ok := brokers[name]; !ok {
+			return nil, fmt.Errorf("快照中的 Broker %s 在目标集群中不存在，请通过 BrokerMapping 指定", b.BrokerName)
+		}
+		targetNames[b.BrokerName] = name
+	}
+
+	ops, err := c.planRestore(ctx, snap, &opts, brokers, targetNames, builtinTopics(clusterInfo))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RestoreReport{DryRun: opts.DryRun}
+	conflicts := 0
+	for _, op := range ops {
+		if op.item.Status == RestoreSkipped {
+			conflicts++
+		}
+	}
+	if opts.Conflict == ConflictFail && conflicts > 0 {
+		for _, op := range ops {
+			report.Items = append(report.Items, op.item)
+		}
+		return report, fmt.Errorf("目标集群存在 %d 处冲突，未做任何修改", conflicts)
+	}
+
+	var errs []error
+	for _, op := range ops {
+		if !opts.DryRun && op.apply != nil {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			if err := op.apply(ctx); err != nil {
+				op.item.Status, op.item.Reason = RestoreFailed, err.Error()
+				errs = append(errs, fmt.Errorf("%s %s %s: %w", op.item.Kind, op.item.Broker, op.item.Name, err))
+			}
+		}
+		report.Items = append(report.Items, op.item)
+	}
+
+	if len(errs) > 0 {
+		return report, fmt.Errorf("恢复元数据失败: %w", errors.Join(errs...))
+	}
+	return report, nil
+}
+
+// planRestore 对比快照与目标现状，生成按恢复顺序排列的操作
+func (c *Client) planRestore(ctx context.Context, snap *MetadataSnapshot, opts *RestoreOptions,
+	brokers, targetNames map[string]string, builtin map[string]bool) ([]*restoreOp, error) {
+
+	f := &opts.SnapshotFilter
+
+	// 只读取快照中实际包含的资源类型，避免 4.x Broker 因不支持 ACL 接口而失败
+	kinds := make(map[ResourceKind]bool)
+	for _, b := range snap.Brokers {
+		kinds[ResourceBrokerConfig] = kinds[ResourceBrokerConfig] || len(b.Config) > 0
+		kinds[ResourceTopic] = kinds[ResourceTopic] || len(b.Topics) > 0
+		kinds[ResourceGroup] = kinds[ResourceGroup] || len(b.Groups) > 0
+		kinds[ResourceOffset] = kinds[ResourceOffset] || len(b.Offsets) > 0
+		kinds[ResourceUser] = kinds[ResourceUser] || len(b.Users) > 0
+		kinds[ResourceAcl] = kinds[ResourceAcl] || len(b.Acls) > 0
+	}
+	for kind := range kinds {
+		kinds[kind] = kinds[kind] && f.kindEnabled(kind)
+	}
+
+	targets := make(map[string]*restoreTarget)
+	for _, b := range snap.Brokers {
+		name := targetNames[b.BrokerName]
+		if targets[name] != nil {
+			continue
+		}
+		target, err := c.loadRestoreTarget(ctx, brokers[name], kinds)
+		if err != nil {
+			return nil, fmt.Errorf("读取目标 Broker %s 失败: %w", name, err)
+		}
+		targets[name] = target
+	}
+
+	var ops []*restoreOp
+	add := func(kind ResourceKind, broker, name string, exists, differ bool, apply func(ctx context.Context) error) {
+		op := &restoreOp{item: RestoreItem{Kind: kind, Broker: broker, Name: name}}
+		switch {
+		case !exists:
+			op.item.Status, op.apply = RestoreCreated, apply
+		case !differ:
+			op.item.Status = RestoreUnchanged
+		case opts.Conflict == ConflictOverwrite:
+			op.item.Status, op.apply = RestoreUpdated, apply
+		default:
+			op.item.Status, op.item.Reason = RestoreSkipped, "目标已存在不同配置"
+		}
+		ops = append(ops, op)
+	}
+
+	if f.kindEnabled(ResourceKV) {
+		for _, namespace := range sortedKeys(snap.KV) {
+			live, err := c.getKVNamespace(ctx, namespace)
+			if err != nil {
+				return nil, err
+			}
+			for _, key := range sortedKeys(snap.KV[namespace]) {
+				if !f.match(namespace+"/"+key, namespace) {
+					continue
+				}
+				value := snap.KV[namespace][key]
+				current, exists := live[key]
+				add(ResourceKV, "", namespace+"/"+key, exists, current != value, func(ctx context.Context) error {
+					return c.PutKVConfig(ctx, namespace, key, value)
+				})
+			}
+		}
+	}
+
+	for _, kind := range []ResourceKind{ResourceBrokerConfig, ResourceTopic, ResourceGroup, ResourceOffset, ResourceUser, ResourceAcl} {
+		if !kinds[kind] {
+			continue
+		}
+		for _, b := range snap.Brokers {
+			name := targetNames[b.BrokerName]
+			target := targets[name]
+			addr := target.addr
+
+			switch kind {
+			case ResourceBrokerConfig:
+				for _, key := range sortedKeys(b.Config) {
+					if brokerIdentityKeys[key] || !f.match(key) {
+						continue
+					}
+					value := b.Config[key]
+					current, exists := target.config[key]
+					// Broker 配置项总是存在，值不同视为冲突
+					add(kind, name, key, true, !exists || current != value, func(ctx context.Context) error {
+						return c.UpdateBrokerConfig(ctx, addr, map[string]string{key: value})
+					})
+				}
+
+			case ResourceTopic:
+				for _, topic := range sortedKeys(b.Topics) {
+					if !f.matchTopic(topic, builtin) {
+						continue
+					}
+					config := *b.Topics[topic]
+					config.TopicName = topic
+					current, exists := target.topics[topic]
+					add(kind, name, topic, exists, exists && len(topicChanges(&config, current)) > 0, func(ctx context.Context) error {
+						return c.CreateTopic(ctx, addr, config)
+					})
+				}
+
+			case ResourceGroup:
+				for _, group := range sortedKeys(b.Groups) {
+					if !f.matchGroup(group) {
+						continue
+					}
+					config := *b.Groups[group]
+					config.GroupName = group
+					current, exists := target.groups[group]
+					add(kind, name, group, exists, exists && len(groupChanges(&config, current)) > 0, func(ctx context.Context) error {
+						return c.CreateSubscriptionGroup(ctx, addr, config)
+					})
+				}
+
+			case ResourceOffset:
+				for _, key := range sortedKeys(b.Offsets) {
+					if !f.matchOffset(key) {
+						continue
+					}
+					topic, group, _ := strings.Cut(key, "@")
+					queues := b.Offsets[key]
+					queueIds := make([]int, 0, len(queues))
+					for queueId := range queues {
+						queueIds = append(queueIds, queueId)
+					}
+					sort.Ints(queueIds)
+					for _, queueId := range queueIds {
+						offset := queues[queueId]
+						current, exists := target.offsets[key][queueId]
+						add(kind, name, key+"@"+strconv.Itoa(queueId), exists, current != offset, func(ctx context.Context) error {
+							return c.UpdateConsumeOffset(ctx, addr, group, topic, queueId, offset)
+						})
+					}
+				}
+
+			case ResourceUser:
+				for _, user := range b.Users {
+					if !f.match(user.Username) {
+						continue
+					}
+					current, exists := target.users[user.Username]
+					add(kind, name, user.Username, exists, exists && len(userChanges(&user, current)) > 0, func(ctx context.Context) error {
+						if exists {
+							return c.UpdateUser(ctx, addr, user)
+						}
+						return c.CreateUser(ctx, addr, user)
+					})
+				}
+
+			case ResourceAcl:
+				for _, acl := range b.Acls {
+					if !f.match(acl.Subject) {
+						continue
+					}
+					current, exists := target.acls[acl.Subject]
+					add(kind, name, acl.Subject, exists, exists && len(aclChanges(&acl, current)) > 0, func(ctx context.Context) error {
+						if exists {
+							return c.UpdateAcl(ctx, addr, acl)
+						}
+						return c.CreateAcl(ctx, addr, acl)
+					})
+				}
+			}
+		}
+	}
+	return ops, nil
+}
+
+// loadRestoreTarget 读取目标 Broker 上需要对比的现状
+func (c *Client) loadRestoreTarget(ctx context.Context, addr string, kinds map[ResourceKind]bool) (*restoreTarget, error) {
+	target := &restoreTarget{addr: addr}
+	var err error
+	if kinds[ResourceBrokerConfig] {
+		if target.config, err = c.GetBrokerConfig(ctx, addr); err != nil {
+			return nil, err
+		}
+	}
+	if kinds[ResourceTopic] {
+		if target.topics, err = c.GetAllTopicConfig(ctx, addr); err != nil {
+			return nil, err
+		}
+	}
+	if kinds[ResourceGroup] {
+		if target.groups, err = c.GetAllSubscriptionGroup(ctx, addr); err != nil {
+			return nil, err
+		}
+	}
+	if kinds[ResourceOffset] {
+		if target.offsets, err = c.GetAllConsumerOffset(ctx, addr); err != nil {
+			return nil, err
+		}
+	}
+	if kinds[ResourceUser] {
+		list, err := c.ListUser(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+		target.users = make(map[string]*UserInfo, len(list.Users))
+		for i := range list.Users {
+			target.users[list.Users[i].Username] = &list.Users[i]
+		}
+	}
+	if kinds[ResourceAcl] {
+		list, err := c.ListAcl(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+		target.acls = make(map[string]*AclInfo, len(list.Acls))
+		for i := range list.Acls {
+			target.acls[list.Acls[i].Subject] = &list.Acls[i]
+		}
+	}
+	return target, nil
+}
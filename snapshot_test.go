@@ -0,0 +1,315 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// 元数据快照单元测试
+// =============================================================================
+
+// TestSnapshotFilter 测试快照过滤条件
+func TestSnapshotFilter(t *testing.T) {
+	f := &SnapshotFilter{Include: []string{"Order*"}, Exclude: []string{"*Test"}}
+	builtin := map[string]bool{"DefaultCluster": true}
+
+	cases := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"包含匹配", f.matchTopic("OrderTopic", builtin), true},
+		{"未包含", f.matchTopic("PayTopic", builtin), false},
+		{"排除优先", f.matchTopic("OrderTest", builtin), false},
+		{"Offset 按订阅组匹配", f.matchOffset("PayTopic@OrderGroup"), true},
+		{"Offset 格式错误", f.matchOffset("OrderTopic"), false},
+		{"系统订阅组", (&SnapshotFilter{}).matchGroup("TOOLS_CONSUMER"), false},
+		{"系统 Topic", (&SnapshotFilter{}).matchTopic("TBW102", builtin), false},
+		{"集群同名 Topic", (&SnapshotFilter{}).matchTopic("DefaultCluster", builtin), false},
+		{"包含系统资源", (&SnapshotFilter{IncludeSystem: true}).matchTopic("TBW102", builtin), true},
+	}
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, tc.got, tc.want)
+		}
+	}
+
+	kinds := &SnapshotFilter{Kinds: []ResourceKind{ResourceTopic}}
+	if !kinds.kindEnabled(ResourceTopic) || kinds.kindEnabled(ResourceOffset) {
+		t.Error("Kinds 过滤不正确")
+	}
+}
+
+// TestSnapshotReadWrite 测试快照读写和版本检查
+func TestSnapshotReadWrite(t *testing.T) {
+	snap := &MetadataSnapshot{
+		Version:   SnapshotVersion,
+		CreatedAt: time.Unix(1700000000, 0).UTC(),
+		Brokers: []*BrokerSnapshot{{
+			BrokerName: "broker-a",
+			Topics:     map[string]*TopicConfig{"TopicA": {TopicName: "TopicA", ReadQueueNums: 4}},
+			Offsets:    map[string]map[int]int64{"TopicA@GroupA": {0: 10, 3: 7}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, snap); err != nil {
+		t.Fatalf("写入快照失败: %v", err)
+	}
+	got, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("读取快照失败: %v", err)
+	}
+	want, _ := json.Marshal(snap)
+	again, _ := json.Marshal(got)
+	if string(want) != string(again) {
+		t.Errorf("快照往返不一致:\n%s\n%s", want, again)
+	}
+
+	// 未压缩的 JSON 同样可以读取
+	if _, err := ReadSnapshot(bytes.NewReader(want)); err != nil {
+		t.Errorf("读取未压缩快照失败: %v", err)
+	}
+
+	for _, version := range []int{0, SnapshotVersion + 1} {
+		data, _ := json.Marshal(MetadataSnapshot{Version: version})
+		if _, err := ReadSnapshot(bytes.NewReader(data)); err == nil {
+			t.Errorf("版本 %d 应返回错误", version)
+		}
+	}
+}
+
+// TestParseProperties 测试解析 Properties 格式配置
+func TestParseProperties(t *testing.T) {
+	config := parseProperties([]byte("# comment\nbrokerName=broker-a\n\nflushDiskType = ASYNC_FLUSH\nnamesrvAddr=a:9876;b:9876\nkey:value\n"))
+	want := map[string]string{
+		"brokerName":    "broker-a",
+		"flushDiskType": "ASYNC_FLUSH",
+		"namesrvAddr":   "a:9876;b:9876",
+		"key":           "value",
+	}
+	if len(config) != len(want) {
+		t.Fatalf("解析结果不正确: %v", config)
+	}
+	for k, v := range want {
+		if config[k] != v {
+			t.Errorf("%s 应为 %q, got %q", k, v, config[k])
+		}
+	}
+}
+
+// =============================================================================
+// 元数据快照端到端测试（本地模拟 NameServer/Broker）
+// =============================================================================
+
+// newSnapshotSource 启动导出用的模拟集群（broker-a）
+func newSnapshotSource(t *testing.T) *remotingtest.Server {
+	t.Helper()
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+	handleClusterInfo(srv, "broker-a")
+
+	srv.Handle(remoting.GetBrokerConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte("brokerName=broker-a\nflushDiskType=ASYNC_FLUSH\n"))
+	})
+	srv.Handle(remoting.GetAllTopicConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"topicConfigTable": map[string]any{
+			"TopicA": map[string]any{"topicName": "TopicA", "readQueueNums": 2, "writeQueueNums": 2, "perm": 6},
+			"TBW102": map[string]any{"topicName": "TBW102", "readQueueNums": 8, "writeQueueNums": 8, "perm": 7},
+		}})
+	})
+	srv.Handle(remoting.GetAllSubscriptionGroup, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"subscriptionGroupTable": map[string]any{
+			"GroupA":         map[string]any{"groupName": "GroupA", "consumeEnable": true, "retryMaxTimes": 16},
+			"TOOLS_CONSUMER": map[string]any{"groupName": "TOOLS_CONSUMER"},
+		}})
+	})
+	srv.Handle(remoting.GetAllConsumerOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"offsetTable":{"TopicA@GroupA":{0:10,1:20},"TopicA@TOOLS_CONSUMER":{0:1}}}`))
+	})
+	for _, code := range []int{remoting.ListUser, remoting.ListAcl} {
+		srv.Handle(code, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+			return remotingtest.Error(remoting.RequestCodeNotSupported, "request type not supported")
+		})
+	}
+	srv.Handle(remoting.GetKVListByNamespace, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"table": map[string]string{"TopicA": "broker-a:2"}})
+	})
+	return srv
+}
+
+// newSnapshotTarget 启动恢复用的模拟集群（broker-b），已有不同配置的 TopicA
+func newSnapshotTarget(t *testing.T) *remotingtest.Server {
+	t.Helper()
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+	handleClusterInfo(srv, "broker-b")
+
+	srv.Handle(remoting.GetBrokerConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte("brokerName=broker-b\nflushDiskType=SYNC_FLUSH\n"))
+	})
+	srv.Handle(remoting.GetAllTopicConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"topicConfigTable": map[string]any{
+			"TopicA": map[string]any{"topicName": "TopicA", "readQueueNums": 8, "writeQueueNums": 8, "perm": 6},
+		}})
+	})
+	srv.Handle(remoting.GetAllSubscriptionGroup, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"subscriptionGroupTable": map[string]any{}})
+	})
+	srv.Handle(remoting.GetAllConsumerOffset, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"offsetTable":{"TopicA@GroupA":{0:10}}}`))
+	})
+	srv.Handle(remoting.GetKVListByNamespace, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Error(remoting.QueryNotFound, "No config item")
+	})
+	for _, code := range []int{
+		remoting.UpdateBrokerConfig, remoting.UpdateAndCreateTopic, remoting.UpdateAndCreateSubscriptionGroup,
+		remoting.UpdateConsumeOffset, remoting.PutKVConfig,
+	} {
+		srv.Handle(code, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+			return remotingtest.Success(nil)
+		})
+	}
+	return srv
+}
+
+// TestExportRestoreMetadata 测试导出快照并恢复到另一个集群
+func TestExportRestoreMetadata(t *testing.T) {
+	source := newSnapshotSource(t)
+	target := newSnapshotTarget(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	snap, err := newFakeClient(t, source).ExportMetadata(ctx, ExportOptions{})
+	if err != nil {
+		t.Fatalf("导出快照失败: %v", err)
+	}
+	if len(snap.Brokers) != 1 || len(snap.Warnings) != 2 {
+		t.Fatalf("快照内容不正确: %+v", snap)
+	}
+	b := snap.Brokers[0]
+	if b.BrokerName != "broker-a" || b.Config["flushDiskType"] != "ASYNC_FLUSH" {
+		t.Errorf("Broker 配置不正确: %+v", b)
+	}
+	if len(b.Topics) != 1 || b.Topics["TopicA"] == nil || len(b.Groups) != 1 || b.Groups["GroupA"] == nil {
+		t.Errorf("应过滤系统 Topic 和订阅组: %v %v", b.Topics, b.Groups)
+	}
+	if len(b.Offsets) != 1 || b.Offsets["TopicA@GroupA"][1] != 20 {
+		t.Errorf("消费 Offset 不正确: %v", b.Offsets)
+	}
+	if snap.KV["ORDER_TOPIC_CONFIG"]["TopicA"] != "broker-a:2" {
+		t.Errorf("KV 不正确: %v", snap.KV)
+	}
+
+	client := newFakeClient(t, target)
+	writes := func() int {
+		n := 0
+		for _, code := range []int{remoting.UpdateBrokerConfig, remoting.UpdateAndCreateTopic,
+			remoting.UpdateAndCreateSubscriptionGroup, remoting.UpdateConsumeOffset, remoting.PutKVConfig} {
+			n += len(target.Requests(code))
+		}
+		return n
+	}
+
+	// Broker 名不同且未配置映射
+	if _, err := client.RestoreMetadata(ctx, snap, RestoreOptions{}); err == nil || !strings.Contains(err.Error(), "broker-a") {
+		t.Fatalf("未映射的 Broker 应返回错误: %v", err)
+	}
+
+	mapping := map[string]string{"broker-a": "broker-b"}
+
+	// 存在冲突时不做修改
+	report, err := client.RestoreMetadata(ctx, snap, RestoreOptions{BrokerMapping: mapping, Conflict: ConflictFail})
+	if err == nil || writes() != 0 {
+		t.Fatalf("冲突时应返回错误且不修改集群: %v", err)
+	}
+	if report.Count(RestoreSkipped) != 2 {
+		t.Errorf("应有 2 处冲突: %+v", report.Items)
+	}
+
+	// 默认跳过冲突，演练不修改集群
+	report, err = client.RestoreMetadata(ctx, snap, RestoreOptions{BrokerMapping: mapping, DryRun: true})
+	if err != nil {
+		t.Fatalf("演练恢复失败: %v", err)
+	}
+	if writes() != 0 {
+		t.Fatal("演练不应修改集群")
+	}
+	var got []string
+	for _, item := range report.Items {
+		got = append(got, string(item.Kind)+" "+item.Name+" "+string(item.Status))
+	}
+	want := []string{
+		"kv ORDER_TOPIC_CONFIG/TopicA created",
+		"brokerConfig flushDiskType skipped",
+		"topic TopicA skipped",
+		"group GroupA created",
+		"offset TopicA@GroupA@0 unchanged",
+		"offset TopicA@GroupA@1 created",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("恢复报告不正确:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// 覆盖冲突
+	report, err = client.RestoreMetadata(ctx, snap, RestoreOptions{BrokerMapping: mapping, Conflict: ConflictOverwrite})
+	if err != nil {
+		t.Fatalf("恢复失败: %v", err)
+	}
+	if report.Count(RestoreUpdated) != 2 || report.Count(RestoreCreated) != 3 || writes() != 5 {
+		t.Errorf("覆盖恢复结果不正确: %+v, writes=%d", report.Items, writes())
+	}
+	if reqs := target.Requests(remoting.UpdateAndCreateTopic); len(reqs) != 1 || reqs[0].ExtFields["readQueueNums"] != "2" {
+		t.Errorf("Topic 应按快照配置覆盖: %+v", reqs)
+	}
+	if reqs := target.Requests(remoting.UpdateConsumeOffset); len(reqs) != 1 || reqs[0].ExtFields["commitOffset"] != "20" {
+		t.Errorf("只应写入不同的 Offset: %+v", reqs)
+	}
+}
+
+// =============================================================================
+// 元数据快照集成测试
+// =============================================================================
+
+// TestIntegration_ExportMetadata 测试导出真实集群的元数据
+func TestIntegration_ExportMetadata(t *testing.T) {
+	skipIfNoRocketMQ(t)
+	client := getTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := testContext()
+	defer cancel()
+
+	topic, cleanup := createTestTopic(t, client, "snapshot")
+	defer cleanup()
+
+	snap, err := client.ExportMetadata(ctx, ExportOptions{SnapshotFilter: SnapshotFilter{Include: []string{topic}}})
+	if err != nil {
+		t.Fatalf("导出快照失败: %v", err)
+	}
+	found := false
+	for _, b := range snap.Brokers {
+		if b.Topics[topic] != nil {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("快照中应包含 Topic %s", topic)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, snap); err != nil {
+		t.Fatalf("写入快照失败: %v", err)
+	}
+	if _, err := ReadSnapshot(&buf); err != nil {
+		t.Fatalf("读取快照失败: %v", err)
+	}
+}
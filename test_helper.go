@@ -3,8 +3,12 @@ package admin
 import (
 	"context"
 	"os"
+	"strconv"
 	"testing"
 	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
 )
 
 // =============================================================================
@@ -107,3 +111,85 @@ func getTestGroupName(suffix string) string {
 func testContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), testTimeout)
 }
+
+// createTestTopic 在第一个 Broker 上创建测试 Topic，返回 Topic 名称和清理函数
+func createTestTopic(t *testing.T, client *Client, suffix string) (string, func()) {
+	t.Helper()
+
+	ctx, cancel := testContext()
+	defer cancel()
+
+	clusterInfo, err := client.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		t.Fatalf("获取集群信息失败: %v", err)
+	}
+
+	var brokerAddr, clusterName string
+	for cluster, brokerNames := range clusterInfo.ClusterAddrTable {
+		for _, brokerName := range brokerNames {
+			if brokerData, ok := clusterInfo.BrokerAddrTable[brokerName]; ok {
+				if addr := brokerData.BrokerAddrs["0"]; addr != "" {
+					brokerAddr, clusterName = addr, cluster
+					break
+				}
+			}
+		}
+		if brokerAddr != "" {
+			break
+		}
+	}
+	if brokerAddr == "" {
+		t.Fatal("未找到可用的 Broker 地址")
+	}
+
+	topicName := getTestTopicName(suffix)
+	topicConfig := TopicConfig{
+		TopicName:       topicName,
+		ReadQueueNums:   4,
+		WriteQueueNums:  4,
+		Perm:            6,
+		TopicFilterType: "SINGLE_TAG",
+	}
+	if err := client.CreateTopic(ctx, brokerAddr, topicConfig); err != nil {
+		t.Fatalf("创建 Topic 失败: %v", err)
+	}
+
+	return topicName, func() {
+		ctx, cancel := testContext()
+		defer cancel()
+		_ = client.DeleteTopic(ctx, topicName, clusterName)
+	}
+}
+
+// =============================================================================
+// 模拟集群辅助函数
+// =============================================================================
+
+// newFakeClient 创建连接模拟服务端的客户端
+func newFakeClient(t *testing.T, srv *remotingtest.Server, opts ...Option) *Client {
+	t.Helper()
+	opts = append([]Option{WithNameServers([]string{srv.Addr}), WithTimeout(2 * time.Second)}, opts...)
+	client, err := NewClient(opts...)
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	if err := client.Start(); err != nil {
+		t.Fatalf("启动客户端失败: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// handleClusterInfo 注册只有一个 Master 的集群信息，Master 地址为服务端自身
+func handleClusterInfo(srv *remotingtest.Server, brokerName string) {
+	srv.Handle(remoting.GetBrokerClusterInfo, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"brokerAddrTable":{"` + brokerName + `":{"cluster":"DefaultCluster","brokerName":"` + brokerName +
+			`","brokerAddrs":{0:"` + srv.Addr + `"}}},"clusterAddrTable":{"DefaultCluster":["` + brokerName + `"]}}`))
+	})
+}
+
+// atoi 解析请求中的整数字段
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
 )
@@ -23,6 +24,9 @@ func (c *Client) CreateTopic(ctx context.Context, addr string, config TopicConfi
 		"topicSysFlag":    fmt.Sprintf("%d", config.TopicSysFlag),
 		"order":           fmt.Sprintf("%t", config.Order),
 	}
+	if len(config.Attributes) > 0 {
+		extFields["attributes"] = attributesModification(config.Attributes)
+	}
 
 	cmd := remoting.NewRequest(remoting.UpdateAndCreateTopic, extFields)
 
@@ -161,6 +165,7 @@ func (c *Client) ExamineTopicRouteInfo(ctx context.Context, topic string) (*Topi
 }
 
 // ExamineTopicStats 查询 Topic 统计信息
+// 汇总 Topic 所在所有 Broker 的队列统计
 func (c *Client) ExamineTopicStats(ctx context.Context, topic string) (*TopicStatsTable, error) {
 	// 先获取路由信息
 	routeData, err := c.ExamineTopicRouteInfo(ctx, topic)
@@ -172,14 +177,41 @@ func (c *Client) ExamineTopicStats(ctx context.Context, topic string) (*TopicSta
 		return nil, ErrBrokerNotFound
 	}
 
-	// 向第一个 Broker 查询统计信息
-	brokerData := routeData.BrokerDatas[0]
-	var brokerAddr string
-	for _, addr := range brokerData.BrokerAddrs {
-		brokerAddr = addr
-		break
+	result := &TopicStatsTable{
+		OffsetTable: make(map[string]*TopicOffset),
 	}
 
+	var lastErr error
+	for _, brokerData := range routeData.BrokerDatas {
+		// 优先查询 Master
+		brokerAddr := brokerData.BrokerAddrs["0"]
+		if brokerAddr == "" {
+			for _, addr := range brokerData.BrokerAddrs {
+				brokerAddr = addr
+				break
+			}
+		}
+
+		statsTable, err := c.examineTopicStatsInBroker(ctx, brokerAddr, topic)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		for k, v := range statsTable.OffsetTable {
+			result.OffsetTable[k] = v
+		}
+	}
+
+	if len(result.OffsetTable) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return result, nil
+}
+
+// examineTopicStatsInBroker 查询 Topic 在单个 Broker 上的统计信息
+func (c *Client) examineTopicStatsInBroker(ctx context.Context, brokerAddr, topic string) (*TopicStatsTable, error) {
 	extFields := map[string]string{
 		"topic": topic,
 	}
@@ -230,9 +262,20 @@ func (c *Client) DeleteTopicInBroker(ctx context.Context, brokerAddr, topic stri
 
 // DeleteTopicInNameServer 在 NameServer 中删除 Topic
 func (c *Client) DeleteTopicInNameServer(ctx context.Context, topic string) error {
+	return c.DeleteTopicInNameServerByCluster(ctx, topic, "")
+}
+
+// DeleteTopicInNameServerByCluster 在 NameServer 中删除 Topic 在指定集群上的路由
+//
+// clusterName 为空时删除全部集群的路由；否则保留其他集群 Broker 的路由，
+// 需要 NameServer 支持按 clusterName 请求头删除路由。
+func (c *Client) DeleteTopicInNameServerByCluster(ctx context.Context, topic, clusterName string) error {
 	extFields := map[string]string{
 		"topic": topic,
 	}
+	if clusterName != "" {
+		extFields["clusterName"] = clusterName
+	}
 	cmd := remoting.NewRequest(remoting.DeleteTopicInNamesrv, extFields)
 
 	resp, err := c.invokeNameServer(ctx, cmd)
@@ -324,25 +367,38 @@ func (c *Client) QueryTopicConsumeByWho(ctx context.Context, topic string) ([]st
 
 // GetAllTopicConfig 获取所有 Topic 配置
 func (c *Client) GetAllTopicConfig(ctx context.Context, brokerAddr string) (map[string]*TopicConfig, error) {
+	table, _, err := c.topicConfigTable(ctx, brokerAddr)
+	return table, err
+}
+
+// topicConfigTable 获取 Broker 上全部 Topic 配置及其数据版本
+func (c *Client) topicConfigTable(ctx context.Context, brokerAddr string) (map[string]*TopicConfig, *DataVersion, error) {
 	cmd := remoting.NewRequest(remoting.GetAllTopicConfig, nil)
 
 	resp, err := c.invokeBroker(ctx, brokerAddr, cmd)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if resp.Code != remoting.Success {
-		return nil, NewAdminError(resp.Code, resp.Remark)
+		return nil, nil, NewAdminError(resp.Code, resp.Remark)
 	}
 
 	var wrapper struct {
-		TopicConfigTable map[string]*TopicConfig `json:"topicConfigTable"`
+		TopicConfigTable map[string]json.RawMessage `json:"topicConfigTable"`
+		DataVersion      *DataVersion               `json:"dataVersion"`
 	}
 	if err := json.Unmarshal(resp.Body, &wrapper); err != nil {
-		return nil, fmt.Errorf("解析 Topic 配置失败: %w", err)
+		return nil, nil, fmt.Errorf("解析 Topic 配置失败: %w", err)
+	}
+	table, err := decodeConfigTable(wrapper.TopicConfigTable, func(config *TopicConfig, extra map[string]json.RawMessage) {
+		config.Extra = extra
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析 Topic 配置失败: %w", err)
 	}
 
-	return wrapper.TopicConfigTable, nil
+	return table, wrapper.DataVersion, nil
 }
 
 // CreateAndUpdateTopicConfigList 批量创建/更新 Topic 配置
@@ -407,3 +463,28 @@ func (c *Client) ExamineTopicStatsConcurrent(ctx context.Context, topic string)
 	// 内部实现与 ExamineTopicStats 相同，但可扩展为真正并发
 	return c.ExamineTopicStats(ctx, topic)
 }
+
+// =============================================================================
+// 系统 Topic
+// =============================================================================
+
+// systemTopics 系统 Topic（对应 Java TopicValidator）
+var systemTopics = map[string]bool{
+	"TBW102":                      true,
+	"SCHEDULE_TOPIC_XXXX":         true,
+	"BenchmarkTest":               true,
+	"OFFSET_MOVED_EVENT":          true,
+	"SELF_TEST_TOPIC":             true,
+	"RMQ_SYS_TRANS_HALF_TOPIC":    true,
+	"RMQ_SYS_TRANS_OP_HALF_TOPIC": true,
+	"RMQ_SYS_TRACE_TOPIC":         true,
+	"TRANS_CHECK_MAX_TIME_TOPIC":  true,
+}
+
+// IsSystemTopic 判断是否为重试、死信或系统 Topic
+func IsSystemTopic(topic string) bool {
+	return systemTopics[topic] ||
+		strings.HasPrefix(topic, RetryGroupTopicPrefix) ||
+		strings.HasPrefix(topic, DLQGroupTopicPrefix) ||
+		strings.HasPrefix(topic, "rmq_sys_")
+}
@@ -0,0 +1,313 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// 集群级 Topic 创建与更新
+// =============================================================================
+
+var (
+	// topicRouteWaitTimeout 等待 NameServer 路由生效的最长时间
+	topicRouteWaitTimeout = 30 * time.Second
+
+	// topicRouteCheckInterval 检查 NameServer 路由的间隔
+	topicRouteCheckInterval = 500 * time.Millisecond
+)
+
+// TopicApplyStatus 单个 Broker 上的 Topic 配置写入状态
+type TopicApplyStatus string
+
+const (
+	TopicCreated    TopicApplyStatus = "created"     // 新建 Topic
+	TopicUpdated    TopicApplyStatus = "updated"     // 更新已存在的 Topic
+	TopicFailed     TopicApplyStatus = "failed"      // 写入失败
+	TopicRolledBack TopicApplyStatus = "rolled-back" // 其他 Broker 失败，已撤销本 Broker 上的修改
+)
+
+// BrokerTopicResult 单个 Broker 上的 Topic 配置写入结果
+type BrokerTopicResult struct {
+	BrokerName string           `json:"brokerName"`
+	Addr       string           `json:"addr"`
+	Topic      string           `json:"topic"`
+	Status     TopicApplyStatus `json:"status"`
+	Previous   *TopicConfig     `json:"previous,omitempty"` // 修改前的配置，新建时为空
+	Error      string           `json:"error,omitempty"`    // 写入或回滚失败的原因
+
+	checked bool // 是否已读取修改前的配置，未读取时无法确认 Topic 是否为新建
+}
+
+// CreateTopicInCluster 在集群的全部 Master 上创建或更新 Topic，clusterName 为空时作用于全部集群
+func (c *Client) CreateTopicInCluster(ctx context.Context, clusterName string, config TopicConfig) ([]BrokerTopicResult, error) {
+	return c.CreateAndUpdateTopicConfigListInCluster(ctx, clusterName, []TopicConfig{config})
+}
+
+// CreateTopicOnBrokers 在指定 Broker 的 Master 上创建或更新 Topic
+func (c *Client) CreateTopicOnBrokers(ctx context.Context, brokerNames []string, config TopicConfig) ([]BrokerTopicResult, error) {
+	masters, err := c.brokerMasters(ctx, brokerNames)
+	if err != nil {
+		return nil, err
+	}
+	return c.applyTopicConfigs(ctx, masters, []TopicConfig{config})
+}
+
+// brokerMasters 返回指定 Broker 的 Master 地址，key: brokerName
+func (c *Client) brokerMasters(ctx context.Context, brokerNames []string) (map[string]string, error) {
+	clusterInfo, err := c.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取集群信息失败: %w", err)
+	}
+	if len(brokerNames) == 0 {
+		return nil, fmt.Errorf("%w: 未指定 Broker", ErrBrokerNotFound)
+	}
+
+	masters := make(map[string]string, len(brokerNames))
+	for _, name := range brokerNames {
+		data, ok := clusterInfo.BrokerAddrTable[name]
+		if !ok || data.BrokerAddrs["0"] == "" {
+			return nil, fmt.Errorf("%w: %s 没有可用的 Master", ErrBrokerNotFound, name)
+		}
+		masters[name] = data.BrokerAddrs["0"]
+	}
+	return masters, nil
+}
+
+// CreateAndUpdateTopicConfigListInCluster 在集群的全部 Master 上批量创建或更新 Topic
+//
+// 各 Master 并行写入。任一 Broker 写入失败时撤销其他 Broker 上的修改：新建的 Topic
+// 被删除，更新的 Topic 恢复为原配置，并返回 BrokerErrors；写入前在全部目标 Broker 上都
+// 不存在的 Topic 同时删除其在目标集群上的 NameServer 路由。全部成功后等待 NameServer
+// 路由反映新的队列数与权限再返回。
+func (c *Client) CreateAndUpdateTopicConfigListInCluster(ctx context.Context, clusterName string, configs []TopicConfig) ([]BrokerTopicResult, error) {
+	clusterInfo, err := c.ExamineBrokerClusterInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取集群信息失败: %w", err)
+	}
+	masters, err := planBrokers(clusterInfo, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return c.applyTopicConfigs(ctx, masters, configs)
+}
+
+// applyTopicConfigs 在各 Master 上并行写入 Topic 配置，失败时回滚，成功时等待路由生效
+func (c *Client) applyTopicConfigs(ctx context.Context, masters map[string]string, configs []TopicConfig) ([]BrokerTopicResult, error) {
+	for _, config := range configs {
+		if config.TopicName == "" || config.ReadQueueNums <= 0 || config.WriteQueueNums <= 0 {
+			return nil, fmt.Errorf("Topic 配置无效: %+v", config)
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results []BrokerTopicResult
+	)
+	for brokerName, addr := range masters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			brokerResults := c.applyTopicConfigsOnBroker(ctx, brokerName, addr, configs)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, brokerResults...)
+		}()
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Topic != results[j].Topic {
+			return results[i].Topic < results[j].Topic
+		}
+		return results[i].BrokerName < results[j].BrokerName
+	})
+
+	var brokerErrs BrokerErrors
+	for _, r := range results {
+		if r.Status == TopicFailed {
+			brokerErrs = append(brokerErrs, &BrokerError{BrokerName: r.BrokerName, Addr: r.Addr, Err: fmt.Errorf("Topic %s: %s", r.Topic, r.Error)})
+		}
+	}
+	if len(brokerErrs) > 0 {
+		c.rollbackTopicConfigs(ctx, masters, results)
+		return results, brokerErrs
+	}
+
+	if err := c.waitTopicRoutes(ctx, masters, configs); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// applyTopicConfigsOnBroker 在单个 Master 上依次写入 Topic 配置，并记录修改前的配置
+func (c *Client) applyTopicConfigsOnBroker(ctx context.Context, brokerName, addr string, configs []TopicConfig) []BrokerTopicResult {
+	results := make([]BrokerTopicResult, len(configs))
+	existing, err := c.GetAllTopicConfig(ctx, addr)
+	for i, config := range configs {
+		r := &results[i]
+		*r = BrokerTopicResult{BrokerName: brokerName, Addr: addr, Topic: config.TopicName, Status: TopicCreated}
+		if err != nil {
+			// 无法确认原配置时不写入，避免回滚时误删已有 Topic
+			r.Status, r.Error = TopicFailed, fmt.Sprintf("读取 Topic 配置失败: %v", err)
+			continue
+		}
+		r.checked = true
+		if previous, ok := existing[config.TopicName]; ok {
+			r.Status, r.Previous = TopicUpdated, previous
+		}
+		if err := c.CreateTopic(ctx, addr, config); err != nil {
+			r.Status, r.Error = TopicFailed, err.Error()
+		}
+	}
+	return results
+}
+
+// rollbackTopicConfigs 撤销已成功写入的 Topic 配置，回滚失败时保留原状态并记录原因
+//
+// Broker 创建 Topic 时已向 NameServer 注册路由，重新注册不会清理，因此写入前在全部目标
+// Broker 上都不存在的 Topic 在 Broker 回滚成功后还要删除 NameServer 路由，失败原因记录在
+// 该 Topic 的各条结果中。
+func (c *Client) rollbackTopicConfigs(ctx context.Context, masters map[string]string, results []BrokerTopicResult) {
+	created := make(map[string]bool) // key: Topic，value: 是否为本次新建且 Broker 回滚全部成功
+	for i := range results {
+		r := &results[i]
+		if _, ok := created[r.Topic]; !ok {
+			created[r.Topic] = true
+		}
+		if !r.checked || r.Previous != nil {
+			created[r.Topic] = false
+		}
+
+		var err error
+		switch r.Status {
+		case TopicCreated:
+			err = c.DeleteTopicInBroker(ctx, r.Addr, r.Topic)
+		case TopicUpdated:
+			err = c.CreateTopic(ctx, r.Addr, *r.Previous)
+		default:
+			continue
+		}
+		if err != nil {
+			r.Error = fmt.Sprintf("回滚失败: %v", err)
+			created[r.Topic] = false
+			continue
+		}
+		r.Status = TopicRolledBack
+	}
+
+	for topic, ok := range created {
+		if !ok {
+			continue
+		}
+		err := c.deleteTopicRoute(ctx, topic, masters)
+		if err == nil {
+			continue
+		}
+		for i := range results {
+			if r := &results[i]; r.Topic == topic {
+				if r.Error != "" {
+					r.Error += "; "
+				}
+				r.Error += fmt.Sprintf("删除 NameServer 路由失败: %v", err)
+			}
+		}
+	}
+}
+
+// deleteTopicRoute 删除 Topic 在目标 Broker 所属集群上的 NameServer 路由
+//
+// 路由中还有其他 Broker 的队列时不删除，避免影响仍持有该 Topic 的 Broker。
+func (c *Client) deleteTopicRoute(ctx context.Context, topic string, masters map[string]string) error {
+	routeData, err := c.ExamineTopicRouteInfo(ctx, topic)
+	if errors.Is(err, ErrTopicNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, qd := range routeData.QueueDatas {
+		if _, ok := masters[qd.BrokerName]; !ok {
+			return nil
+		}
+	}
+
+	seen := make(map[string]bool)
+	var clusters []string
+	for _, bd := range routeData.BrokerDatas {
+		if _, ok := masters[bd.BrokerName]; ok && bd.Cluster != "" && !seen[bd.Cluster] {
+			seen[bd.Cluster] = true
+			clusters = append(clusters, bd.Cluster)
+		}
+	}
+	sort.Strings(clusters)
+
+	var errs []error
+	for _, cluster := range clusters {
+		if err := c.DeleteTopicInNameServerByCluster(ctx, topic, cluster); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", cluster, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// waitTopicRoutes 等待 NameServer 中各 Topic 在目标 Broker 上的队列数与权限与配置一致
+func (c *Client) waitTopicRoutes(ctx context.Context, masters map[string]string, configs []TopicConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, topicRouteWaitTimeout)
+	defer cancel()
+
+	brokerPerms := c.brokerPermissions(ctx, masters)
+	for _, config := range configs {
+		err := c.waitTopicRoute(ctx, config.TopicName, masters, func(qd *QueueData) bool {
+			return qd.ReadQueueNums == config.ReadQueueNums && qd.WriteQueueNums == config.WriteQueueNums &&
+				qd.Perm == routePerm(config.Perm, brokerPerms[qd.BrokerName])
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitTopicRoute 轮询 NameServer，直到 Topic 在全部目标 Broker 上的路由满足 match 或 ctx 结束
+func (c *Client) waitTopicRoute(ctx context.Context, topic string, masters map[string]string, match func(qd *QueueData) bool) error {
+	ticker := time.NewTicker(topicRouteCheckInterval)
+	defer ticker.Stop()
+	for {
+		ready, err := c.topicRouteReady(ctx, topic, masters, match)
+		if err != nil && !errors.Is(err, ErrTopicNotFound) && ctx.Err() == nil {
+			return fmt.Errorf("查询 Topic %s 路由失败: %w", topic, err)
+		}
+		if ready {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("等待 Topic %s 路由生效超时: %w", topic, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// topicRouteReady 判断 Topic 路由中全部目标 Broker 的队列数据是否满足 match
+func (c *Client) topicRouteReady(ctx context.Context, topic string, masters map[string]string, match func(qd *QueueData) bool) (bool, error) {
+	routeData, err := c.ExamineTopicRouteInfo(ctx, topic)
+	if err != nil {
+		return false, err
+	}
+	matched := 0
+	for _, qd := range routeData.QueueDatas {
+		if _, ok := masters[qd.BrokerName]; !ok {
+			continue
+		}
+		if match(qd) {
+			matched++
+		}
+	}
+	return matched == len(masters), nil
+}
@@ -0,0 +1,161 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// 集群级 Topic 创建单元测试
+// =============================================================================
+
+// newTopicCluster 启动两个 Master 的模拟集群：broker-a 上已有 TopicX（4 个队列），broker-b 上没有且为只读 Broker。
+// NameServer 与 broker-a 共用 srvA，路由中的队列数由 routeQueues 决定
+func newTopicCluster(t *testing.T, routeQueues *atomic.Int32) (srvA, srvB *remotingtest.Server) {
+	t.Helper()
+	srvA, srvB = remotingtest.NewServer(), remotingtest.NewServer()
+	t.Cleanup(srvA.Close)
+	t.Cleanup(srvB.Close)
+
+	srvA.Handle(remoting.GetBrokerClusterInfo, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"brokerAddrTable":{` +
+			`"broker-a":{"cluster":"DefaultCluster","brokerName":"broker-a","brokerAddrs":{0:"` + srvA.Addr + `"}},` +
+			`"broker-b":{"cluster":"DefaultCluster","brokerName":"broker-b","brokerAddrs":{0:"` + srvB.Addr + `"}}},` +
+			`"clusterAddrTable":{"DefaultCluster":["broker-a","broker-b"]}}`))
+	})
+	srvA.Handle(remoting.GetRouteInfoByTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		n := routeQueues.Load()
+		return remotingtest.JSON(map[string]any{"queueDatas": []map[string]any{
+			{"brokerName": "broker-a", "readQueueNums": n, "writeQueueNums": n, "perm": 6},
+			{"brokerName": "broker-b", "readQueueNums": n, "writeQueueNums": n, "perm": 4},
+		}, "brokerDatas": []map[string]any{
+			{"cluster": "DefaultCluster", "brokerName": "broker-a", "brokerAddrs": map[string]string{"0": srvA.Addr}},
+			{"cluster": "DefaultCluster", "brokerName": "broker-b", "brokerAddrs": map[string]string{"0": srvB.Addr}},
+		}})
+	})
+	srvA.Handle(remoting.DeleteTopicInNamesrv, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success(nil)
+	})
+	srvA.Handle(remoting.GetAllTopicConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"topicConfigTable": map[string]any{
+			"TopicX": map[string]any{"topicName": "TopicX", "readQueueNums": 4, "writeQueueNums": 4, "perm": 6},
+		}})
+	})
+	srvB.Handle(remoting.GetAllTopicConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"topicConfigTable": map[string]any{}})
+	})
+	// 只读 Broker 注册的路由权限被 brokerPermission 屏蔽写权限
+	srvB.Handle(remoting.GetBrokerConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte("brokerName=broker-b\nbrokerPermission=4\n"))
+	})
+	for _, srv := range []*remotingtest.Server{srvA, srvB} {
+		srv.Handle(remoting.UpdateAndCreateTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+			return remotingtest.Success(nil)
+		})
+		srv.Handle(remoting.DeleteTopicInBroker, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+			return remotingtest.Success(nil)
+		})
+	}
+	return srvA, srvB
+}
+
+// TestCreateTopicInCluster 测试在集群全部 Master 上创建 Topic 并等待路由生效
+func TestCreateTopicInCluster(t *testing.T) {
+	var routeQueues atomic.Int32
+	routeQueues.Store(8)
+	srvA, srvB := newTopicCluster(t, &routeQueues)
+	client := newFakeClient(t, srvA)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	config := TopicConfig{TopicName: "TopicX", ReadQueueNums: 8, WriteQueueNums: 8, Perm: 6}
+	results, err := client.CreateTopicInCluster(ctx, "DefaultCluster", config)
+	if err != nil {
+		t.Fatalf("创建 Topic 失败: %v", err)
+	}
+	if len(results) != 2 || results[0].Status != TopicUpdated || results[0].Previous.ReadQueueNums != 4 || results[1].Status != TopicCreated {
+		t.Errorf("创建结果错误: %+v", results)
+	}
+	if len(srvA.Requests(remoting.UpdateAndCreateTopic)) != 1 || len(srvB.Requests(remoting.UpdateAndCreateTopic)) != 1 {
+		t.Error("应在每个 Master 上各写入一次")
+	}
+
+	if _, err := client.CreateTopicOnBrokers(ctx, []string{"broker-a", "broker-c"}, config); !errors.Is(err, ErrBrokerNotFound) {
+		t.Errorf("不存在的 Broker 应返回 ErrBrokerNotFound, got %v", err)
+	}
+
+	// 路由未更新时等待超时
+	defer func(timeout, interval time.Duration) {
+		topicRouteWaitTimeout, topicRouteCheckInterval = timeout, interval
+	}(topicRouteWaitTimeout, topicRouteCheckInterval)
+	topicRouteWaitTimeout, topicRouteCheckInterval = 100*time.Millisecond, 10*time.Millisecond
+	routeQueues.Store(4)
+	if _, err := client.CreateTopicOnBrokers(ctx, []string{"broker-b"}, config); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("路由未生效应超时, got %v", err)
+	}
+}
+
+// TestCreateTopicInCluster_Rollback 测试部分 Broker 失败时回滚其他 Broker
+func TestCreateTopicInCluster_Rollback(t *testing.T) {
+	var routeQueues atomic.Int32
+	srvA, srvB := newTopicCluster(t, &routeQueues)
+	srvB.Handle(remoting.UpdateAndCreateTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Error(remoting.SystemError, "disk full")
+	})
+	client := newFakeClient(t, srvA)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	configs := []TopicConfig{
+		{TopicName: "TopicX", ReadQueueNums: 8, WriteQueueNums: 8, Perm: 6},
+		{TopicName: "TopicY", ReadQueueNums: 2, WriteQueueNums: 2, Perm: 6},
+	}
+	results, err := client.CreateAndUpdateTopicConfigListInCluster(ctx, "DefaultCluster", configs)
+	var brokerErrs BrokerErrors
+	if !errors.As(err, &brokerErrs) || len(brokerErrs) != 2 || brokerErrs[0].BrokerName != "broker-b" {
+		t.Fatalf("应返回 broker-b 的失败, got %v", err)
+	}
+	for _, r := range results {
+		want := TopicRolledBack
+		if r.BrokerName == "broker-b" {
+			want = TopicFailed
+		}
+		if r.Status != want {
+			t.Errorf("%s/%s 应为 %s, got %+v", r.BrokerName, r.Topic, want, r)
+		}
+	}
+
+	// TopicX 恢复为原配置，新建的 TopicY 被删除
+	writes := srvA.Requests(remoting.UpdateAndCreateTopic)
+	if len(writes) != 3 || writes[2].ExtFields["topic"] != "TopicX" || writes[2].ExtFields["readQueueNums"] != "4" {
+		t.Errorf("TopicX 应恢复为 4 个队列: %+v", writes)
+	}
+	deletes := srvA.Requests(remoting.DeleteTopicInBroker)
+	if len(deletes) != 1 || deletes[0].ExtFields["topic"] != "TopicY" {
+		t.Errorf("应删除新建的 TopicY: %+v", deletes)
+	}
+	// Broker 创建时注册的路由不会自动清理，只删除新建 TopicY 在本集群的路由
+	routes := srvA.Requests(remoting.DeleteTopicInNamesrv)
+	if len(routes) != 1 || routes[0].ExtFields["topic"] != "TopicY" || routes[0].ExtFields["clusterName"] != "DefaultCluster" {
+		t.Errorf("应删除 TopicY 在 DefaultCluster 的路由: %+v", routes)
+	}
+
+	// 删除路由失败时记录在 TopicY 的结果中
+	srvA.Handle(remoting.DeleteTopicInNamesrv, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Error(remoting.SystemError, "namesrv busy")
+	})
+	results, _ = client.CreateAndUpdateTopicConfigListInCluster(ctx, "DefaultCluster", configs)
+	for _, r := range results {
+		failed := strings.Contains(r.Error, "删除 NameServer 路由失败")
+		if failed != (r.Topic == "TopicY") {
+			t.Errorf("%s/%s 路由删除结果错误: %+v", r.BrokerName, r.Topic, r)
+		}
+	}
+}
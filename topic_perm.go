@@ -0,0 +1,134 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// =============================================================================
+// Topic 权限
+// =============================================================================
+
+// Perm Topic 与 Broker 的读写权限位（对应 Java PermName）
+type Perm int
+
+const (
+	PermInherit  Perm = 1 << 0 // 从默认 Topic 继承
+	PermWrite    Perm = 1 << 1 // 可写
+	PermRead     Perm = 1 << 2 // 可读
+	PermPriority Perm = 1 << 3 // 优先
+
+	PermReadWrite = PermRead | PermWrite // 读写，Topic 默认权限
+)
+
+// Readable 返回是否可读
+func (p Perm) Readable() bool {
+	return p&PermRead != 0
+}
+
+// Writable 返回是否可写
+func (p Perm) Writable() bool {
+	return p&PermWrite != 0
+}
+
+// Inherited 返回是否从默认 Topic 继承
+func (p Perm) Inherited() bool {
+	return p&PermInherit != 0
+}
+
+// String 返回与 Java PermName.perm2String 相同的格式，如 "RW-"、"R--"、"RWX"
+func (p Perm) String() string {
+	s := []byte("---")
+	if p.Readable() {
+		s[0] = 'R'
+	}
+	if p.Writable() {
+		s[1] = 'W'
+	}
+	if p.Inherited() {
+		s[2] = 'X'
+	}
+	return string(s)
+}
+
+// ParsePerm 解析权限，支持数字（如 6）与 String 的格式（如 "RW-"、"R-"，大小写不敏感）
+func ParsePerm(s string) (Perm, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 || Perm(n) > PermPriority|PermReadWrite|PermInherit {
+			return 0, fmt.Errorf("无效的权限: %s", s)
+		}
+		return Perm(n), nil
+	}
+	if s == "" || len(s) > 3 {
+		return 0, fmt.Errorf("无效的权限: %q", s)
+	}
+	var p Perm
+	for i, ch := range []byte(s) {
+		switch {
+		case ch == '-':
+		case i == 0 && (ch == 'R' || ch == 'r'):
+			p |= PermRead
+		case i == 1 && (ch == 'W' || ch == 'w'):
+			p |= PermWrite
+		case i == 2 && (ch == 'X' || ch == 'x'):
+			p |= PermInherit
+		default:
+			return 0, fmt.Errorf("无效的权限: %q", s)
+		}
+	}
+	return p, nil
+}
+
+// UpdateTopicPerm 只修改 Topic 在指定 Broker 上的权限（对应 Java updateTopicPerm）
+//
+// 用于对单个 Topic 摘除部分 Broker，例如将 broker-a 改为只读后等待堆积消费完。
+// 各 Master 通过 PatchTopicConfig 读取-修改-写入，Topic 的其他配置与未指定的 Broker 保持不变；
+// 失败的 Broker 汇总为 BrokerErrors。全部成功后等待 NameServer 路由中这些 Broker 的权限生效，
+// Broker 的 brokerPermission 不是读写时路由中的权限为两者的交集。
+func (c *Client) UpdateTopicPerm(ctx context.Context, topic string, brokerNames []string, perm Perm) ([]BrokerPatchResult, error) {
+	masters, err := c.brokerMasters(ctx, brokerNames)
+	if err != nil {
+		return nil, err
+	}
+	results, err := patchOnBrokers(ctx, masters, func(ctx context.Context, addr string) ([]FieldChange, error) {
+		return c.PatchTopicConfig(ctx, addr, topic, TopicConfigPatch{Perm: &perm})
+	})
+	if err != nil {
+		return results, err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, topicRouteWaitTimeout)
+	defer cancel()
+	brokerPerms := c.brokerPermissions(waitCtx, masters)
+	err = c.waitTopicRoute(waitCtx, topic, masters, func(qd *QueueData) bool {
+		return qd.Perm == routePerm(perm, brokerPerms[qd.BrokerName])
+	})
+	return results, err
+}
+
+// routePerm 返回 Broker 向 NameServer 注册的 Topic 权限（对应 Java BrokerController.registerBrokerAll）
+// Broker 被设置为只读或只写时，注册的 Topic 权限与 brokerPermission 取交集
+func routePerm(perm, brokerPerm Perm) Perm {
+	if brokerPerm.Readable() && brokerPerm.Writable() {
+		return perm
+	}
+	return perm & brokerPerm
+}
+
+// brokerPermissions 读取各 Master 的 brokerPermission 配置
+// 读取失败或未配置时视为可读写，即按 Topic 权限原样比较路由
+func (c *Client) brokerPermissions(ctx context.Context, masters map[string]string) map[string]Perm {
+	perms := make(map[string]Perm, len(masters))
+	for name, addr := range masters {
+		perms[name] = PermReadWrite
+		config, err := c.GetBrokerConfig(ctx, addr)
+		if err != nil {
+			continue
+		}
+		if perm, err := ParsePerm(config["brokerPermission"]); err == nil {
+			perms[name] = perm
+		}
+	}
+	return perms
+}
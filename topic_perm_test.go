@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// Topic 权限单元测试
+// =============================================================================
+
+// TestPerm 测试权限位判断、格式化与解析
+func TestPerm(t *testing.T) {
+	cases := map[Perm]string{PermReadWrite: "RW-", PermRead: "R--", PermWrite: "-W-", 0: "---", PermReadWrite | PermInherit: "RWX"}
+	for perm, want := range cases {
+		if got := perm.String(); got != want {
+			t.Errorf("Perm(%d).String() = %s, want %s", int(perm), got, want)
+		}
+		if parsed, err := ParsePerm(want); err != nil || parsed != perm {
+			t.Errorf("ParsePerm(%q) = %d, %v", want, parsed, err)
+		}
+	}
+	if !PermReadWrite.Readable() || !PermReadWrite.Writable() || PermRead.Writable() || PermReadWrite.Inherited() {
+		t.Error("权限位判断错误")
+	}
+	if p, err := ParsePerm("rw"); err != nil || p != PermReadWrite {
+		t.Errorf("应支持小写与省略末尾: %d %v", p, err)
+	}
+	if p, err := ParsePerm("4"); err != nil || p != PermRead {
+		t.Errorf("应支持数字: %d %v", p, err)
+	}
+	for _, s := range []string{"", "WR", "RW-X", "99", "abc"} {
+		if _, err := ParsePerm(s); err == nil {
+			t.Errorf("ParsePerm(%q) 应返回错误", s)
+		}
+	}
+}
+
+// TestUpdateTopicPerm 测试只修改指定 Broker 上的 Topic 权限并等待路由生效
+func TestUpdateTopicPerm(t *testing.T) {
+	srvA, srvB := remotingtest.NewServer(), remotingtest.NewServer()
+	t.Cleanup(srvA.Close)
+	t.Cleanup(srvB.Close)
+	var (
+		permA, permB atomic.Int32
+		brokerPermA  atomic.Int32 // broker-a 的 brokerPermission
+		routeStale   atomic.Bool  // 为 true 时 NameServer 路由不反映 broker-a 的修改
+	)
+	permA.Store(int32(PermReadWrite))
+	permB.Store(int32(PermReadWrite))
+	brokerPermA.Store(int32(PermReadWrite))
+
+	srvA.Handle(remoting.GetBrokerClusterInfo, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"brokerAddrTable":{` +
+			`"broker-a":{"cluster":"DefaultCluster","brokerName":"broker-a","brokerAddrs":{0:"` + srvA.Addr + `"}},` +
+			`"broker-b":{"cluster":"DefaultCluster","brokerName":"broker-b","brokerAddrs":{0:"` + srvB.Addr + `"}}},` +
+			`"clusterAddrTable":{"DefaultCluster":["broker-a","broker-b"]}}`))
+	})
+	srvA.Handle(remoting.GetRouteInfoByTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		routePermA := int32(routePerm(Perm(permA.Load()), Perm(brokerPermA.Load())))
+		if routeStale.Load() {
+			routePermA = int32(PermReadWrite)
+		}
+		return remotingtest.JSON(map[string]any{"queueDatas": []map[string]any{
+			{"brokerName": "broker-a", "readQueueNums": 4, "writeQueueNums": 4, "perm": routePermA},
+			{"brokerName": "broker-b", "readQueueNums": 4, "writeQueueNums": 4, "perm": permB.Load()},
+		}})
+	})
+	for srv, perm := range map[*remotingtest.Server]*atomic.Int32{srvA: &permA, srvB: &permB} {
+		srv.Handle(remoting.GetAllTopicConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+			return remotingtest.JSON(map[string]any{"topicConfigTable": map[string]any{
+				"TopicX": map[string]any{"topicName": "TopicX", "readQueueNums": 4, "writeQueueNums": 4, "perm": perm.Load(), "order": true},
+			}})
+		})
+		srv.Handle(remoting.UpdateAndCreateTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+			perm.Store(int32(atoi(req.ExtFields["perm"])))
+			return remotingtest.Success(nil)
+		})
+	}
+	srvA.Handle(remoting.GetBrokerConfig, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success(fmt.Appendf(nil, "brokerName=broker-a\nbrokerPermission=%d\n", brokerPermA.Load()))
+	})
+	client := newFakeClient(t, srvA)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	results, err := client.UpdateTopicPerm(ctx, "TopicX", []string{"broker-a"}, PermRead)
+	if err != nil {
+		t.Fatalf("修改 Topic 权限失败: %v", err)
+	}
+	if len(results) != 1 || len(results[0].Changes) != 1 || results[0].Changes[0] != (FieldChange{Field: "perm", Old: "RW-", New: "R--"}) {
+		t.Errorf("修改结果错误: %+v", results)
+	}
+	if w := srvA.Requests(remoting.UpdateAndCreateTopic)[0].ExtFields; w["readQueueNums"] != "4" || w["order"] != "true" {
+		t.Errorf("其他配置应保持不变: %v", w)
+	}
+	if n := len(srvB.Requests(remoting.UpdateAndCreateTopic)); n != 0 || Perm(permB.Load()) != PermReadWrite {
+		t.Errorf("未指定的 broker-b 不应修改, got %d 次写入", n)
+	}
+
+	// 路由未反映修改时等待超时
+	defer func(timeout, interval time.Duration) {
+		topicRouteWaitTimeout, topicRouteCheckInterval = timeout, interval
+	}(topicRouteWaitTimeout, topicRouteCheckInterval)
+	topicRouteWaitTimeout, topicRouteCheckInterval = 100*time.Millisecond, 10*time.Millisecond
+
+	// broker-a 只读时路由中的权限被 brokerPermission 屏蔽写权限
+	brokerPermA.Store(int32(PermRead))
+	if _, err := client.UpdateTopicPerm(ctx, "TopicX", []string{"broker-a"}, PermReadWrite); err != nil {
+		t.Errorf("应按 brokerPermission 屏蔽后的权限等待路由: %v", err)
+	}
+	brokerPermA.Store(int32(PermReadWrite))
+
+	routeStale.Store(true)
+	if _, err := client.UpdateTopicPerm(ctx, "TopicX", []string{"broker-a"}, PermWrite); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("路由未生效应超时, got %v", err)
+	}
+	if _, err := client.UpdateTopicPerm(ctx, "TopicX", []string{"broker-c"}, PermRead); !errors.Is(err, ErrBrokerNotFound) {
+		t.Errorf("不存在的 Broker 应返回 ErrBrokerNotFound, got %v", err)
+	}
+}
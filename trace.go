@@ -0,0 +1,605 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// 消息轨迹
+// =============================================================================
+
+// DefaultTraceTopic 默认轨迹 Topic
+const DefaultTraceTopic = "RMQ_SYS_TRACE_TOPIC"
+
+// 轨迹数据分隔符（对应 Java TraceConstants）
+const (
+	traceContentSplitor = "\x01"
+	traceFieldSplitor   = "\x02"
+)
+
+// traceQueryMaxNum 查询轨迹消息的最大条数
+const traceQueryMaxNum = 64
+
+// TraceType 轨迹类型
+type TraceType string
+
+// 轨迹类型定义
+const (
+	TraceTypePub            TraceType = "Pub"            // 发送
+	TraceTypeSubBefore      TraceType = "SubBefore"      // 消费前
+	TraceTypeSubAfter       TraceType = "SubAfter"       // 消费后
+	TraceTypeEndTransaction TraceType = "EndTransaction" // 事务结束
+	TraceTypeRecall         TraceType = "Recall"         // 撤回
+)
+
+// TraceContext 轨迹上下文（对应 Java TraceContext）
+type TraceContext struct {
+	TraceType   TraceType    `json:"traceType"`   // 轨迹类型
+	TimeStamp   int64        `json:"timeStamp"`   // 时间戳
+	RegionId    string       `json:"regionId"`    // 区域 ID
+	GroupName   string       `json:"groupName"`   // 生产者组或消费者组
+	CostTime    int          `json:"costTime"`    // 耗时（毫秒）
+	Success     bool         `json:"success"`     // 是否成功
+	RequestId   string       `json:"requestId"`   // 消费请求 ID
+	ContextCode int          `json:"contextCode"` // 消费结果码
+	TraceBeans  []*TraceBean `json:"traceBeans"`  // 轨迹数据
+}
+
+// TraceBean 轨迹数据（对应 Java TraceBean）
+type TraceBean struct {
+	Topic                string `json:"topic"`                // Topic
+	MsgId                string `json:"msgId"`                // 消息 ID
+	OffsetMsgId          string `json:"offsetMsgId"`          // 偏移消息 ID
+	Tags                 string `json:"tags"`                 // Tag
+	Keys                 string `json:"keys"`                 // Key
+	StoreHost            string `json:"storeHost"`            // 存储地址
+	ClientHost           string `json:"clientHost"`           // 客户端地址
+	BodyLength           int    `json:"bodyLength"`           // 消息体长度
+	MsgType              int    `json:"msgType"`              // 消息类型
+	RetryTimes           int    `json:"retryTimes"`           // 重试次数
+	TransactionId        string `json:"transactionId"`        // 事务 ID
+	TransactionState     string `json:"transactionState"`     // 事务状态
+	FromTransactionCheck bool   `json:"fromTransactionCheck"` // 是否来自事务回查
+}
+
+// DecodeTraceData 解码轨迹消息体（对应 Java TraceDataEncoder.decoderFromTraceDataString）
+func DecodeTraceData(data string) ([]*TraceContext, error) {
+	var contexts []*TraceContext
+	for _, record := range strings.Split(data, traceFieldSplitor) {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+
+		line := strings.Split(record, traceContentSplitor)
+		traceCtx, err := decodeTraceRecord(line)
+		if err != nil {
+			return nil, err
+		}
+		if traceCtx != nil {
+			contexts = append(contexts, traceCtx)
+		}
+	}
+	return contexts, nil
+}
+
+// decodeTraceRecord 解码单条轨迹记录，未知类型返回 nil
+func decodeTraceRecord(line []string) (*TraceContext, error) {
+	p := &traceFieldParser{line: line}
+	traceCtx := &TraceContext{TraceType: TraceType(line[0])}
+	bean := &TraceBean{}
+
+	switch traceCtx.TraceType {
+	case TraceTypePub:
+		p.require(12)
+		traceCtx.TimeStamp = p.int64(1)
+		traceCtx.RegionId = p.str(2)
+		traceCtx.GroupName = p.str(3)
+		bean.Topic = p.str(4)
+		bean.MsgId = p.str(5)
+		bean.Tags = p.str(6)
+		bean.Keys = p.str(7)
+		bean.StoreHost = p.str(8)
+		bean.BodyLength = p.int(9)
+		traceCtx.CostTime = p.int(10)
+		bean.MsgType = p.int(11)
+		traceCtx.Success = true
+		switch {
+		case len(line) == 13:
+			traceCtx.Success = p.bool(12)
+		case len(line) >= 14:
+			bean.OffsetMsgId = p.str(12)
+			traceCtx.Success = p.bool(13)
+		}
+		if len(line) >= 15 {
+			bean.ClientHost = p.str(14)
+		}
+
+	case TraceTypeSubBefore:
+		p.require(8)
+		traceCtx.TimeStamp = p.int64(1)
+		traceCtx.RegionId = p.str(2)
+		traceCtx.GroupName = p.str(3)
+		traceCtx.RequestId = p.str(4)
+		bean.MsgId = p.str(5)
+		bean.RetryTimes = p.int(6)
+		bean.Keys = p.str(7)
+		if len(line) >= 9 {
+			bean.ClientHost = p.str(8)
+		}
+
+	case TraceTypeSubAfter:
+		p.require(6)
+		traceCtx.RequestId = p.str(1)
+		bean.MsgId = p.str(2)
+		traceCtx.CostTime = p.int(3)
+		traceCtx.Success = p.bool(4)
+		bean.Keys = p.str(5)
+		if len(line) >= 7 {
+			traceCtx.ContextCode = p.int(6)
+		}
+		if len(line) >= 9 {
+			traceCtx.TimeStamp = p.int64(7)
+			traceCtx.GroupName = p.str(8)
+		}
+
+	case TraceTypeEndTransaction:
+		p.require(13)
+		traceCtx.TimeStamp = p.int64(1)
+		traceCtx.RegionId = p.str(2)
+		traceCtx.GroupName = p.str(3)
+		bean.Topic = p.str(4)
+		bean.MsgId = p.str(5)
+		bean.Tags = p.str(6)
+		bean.Keys = p.str(7)
+		bean.StoreHost = p.str(8)
+		bean.MsgType = p.int(9)
+		bean.TransactionId = p.str(10)
+		bean.TransactionState = p.str(11)
+		bean.FromTransactionCheck = p.bool(12)
+		traceCtx.Success = true
+
+	case TraceTypeRecall:
+		p.require(7)
+		traceCtx.TimeStamp = p.int64(1)
+		traceCtx.RegionId = p.str(2)
+		traceCtx.GroupName = p.str(3)
+		bean.Topic = p.str(4)
+		bean.MsgId = p.str(5)
+		traceCtx.Success = p.bool(6)
+
+	default:
+		return nil, nil
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	traceCtx.TraceBeans = []*TraceBean{bean}
+	return traceCtx, nil
+}
+
+// traceFieldParser 轨迹字段解析器，记录第一个解析错误
+type traceFieldParser struct {
+	line []string
+	err  error
+}
+
+func (p *traceFieldParser) require(n int) {
+	if p.err == nil && len(p.line) < n {
+		p.err = fmt.Errorf("%w: %s 轨迹字段数不足 %d", ErrInvalidMessageData, p.line[0], n)
+	}
+}
+
+func (p *traceFieldParser) str(i int) string {
+	if p.err != nil || i >= len(p.line) {
+		return ""
+	}
+	return p.line[i]
+}
+
+func (p *traceFieldParser) int64(i int) int64 {
+	s := p.str(i)
+	if s == "" || p.err != nil {
+		return 0
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		p.err = fmt.Errorf("%w: %s 轨迹字段 %d 非法: %s", ErrInvalidMessageData, p.line[0], i, s)
+	}
+	return v
+}
+
+func (p *traceFieldParser) int(i int) int {
+	return int(p.int64(i))
+}
+
+func (p *traceFieldParser) bool(i int) bool {
+	return strings.EqualFold(p.str(i), "true")
+}
+
+// QueryMessageTrace 按消息 ID 查询轨迹记录
+// traceTopic 为空时使用默认轨迹 Topic
+func (c *Client) QueryMessageTrace(ctx context.Context, traceTopic, msgId string) ([]*TraceContext, error) {
+	if traceTopic == "" {
+		traceTopic = DefaultTraceTopic
+	}
+
+	// 轨迹消息以消息 ID 作为 Key 建立索引
+	msgs, err := c.QueryMessage(ctx, traceTopic, msgId, traceQueryMaxNum, 0, time.Now().UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*TraceContext
+	for _, msg := range msgs {
+		contexts, err := DecodeTraceData(string(msg.Body))
+		if err != nil {
+			continue
+		}
+		for _, traceCtx := range contexts {
+			for _, bean := range traceCtx.TraceBeans {
+				if bean.MsgId == msgId {
+					result = append(result, traceCtx)
+					break
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// =============================================================================
+// 消息消费轨迹（对应 Java messageTrackDetail）
+// =============================================================================
+
+// 消息消费轨迹类型（对应 Java TrackType）
+const (
+	TrackTypeConsumed            = "CONSUMED"              // 已消费
+	TrackTypeConsumedButFiltered = "CONSUMED_BUT_FILTERED" // 已被过滤
+	TrackTypePull                = "PULL"                  // Pull 模式，无法判断
+	TrackTypeNotConsumeYet       = "NOT_CONSUME_YET"       // 尚未消费
+	TrackTypeNotOnline           = "NOT_ONLINE"            // 消费者不在线
+	TrackTypeConsumeBroadcasting = "CONSUME_BROADCASTING"  // 广播模式，无法判断
+	TrackTypeUnknown             = "UNKNOWN"               // 未知
+)
+
+// messageTrackConcurrency MessageTrackDetailConcurrent 同时查询的消费组数量
+const messageTrackConcurrency = 8
+
+// MessageTrackDetail 查询消息被各消费组的消费情况
+// msg 需要包含 Topic、BrokerName、QueueId、QueueOffset 和 Tag 信息，通常来自 QueryMessage
+func (c *Client) MessageTrackDetail(ctx context.Context, msg *MessageExt) ([]MessageTrack, error) {
+	groups, err := c.QueryTopicConsumeByWho(ctx, msg.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]MessageTrack, 0, len(groups))
+	for _, group := range groups {
+		tracks = append(tracks, c.messageTrack(ctx, msg, group))
+	}
+
+	return tracks, nil
+}
+
+// MessageTrackDetailConcurrent 并发查询消息消费轨迹，结果与 MessageTrackDetail 相同
+// 同时最多查询 messageTrackConcurrency 个消费组，适用于订阅消费组较多的 Topic
+func (c *Client) MessageTrackDetailConcurrent(ctx context.Context, msg *MessageExt) ([]MessageTrack, error) {
+	groups, err := c.QueryTopicConsumeByWho(ctx, msg.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]MessageTrack, len(groups))
+	sem := make(chan struct{}, messageTrackConcurrency)
+	var wg sync.WaitGroup
+	for i, group := range groups {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			tracks[i] = c.messageTrack(ctx, msg, group)
+		}()
+	}
+	wg.Wait()
+
+	return tracks, nil
+}
+
+// messageTrack 查询单个消费组对消息的消费情况，查询失败记录在 ExceptionDesc 中
+func (c *Client) messageTrack(ctx context.Context, msg *MessageExt, group string) MessageTrack {
+	track := MessageTrack{
+		ConsumerGroup: group,
+		TrackType:     TrackTypeUnknown,
+	}
+
+	connInfo, err := c.ExamineConsumerConnectionInfo(ctx, group)
+	if err != nil {
+		if errors.Is(err, ErrConsumerGroupNotFound) {
+			track.TrackType = TrackTypeNotOnline
+		}
+		track.ExceptionDesc = err.Error()
+		return track
+	}
+
+	switch {
+	case connInfo.MessageModel == "BROADCASTING":
+		track.TrackType = TrackTypeConsumeBroadcasting
+	case connInfo.ConsumeType == "CONSUME_ACTIVELY":
+		track.TrackType = TrackTypePull
+	default:
+		consumed, err := c.isMessageConsumed(ctx, msg, group)
+		if err != nil {
+			track.ExceptionDesc = err.Error()
+			break
+		}
+
+		if !consumed {
+			track.TrackType = TrackTypeNotConsumeYet
+			break
+		}
+
+		track.TrackType = TrackTypeConsumed
+		if sub, ok := connInfo.SubscriptionTable[msg.Topic]; ok && !subscriptionMatchesTag(sub, msg.GetTags()) {
+			track.TrackType = TrackTypeConsumedButFiltered
+		}
+	}
+
+	track.ConsumedStatus = track.TrackType == TrackTypeConsumed
+	return track
+}
+
+// isMessageConsumed 根据消费进度判断消息是否已被消费组消费
+func (c *Client) isMessageConsumed(ctx context.Context, msg *MessageExt, group string) (bool, error) {
+	stats, err := c.ExamineConsumeStats(ctx, group)
+	if err != nil {
+		return false, err
+	}
+
+	for key, wrapper := range stats.OffsetTable {
+		mq, err := ParseMessageQueueKey(key)
+		if err != nil {
+			continue
+		}
+		if mq.Topic == msg.Topic && mq.BrokerName == msg.BrokerName && mq.QueueId == msg.QueueId {
+			return wrapper.ConsumerOffset > msg.QueueOffset, nil
+		}
+	}
+
+	return false, nil
+}
+
+// subscriptionMatchesTag 判断订阅表达式是否匹配消息 Tag
+func subscriptionMatchesTag(sub *SubscriptionData, tag string) bool {
+	if sub.ExpressionType != "" && sub.ExpressionType != "TAG" {
+		return true
+	}
+	if sub.SubString == "" || sub.SubString == "*" || len(sub.TagsSet) == 0 {
+		return true
+	}
+	for _, t := range sub.TagsSet {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// 消息轨迹详情
+// =============================================================================
+
+// TraceConsumeStatus 消费组对消息的消费状态
+type TraceConsumeStatus string
+
+// 消费状态定义
+const (
+	TraceConsumeStatusConsumed    TraceConsumeStatus = "CONSUMED"     // 已消费
+	TraceConsumeStatusNotConsumed TraceConsumeStatus = "NOT_CONSUMED" // 未消费
+	TraceConsumeStatusFailed      TraceConsumeStatus = "FAILED"       // 消费失败
+)
+
+// MessageTraceNode 轨迹时间线节点
+type MessageTraceNode struct {
+	TraceType  TraceType `json:"traceType"`  // 轨迹类型
+	Timestamp  int64     `json:"timestamp"`  // 时间戳
+	GroupName  string    `json:"groupName"`  // 生产者组或消费者组
+	ClientHost string    `json:"clientHost"` // 客户端地址
+	StoreHost  string    `json:"storeHost"`  // 存储地址
+	CostTime   int       `json:"costTime"`   // 耗时（毫秒）
+	Success    bool      `json:"success"`    // 是否成功
+	RetryTimes int       `json:"retryTimes"` // 重试次数
+	RequestId  string    `json:"requestId"`  // 消费请求 ID
+	Detail     string    `json:"detail"`     // 附加信息（如事务状态）
+}
+
+// ConsumerGroupTrace 消费组维度的消费轨迹
+type ConsumerGroupTrace struct {
+	ConsumerGroup    string             `json:"consumerGroup"`    // 消费者组
+	Status           TraceConsumeStatus `json:"status"`           // 消费状态
+	TrackType        string             `json:"trackType"`        // 基于消费进度的轨迹类型
+	ConsumeTimes     int                `json:"consumeTimes"`     // 消费次数
+	FirstConsumeTime int64              `json:"firstConsumeTime"` // 首次消费时间
+	LastConsumeTime  int64              `json:"lastConsumeTime"`  // 最后消费时间
+	ExceptionDesc    string             `json:"exceptionDesc"`    // 异常描述
+}
+
+// MessageTraceDetail 消息轨迹详情
+type MessageTraceDetail struct {
+	Topic          string                `json:"topic"`          // Topic
+	MsgId          string                `json:"msgId"`          // 消息 ID
+	Producer       *MessageTraceNode     `json:"producer"`       // 发送节点
+	Timeline       []*MessageTraceNode   `json:"timeline"`       // 按时间排序的轨迹
+	ConsumerGroups []*ConsumerGroupTrace `json:"consumerGroups"` // 各消费组消费情况
+}
+
+// MessageTraceDetail 查询消息轨迹详情，包括时间线和各消费组的消费状态
+// 消费状态优先取自轨迹记录，没有轨迹的消费组根据消费进度判断。
+// 消息已过期（ErrMessageNotFound）时只返回轨迹记录；查询消息或消费进度失败时
+// 同时返回已构建的轨迹详情和错误
+func (c *Client) MessageTraceDetail(ctx context.Context, topic, msgId, traceTopic string) (*MessageTraceDetail, error) {
+	contexts, err := c.QueryMessageTrace(ctx, traceTopic, msgId)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := buildMessageTraceDetail(topic, msgId, contexts)
+
+	// 补充没有轨迹记录的消费组
+	msgs, err := c.QueryMessageByUniqKey(ctx, topic, msgId, 0, 0)
+	if errors.Is(err, ErrMessageNotFound) {
+		return detail, nil
+	}
+	if err != nil {
+		return detail, fmt.Errorf("查询消息失败: %w", err)
+	}
+
+	tracks, err := c.MessageTrackDetail(ctx, msgs[0])
+	if err != nil {
+		return detail, fmt.Errorf("查询消息消费轨迹失败: %w", err)
+	}
+
+	byGroup := make(map[string]*ConsumerGroupTrace, len(detail.ConsumerGroups))
+	for _, g := range detail.ConsumerGroups {
+		byGroup[g.ConsumerGroup] = g
+	}
+	for _, track := range tracks {
+		if g, ok := byGroup[track.ConsumerGroup]; ok {
+			g.TrackType = track.TrackType
+			continue
+		}
+
+		g := &ConsumerGroupTrace{
+			ConsumerGroup: track.ConsumerGroup,
+			Status:        TraceConsumeStatusNotConsumed,
+			TrackType:     track.TrackType,
+			ExceptionDesc: track.ExceptionDesc,
+		}
+		if track.TrackType == TrackTypeConsumed || track.TrackType == TrackTypeConsumedButFiltered {
+			g.Status = TraceConsumeStatusConsumed
+		}
+		detail.ConsumerGroups = append(detail.ConsumerGroups, g)
+	}
+
+	sort.Slice(detail.ConsumerGroups, func(i, j int) bool {
+		return detail.ConsumerGroups[i].ConsumerGroup < detail.ConsumerGroups[j].ConsumerGroup
+	})
+
+	return detail, nil
+}
+
+// buildMessageTraceDetail 根据轨迹记录构建时间线和消费组状态
+func buildMessageTraceDetail(topic, msgId string, contexts []*TraceContext) *MessageTraceDetail {
+	detail := &MessageTraceDetail{
+		Topic: topic,
+		MsgId: msgId,
+	}
+
+	// SubAfter 在旧版本中不携带时间戳和消费组，需要通过 requestId 关联 SubBefore
+	subBefore := make(map[string]*TraceContext)
+	for _, traceCtx := range contexts {
+		if traceCtx.TraceType == TraceTypeSubBefore && traceCtx.RequestId != "" {
+			subBefore[traceCtx.RequestId] = traceCtx
+		}
+	}
+
+	groups := make(map[string]*ConsumerGroupTrace)
+	groupOf := func(name string) *ConsumerGroupTrace {
+		g, ok := groups[name]
+		if !ok {
+			g = &ConsumerGroupTrace{ConsumerGroup: name, Status: TraceConsumeStatusNotConsumed}
+			groups[name] = g
+		}
+		return g
+	}
+
+	for _, traceCtx := range contexts {
+		bean := traceCtx.TraceBeans[0]
+		node := &MessageTraceNode{
+			TraceType:  traceCtx.TraceType,
+			Timestamp:  traceCtx.TimeStamp,
+			GroupName:  traceCtx.GroupName,
+			ClientHost: bean.ClientHost,
+			StoreHost:  bean.StoreHost,
+			CostTime:   traceCtx.CostTime,
+			Success:    traceCtx.Success,
+			RetryTimes: bean.RetryTimes,
+			RequestId:  traceCtx.RequestId,
+		}
+
+		switch traceCtx.TraceType {
+		case TraceTypePub:
+			if detail.Topic == "" {
+				detail.Topic = bean.Topic
+			}
+			detail.Producer = node
+
+		case TraceTypeSubBefore:
+			g := groupOf(traceCtx.GroupName)
+			g.ConsumeTimes++
+			if g.FirstConsumeTime == 0 || node.Timestamp < g.FirstConsumeTime {
+				g.FirstConsumeTime = node.Timestamp
+			}
+			if node.Timestamp > g.LastConsumeTime {
+				g.LastConsumeTime = node.Timestamp
+			}
+
+		case TraceTypeSubAfter:
+			if before, ok := subBefore[traceCtx.RequestId]; ok {
+				if node.GroupName == "" {
+					node.GroupName = before.GroupName
+				}
+				if node.Timestamp == 0 {
+					node.Timestamp = before.TimeStamp + int64(traceCtx.CostTime)
+				}
+				node.RetryTimes = before.TraceBeans[0].RetryTimes
+				node.ClientHost = before.TraceBeans[0].ClientHost
+			}
+			if node.GroupName == "" {
+				break
+			}
+			g := groupOf(node.GroupName)
+			switch {
+			case traceCtx.Success:
+				g.Status = TraceConsumeStatusConsumed
+				g.ExceptionDesc = ""
+			case g.Status != TraceConsumeStatusConsumed:
+				g.Status = TraceConsumeStatusFailed
+				g.ExceptionDesc = fmt.Sprintf("消费失败，结果码: %d", traceCtx.ContextCode)
+			}
+
+		case TraceTypeEndTransaction:
+			node.Detail = bean.TransactionState
+
+		case TraceTypeRecall:
+			if !traceCtx.Success {
+				node.Detail = "撤回失败"
+			}
+		}
+
+		detail.Timeline = append(detail.Timeline, node)
+	}
+
+	sort.SliceStable(detail.Timeline, func(i, j int) bool {
+		return detail.Timeline[i].Timestamp < detail.Timeline[j].Timestamp
+	})
+
+	for _, g := range groups {
+		detail.ConsumerGroups = append(detail.ConsumerGroups, g)
+	}
+	sort.Slice(detail.ConsumerGroups, func(i, j int) bool {
+		return detail.ConsumerGroups[i].ConsumerGroup < detail.ConsumerGroups[j].ConsumerGroup
+	})
+
+	return detail
+}
@@ -0,0 +1,257 @@
+package admin
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting"
+	"github.com/codermast/rocketmq-admin-go/protocol/remoting/remotingtest"
+)
+
+// =============================================================================
+// 消息轨迹单元测试
+// =============================================================================
+
+// joinTraceRecord 按轨迹格式拼接一条记录
+func joinTraceRecord(fields ...string) string {
+	return strings.Join(fields, traceContentSplitor) + traceFieldSplitor
+}
+
+// TestDecodeTraceData 测试解码各类型轨迹记录
+func TestDecodeTraceData(t *testing.T) {
+	data := joinTraceRecord("Pub", "1700000000000", "DefaultRegion", "PG", "TopicTest", "MSG1", "TagA", "k1", "10.0.0.1:10911", "5", "3", "0", "OFFSET1", "true", "10.0.0.9") +
+		joinTraceRecord("SubBefore", "1700000001000", "DefaultRegion", "CG", "REQ1", "MSG1", "0", "k1", "10.0.0.8") +
+		joinTraceRecord("SubAfter", "REQ1", "MSG1", "20", "false", "k1", "1") +
+		joinTraceRecord("EndTransaction", "1700000000500", "DefaultRegion", "PG", "TopicTest", "MSG1", "TagA", "k1", "10.0.0.1:10911", "2", "TX1", "COMMIT_MESSAGE", "false") +
+		joinTraceRecord("Recall", "1700000002000", "DefaultRegion", "PG", "TopicTest", "MSG1", "true")
+
+	contexts, err := DecodeTraceData(data)
+	if err != nil {
+		t.Fatalf("解码轨迹失败: %v", err)
+	}
+	if len(contexts) != 5 {
+		t.Fatalf("轨迹数量应为 5, got %d", len(contexts))
+	}
+
+	pub := contexts[0]
+	if pub.TraceType != TraceTypePub || pub.GroupName != "PG" || !pub.Success || pub.CostTime != 3 {
+		t.Errorf("Pub 轨迹解析错误: %+v", pub)
+	}
+	if bean := pub.TraceBeans[0]; bean.OffsetMsgId != "OFFSET1" || bean.ClientHost != "10.0.0.9" || bean.BodyLength != 5 {
+		t.Errorf("Pub 轨迹数据解析错误: %+v", bean)
+	}
+
+	subAfter := contexts[2]
+	if subAfter.TraceType != TraceTypeSubAfter || subAfter.Success || subAfter.RequestId != "REQ1" || subAfter.ContextCode != 1 {
+		t.Errorf("SubAfter 轨迹解析错误: %+v", subAfter)
+	}
+
+	endTx := contexts[3]
+	if endTx.TraceBeans[0].TransactionState != "COMMIT_MESSAGE" || endTx.TraceBeans[0].TransactionId != "TX1" {
+		t.Errorf("EndTransaction 轨迹解析错误: %+v", endTx.TraceBeans[0])
+	}
+
+	// 12 个字段的旧版本默认成功，13 个字段时第 13 个字段为是否成功
+	pubFields := []string{"Pub", "1700000000000", "DefaultRegion", "PG", "TopicTest", "MSG1", "TagA", "k1", "10.0.0.1:10911", "5", "3", "0"}
+	for _, c := range []struct {
+		fields  []string
+		success bool
+	}{
+		{pubFields, true},
+		{append(pubFields[:12:12], "false"), false},
+		{append(pubFields[:12:12], "true"), true},
+	} {
+		contexts, err := DecodeTraceData(joinTraceRecord(c.fields...))
+		if err != nil || len(contexts) != 1 {
+			t.Fatalf("解码 %d 个字段的 Pub 轨迹失败: %v", len(c.fields), err)
+		}
+		if contexts[0].Success != c.success || contexts[0].TraceBeans[0].OffsetMsgId != "" {
+			t.Errorf("%d 个字段的 Pub 轨迹解析错误: %+v %+v", len(c.fields), contexts[0], contexts[0].TraceBeans[0])
+		}
+	}
+
+	if _, err := DecodeTraceData(joinTraceRecord("Pub", "bad")); err == nil {
+		t.Error("字段不足的轨迹应返回错误")
+	}
+}
+
+// TestBuildMessageTraceDetail 测试构建轨迹时间线和消费组状态
+func TestBuildMessageTraceDetail(t *testing.T) {
+	data := joinTraceRecord("Pub", "1000", "R", "PG", "TopicTest", "MSG1", "", "", "10.0.0.1:10911", "5", "3", "0", "OFFSET1", "true") +
+		joinTraceRecord("SubBefore", "2000", "R", "CG_OK", "REQ1", "MSG1", "0", "") +
+		joinTraceRecord("SubAfter", "REQ1", "MSG1", "20", "false", "", "1") +
+		joinTraceRecord("SubBefore", "3000", "R", "CG_OK", "REQ2", "MSG1", "1", "") +
+		joinTraceRecord("SubAfter", "REQ2", "MSG1", "10", "true", "", "0", "3010", "CG_OK") +
+		joinTraceRecord("SubBefore", "2500", "R", "CG_FAIL", "REQ3", "MSG1", "0", "") +
+		joinTraceRecord("SubAfter", "REQ3", "MSG1", "10", "false", "", "1")
+
+	contexts, err := DecodeTraceData(data)
+	if err != nil {
+		t.Fatalf("解码轨迹失败: %v", err)
+	}
+
+	detail := buildMessageTraceDetail("TopicTest", "MSG1", contexts)
+	if detail.Producer == nil || detail.Producer.GroupName != "PG" {
+		t.Fatalf("缺少发送节点: %+v", detail.Producer)
+	}
+
+	for i := 1; i < len(detail.Timeline); i++ {
+		if detail.Timeline[i].Timestamp < detail.Timeline[i-1].Timestamp {
+			t.Fatalf("时间线未按时间排序")
+		}
+	}
+
+	if len(detail.ConsumerGroups) != 2 {
+		t.Fatalf("消费组数量应为 2, got %d", len(detail.ConsumerGroups))
+	}
+	failGroup, okGroup := detail.ConsumerGroups[0], detail.ConsumerGroups[1]
+	if failGroup.ConsumerGroup != "CG_FAIL" || failGroup.Status != TraceConsumeStatusFailed {
+		t.Errorf("CG_FAIL 状态应为 FAILED: %+v", failGroup)
+	}
+	if okGroup.ConsumerGroup != "CG_OK" || okGroup.Status != TraceConsumeStatusConsumed || okGroup.ConsumeTimes != 2 {
+		t.Errorf("CG_OK 状态应为 CONSUMED 且消费 2 次: %+v", okGroup)
+	}
+}
+
+// newTraceCluster 启动模拟集群：消息被 CG1 消费并有轨迹，CG2 未消费，CG3 不在线
+func newTraceCluster(t *testing.T) (*remotingtest.Server, string) {
+	t.Helper()
+	srv := remotingtest.NewServer()
+	t.Cleanup(srv.Close)
+	handleClusterInfo(srv, "broker-a")
+	const msgId = "7F00000100002A9F0000000000000001"
+
+	srv.Handle(remoting.GetRouteInfoByTopic, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Success([]byte(`{"queueDatas":[],"brokerDatas":[` +
+			`{"cluster":"DefaultCluster","brokerName":"broker-a","brokerAddrs":{0:"` + srv.Addr + `"}}]}`))
+	})
+	storeIP := net.ParseIP("10.0.0.1")
+	srv.Handle(remoting.QueryMessage, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		if req.ExtFields["topic"] == DefaultTraceTopic {
+			// 同一轨迹消息中包含其他消息的轨迹
+			body := joinTraceRecord("Pub", "1000", "R", "PG", "TopicA", msgId, "TagA", "", "10.0.0.1:10911", "5", "3", "0", "OFFSET1", "true") +
+				joinTraceRecord("SubBefore", "2000", "R", "CG1", "REQ1", msgId, "0", "") +
+				joinTraceRecord("SubAfter", "REQ1", msgId, "20", "true", "", "0") +
+				joinTraceRecord("SubBefore", "2000", "R", "CG1", "REQ2", "OTHER", "0", "")
+			return remotingtest.Success(encodeTestMessage(&MessageExt{Topic: DefaultTraceTopic, CommitLogOffset: 100, Body: []byte(body)}, storeIP, 10911))
+		}
+		return remotingtest.Success(encodeTestMessage(&MessageExt{
+			Topic:           "TopicA",
+			QueueOffset:     5,
+			CommitLogOffset: 200,
+			Properties:      map[string]string{PropertyUniqKey: msgId, PropertyTags: "TagA"},
+		}, storeIP, 10911))
+	})
+	srv.Handle(remoting.QueryTopicConsumeByWho, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.JSON(map[string]any{"groupList": []string{"CG1", "CG2", "CG3"}})
+	})
+	srv.Handle(remoting.GetConsumerConnectionList, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		if req.ExtFields["consumerGroup"] == "CG3" {
+			return remotingtest.Error(remoting.ConsumerNotOnline, "the consumer group not online")
+		}
+		return remotingtest.JSON(map[string]any{
+			"connectionSet": []map[string]any{{"clientId": "10.0.0.3@1"}},
+			"consumeType":   "CONSUME_PASSIVELY",
+			"messageModel":  "CLUSTERING",
+		})
+	})
+	srv.Handle(remoting.GetConsumeStats, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		consumerOffset := 10
+		if req.ExtFields["consumerGroup"] == "CG2" {
+			consumerOffset = 3
+		}
+		return remotingtest.JSON(map[string]any{"offsetTable": map[string]any{
+			`{"brokerName":"broker-a","queueId":0,"topic":"TopicA"}`: map[string]any{"brokerOffset": 10, "consumerOffset": consumerOffset},
+		}})
+	})
+	return srv, msgId
+}
+
+// TestMessageTraceDetail 测试查询轨迹、按消费进度补充消费组与错误返回
+func TestMessageTraceDetail(t *testing.T) {
+	srv, msgId := newTraceCluster(t)
+	client := newFakeClient(t, srv)
+	ctx, cancel := testContext()
+	defer cancel()
+
+	contexts, err := client.QueryMessageTrace(ctx, "", msgId)
+	if err != nil {
+		t.Fatalf("查询消息轨迹失败: %v", err)
+	}
+	if len(contexts) != 3 {
+		t.Errorf("应只返回该消息的 3 条轨迹, got %d", len(contexts))
+	}
+	if w := srv.Requests(remoting.QueryMessage)[0].ExtFields; w["topic"] != DefaultTraceTopic || w["key"] != msgId || w[uniqueKeyQueryFlag] != "false" {
+		t.Errorf("轨迹查询请求头错误: %v", w)
+	}
+
+	detail, err := client.MessageTraceDetail(ctx, "TopicA", msgId, "")
+	if err != nil {
+		t.Fatalf("查询消息轨迹详情失败: %v", err)
+	}
+	want := map[string][2]string{
+		"CG1": {string(TraceConsumeStatusConsumed), TrackTypeConsumed},
+		"CG2": {string(TraceConsumeStatusNotConsumed), TrackTypeNotConsumeYet},
+		"CG3": {string(TraceConsumeStatusNotConsumed), TrackTypeNotOnline},
+	}
+	if len(detail.ConsumerGroups) != len(want) || detail.Producer == nil {
+		t.Fatalf("轨迹详情错误: %+v", detail)
+	}
+	for _, g := range detail.ConsumerGroups {
+		if got := [2]string{string(g.Status), g.TrackType}; got != want[g.ConsumerGroup] {
+			t.Errorf("%s 消费状态应为 %v, got %v", g.ConsumerGroup, want[g.ConsumerGroup], got)
+		}
+	}
+
+	msgs, err := client.QueryMessageByUniqKey(ctx, "TopicA", msgId, 0, 0)
+	if err != nil {
+		t.Fatalf("查询消息失败: %v", err)
+	}
+	serial, err := client.MessageTrackDetail(ctx, msgs[0])
+	if err != nil {
+		t.Fatalf("查询消费轨迹失败: %v", err)
+	}
+	concurrent, err := client.MessageTrackDetailConcurrent(ctx, msgs[0])
+	if err != nil || !reflect.DeepEqual(serial, concurrent) {
+		t.Errorf("并发查询结果应与顺序查询一致: %v\n%+v\n%+v", err, serial, concurrent)
+	}
+
+	// 查询消费组失败时返回错误和已有的轨迹
+	srv.Handle(remoting.QueryTopicConsumeByWho, func(req *remoting.RemotingCommand) *remoting.RemotingCommand {
+		return remotingtest.Error(remoting.SystemError, "system error")
+	})
+	detail, err = client.MessageTraceDetail(ctx, "TopicA", msgId, "")
+	if !IsResponseCode(err, remoting.SystemError) || detail == nil || detail.Producer == nil {
+		t.Errorf("应返回错误和已有的轨迹: %v %+v", err, detail)
+	}
+}
+
+// TestIntegration_QueryMessageTrace 测试查询消息轨迹
+func TestIntegration_QueryMessageTrace(t *testing.T) {
+	skipIfNoRocketMQ(t)
+	client := getTestClient(t)
+	defer client.Close()
+
+	ctx, cancel := testContext()
+	defer cancel()
+
+	// 轨迹 Topic 仅在开启轨迹的集群中存在
+	if _, err := client.ExamineTopicRouteInfo(ctx, DefaultTraceTopic); err != nil {
+		t.Skipf("轨迹 Topic 不可用: %v", err)
+	}
+
+	contexts, err := client.QueryMessageTrace(ctx, "", "7F00000100002A9F0000000000000001")
+	if err != nil {
+		t.Fatalf("查询消息轨迹失败: %v", err)
+	}
+
+	t.Logf("轨迹记录数: %d", len(contexts))
+}
+
+// TestIntegration_MessageTrackDetail 测试查询消息消费轨迹
+func TestIntegration_MessageTrackDetail(t *testing.T) {
+	skipIfNoRocketMQ(t)
+	t.Skip("跳过 MessageTrackDetail 测试：需要有效的消息和在线消费者")
+}